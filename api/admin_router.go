@@ -0,0 +1,417 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/service"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+type AdminCreateAccountRequest struct {
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Profile   string `json:"profile"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+func (router *Router) adminGetAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := router.adminService.GetAccounts()
+
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, accounts)
+}
+
+func (router *Router) adminCreateAccount(w http.ResponseWriter, r *http.Request) {
+	var car AdminCreateAccountRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&car); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	account, err := router.adminService.CreateAccount(car.FirstName, car.LastName, car.Email, car.Password, car.Profile, car.Confirmed)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	router.respondCreated(w, r, fmt.Sprintf("/admin/accounts/%d", account.Id))
+}
+
+func (router *Router) adminGetAccount(w http.ResponseWriter, r *http.Request) {
+	accountId, err := pathVariable(r, "accountId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	account, err := router.adminService.GetAccount(accountId)
+
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, account)
+}
+
+func (router *Router) adminUpdateAccount(w http.ResponseWriter, r *http.Request) {
+	accountId, err := pathVariable(r, "accountId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	patchJSON, err := ioutil.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	account, err := router.adminService.UpdateAccount(accountId, patchJSON)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, account)
+}
+
+func (router *Router) adminDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	accountId, err := pathVariable(r, "accountId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = router.adminService.DeleteAccount(accountId); err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type LockAccountRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (router *Router) adminLockAccount(w http.ResponseWriter, r *http.Request) {
+	router.adminSetLocked(w, r, true)
+}
+
+func (router *Router) adminUnlockAccount(w http.ResponseWriter, r *http.Request) {
+	router.adminSetLocked(w, r, false)
+}
+
+func (router *Router) adminSetLocked(w http.ResponseWriter, r *http.Request, locked bool) {
+	accountId, err := pathVariable(r, "accountId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var lar LockAccountRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&lar); err != nil || len(lar.Reason) == 0 {
+		respondWithError(w, http.StatusBadRequest, "A reason must be provided")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+
+	if locked {
+		err = router.accountService.LockAccount(principal.AccountId, accountId, lar.Reason)
+	} else {
+		err = router.accountService.UnlockAccount(principal.AccountId, accountId, lar.Reason)
+	}
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (router *Router) adminLockEvents(w http.ResponseWriter, r *http.Request) {
+	accountId, err := pathVariable(r, "accountId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	events, err := router.accountService.GetLockEvents(accountId)
+
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, events)
+}
+
+func (router *Router) adminGetTeams(w http.ResponseWriter, r *http.Request) {
+	teams, err := router.adminService.GetTeams()
+
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, teams)
+}
+
+func (router *Router) adminCreateTeam(w http.ResponseWriter, r *http.Request) {
+	var team domain.AdminTeam
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&team); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	created, err := router.adminService.CreateTeam(team)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	router.respondCreated(w, r, fmt.Sprintf("/admin/teams/%d", created.Id))
+}
+
+func (router *Router) adminGetTeam(w http.ResponseWriter, r *http.Request) {
+	teamId, err := pathVariable(r, "teamId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	team, err := router.adminService.GetTeam(teamId)
+
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, team)
+}
+
+func (router *Router) adminUpdateTeam(w http.ResponseWriter, r *http.Request) {
+	teamId, err := pathVariable(r, "teamId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	patchJSON, err := ioutil.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	team, err := router.adminService.UpdateTeam(teamId, patchJSON)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, team)
+}
+
+func (router *Router) adminDeleteTeam(w http.ResponseWriter, r *http.Request) {
+	teamId, err := pathVariable(r, "teamId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = router.adminService.DeleteTeam(teamId); err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (router *Router) adminGetPlayers(w http.ResponseWriter, r *http.Request) {
+	players, err := router.adminService.GetPlayers()
+
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, players)
+}
+
+func (router *Router) adminCreatePlayer(w http.ResponseWriter, r *http.Request) {
+	var player domain.AdminPlayer
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&player); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	created, err := router.adminService.CreatePlayer(player)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	router.respondCreated(w, r, fmt.Sprintf("/admin/players/%d", created.Id))
+}
+
+func (router *Router) adminGetPlayer(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	player, err := router.adminService.GetPlayer(playerId)
+
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, player)
+}
+
+func (router *Router) adminUpdatePlayer(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	patchJSON, err := ioutil.ReadAll(r.Body)
+	defer r.Body.Close()
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	player, err := router.adminService.UpdatePlayer(playerId, patchJSON)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, player)
+}
+
+func (router *Router) adminDeletePlayer(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = router.adminService.DeletePlayer(playerId); err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (router *Router) adminNewTransfer(w http.ResponseWriter, r *http.Request) {
+	var tr PutInTransferListRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&tr); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	transferId, err := router.adminService.NewTransfer(tr.PlayerId, tr.AskedPrice)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	router.respondCreated(w, r, fmt.Sprintf("/transfers/%d", transferId))
+}
+
+type CreateFreeAgentsRequest struct {
+	Country  string                `json:"country"`
+	Position domain.PlayerPosition `json:"position"`
+	Count    int                   `json:"count"`
+	Seed     *int64                `json:"seed"`
+}
+
+func (router *Router) adminCreateFreeAgents(w http.ResponseWriter, r *http.Request) {
+	var request CreateFreeAgentsRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&request); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	players, err := router.adminService.CreateFreeAgents(request.Country, request.Position, request.Count, request.Seed)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, players)
+}
+
+func pathVariable(r *http.Request, name string) (int, error) {
+	return strconv.Atoi(mux.Vars(r)[name])
+}
+
+func statusFor(err error, fallback int) int {
+	if err == sql.ErrNoRows {
+		return http.StatusNotFound
+	}
+
+	if err == service.ErrTransferWindowClosed || err == repository.ErrTeamInRunningLeague || errors.Is(err, service.ErrContractRejected) {
+		return http.StatusConflict
+	}
+
+	return fallback
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/service"
+	"net/http"
+)
+
+func (router *Router) getContractDemand(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	demand, err := router.contractService.GetDemand(principal.AccountId, playerId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, demand)
+}
+
+func (router *Router) renewContract(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var terms domain.ContractTerms
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&terms); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	contract, err := router.contractService.RenewContract(principal.AccountId, playerId, terms)
+
+	if errors.Is(err, service.ErrContractRejected) {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, contract)
+}
+
+type PayReleaseClauseRequest struct {
+	Contract *domain.ContractTerms `json:"contract"`
+}
+
+func (router *Router) payReleaseClause(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request PayReleaseClauseRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&request); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	contract, err := router.contractService.PayReleaseClause(principal.AccountId, playerId, request.Contract)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, contract)
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+)
+
+func (router *Router) getTeamFinances(w http.ResponseWriter, r *http.Request) {
+	teamId, err := pathVariable(r, "teamId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	finances, err := router.financeService.GetTeamFinances(principal.AccountId, teamId)
+
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, finances)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/service"
+	"net/http"
+	"strconv"
+)
+
+func (router *Router) getFreeAgents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFreeAgentFilter(r)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	freeAgents, err := router.freeAgentService.GetFreeAgents(filter)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, freeAgents)
+}
+
+func parseFreeAgentFilter(r *http.Request) (domain.FreeAgentFilter, error) {
+	query := r.URL.Query()
+	filter := domain.FreeAgentFilter{
+		Country:    query.Get("country"),
+		PlayerName: query.Get("playerName"),
+		Position:   domain.PlayerPosition(query.Get("position")),
+	}
+
+	ints := map[string]*int{
+		"minAge":   &filter.MinAge,
+		"maxAge":   &filter.MaxAge,
+		"minValue": &filter.MinMarketValue,
+		"maxValue": &filter.MaxMarketValue,
+		"limit":    &filter.Limit,
+	}
+
+	for name, value := range ints {
+		if raw := query.Get(name); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %s", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	return filter, nil
+}
+
+func (router *Router) signFreeAgent(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var terms domain.ContractTerms
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&terms); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	player, err := router.freeAgentService.SignFreeAgent(principal.AccountId, playerId, terms)
+
+	if errors.Is(err, service.ErrContractRejected) {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, player)
+}
+
+func (router *Router) releasePlayer(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	player, err := router.freeAgentService.ReleasePlayer(principal.AccountId, playerId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, player)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+type CreateLeagueRequest struct {
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Seed    *int64 `json:"seed"`
+}
+
+type EnrolTeamRequest struct {
+	TeamId int `json:"teamId"`
+}
+
+func (router *Router) createLeague(w http.ResponseWriter, r *http.Request) {
+	var clr CreateLeagueRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&clr); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	league, err := router.leagueService.CreateLeague(clr.Name, clr.Country, clr.Seed)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, league)
+}
+
+func (router *Router) getLeagues(w http.ResponseWriter, r *http.Request) {
+	leagues, err := router.leagueService.GetLeagues()
+
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, leagues)
+}
+
+func (router *Router) getLeague(w http.ResponseWriter, r *http.Request) {
+	leagueId, err := pathVariable(r, "leagueId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	league, err := router.leagueService.GetLeague(leagueId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, league)
+}
+
+func (router *Router) enrolTeam(w http.ResponseWriter, r *http.Request) {
+	leagueId, err := pathVariable(r, "leagueId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var etr EnrolTeamRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&etr); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+
+	if err = router.leagueService.EnrolTeam(principal, leagueId, etr.TeamId); err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (router *Router) startLeague(w http.ResponseWriter, r *http.Request) {
+	leagueId, err := pathVariable(r, "leagueId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	league, err := router.leagueService.StartLeague(leagueId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, league)
+}
+
+func (router *Router) playLeagueRound(w http.ResponseWriter, r *http.Request) {
+	leagueId, err := pathVariable(r, "leagueId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fixtures, err := router.leagueService.PlayNextRound(leagueId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, fixtures)
+}
+
+func (router *Router) getFixtures(w http.ResponseWriter, r *http.Request) {
+	leagueId, err := pathVariable(r, "leagueId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	round := 0
+
+	if value := r.URL.Query().Get("round"); value != "" {
+		if round, err = strconv.Atoi(value); err != nil || round < 1 {
+			respondWithError(w, http.StatusBadRequest, "invalid round")
+			return
+		}
+	}
+
+	fixtures, err := router.leagueService.GetFixtures(leagueId, round)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, fixtures)
+}
+
+func (router *Router) getStandings(w http.ResponseWriter, r *http.Request) {
+	leagueId, err := pathVariable(r, "leagueId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	standings, err := router.leagueService.GetStandings(leagueId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, standings)
+}
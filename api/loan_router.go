@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"net/http"
+)
+
+type ProposeLoanRequest struct {
+	PlayerId       int  `json:"playerId"`
+	Fee            int  `json:"fee"`
+	Days           *int `json:"days"`
+	Rounds         *int `json:"rounds"`
+	BuyOptionPrice *int `json:"buyOptionPrice"`
+}
+
+type BuyLoanedPlayerRequest struct {
+	Contract *domain.ContractTerms `json:"contract"`
+}
+
+func (router *Router) proposeLoan(w http.ResponseWriter, r *http.Request) {
+	var plr ProposeLoanRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&plr); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	loan, err := router.loanService.ProposeLoan(principal.AccountId, domain.Loan{
+		PlayerId:       plr.PlayerId,
+		Fee:            plr.Fee,
+		DurationDays:   plr.Days,
+		DurationRounds: plr.Rounds,
+		BuyOptionPrice: plr.BuyOptionPrice,
+	})
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, loan)
+}
+
+func (router *Router) getLoans(w http.ResponseWriter, r *http.Request) {
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	loans, err := router.loanService.GetLoans(principal.AccountId, r.URL.Query().Get("status"))
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, loans)
+}
+
+func (router *Router) getLoan(w http.ResponseWriter, r *http.Request) {
+	loanId, err := pathVariable(r, "loanId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	loan, err := router.loanService.GetLoan(router.authenticationMiddleware.GetPrincipal(r), loanId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, loan)
+}
+
+func (router *Router) acceptLoan(w http.ResponseWriter, r *http.Request) {
+	router.respondToLoan(w, r, router.loanService.AcceptLoan)
+}
+
+func (router *Router) rejectLoan(w http.ResponseWriter, r *http.Request) {
+	router.respondToLoan(w, r, router.loanService.RejectLoan)
+}
+
+func (router *Router) withdrawLoan(w http.ResponseWriter, r *http.Request) {
+	router.respondToLoan(w, r, router.loanService.WithdrawLoan)
+}
+
+func (router *Router) buyLoanedPlayer(w http.ResponseWriter, r *http.Request) {
+	var request BuyLoanedPlayerRequest
+	_ = json.NewDecoder(r.Body).Decode(&request)
+	defer r.Body.Close()
+
+	router.respondToLoan(w, r, func(accountId, loanId int) (*domain.Loan, error) {
+		return router.loanService.ExerciseBuyOption(accountId, loanId, request.Contract)
+	})
+}
+
+func (router *Router) respondToLoan(w http.ResponseWriter, r *http.Request, respond func(accountId, loanId int) (*domain.Loan, error)) {
+	loanId, err := pathVariable(r, "loanId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	loan, err := respond(principal.AccountId, loanId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusConflict), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, loan)
+}
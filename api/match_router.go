@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type PlayMatchRequest struct {
+	HomeTeamId int    `json:"homeTeamId"`
+	AwayTeamId int    `json:"awayTeamId"`
+	Seed       *int64 `json:"seed"`
+}
+
+func (router *Router) playMatch(w http.ResponseWriter, r *http.Request) {
+	var pmr PlayMatchRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&pmr); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	match, err := router.matchService.PlayMatch(principal.AccountId, pmr.HomeTeamId, pmr.AwayTeamId, pmr.Seed)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, match)
+}
+
+func (router *Router) getMatch(w http.ResponseWriter, r *http.Request) {
+	matchId, err := pathVariable(r, "matchId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	match, err := router.matchService.GetMatch(matchId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, match)
+}
+
+func (router *Router) getTeamMatches(w http.ResponseWriter, r *http.Request) {
+	teamId, err := pathVariable(r, "teamId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	matches, err := router.matchService.GetTeamMatches(teamId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, matches)
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"net/http"
+)
+
+type MakeOfferRequest struct {
+	PlayerId int                   `json:"playerId"`
+	Amount   int                   `json:"amount"`
+	Contract *domain.ContractTerms `json:"contract"`
+}
+
+type CounterOfferRequest struct {
+	Amount int `json:"amount"`
+}
+
+func (router *Router) makeOffer(w http.ResponseWriter, r *http.Request) {
+	var mor MakeOfferRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&mor); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	offer, err := router.offerService.MakeOffer(principal.AccountId, mor.PlayerId, mor.Amount, mor.Contract)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, offer)
+}
+
+func (router *Router) getOffers(w http.ResponseWriter, r *http.Request) {
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	offers, err := router.offerService.GetOffers(principal.AccountId, r.URL.Query().Get("status"))
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, offers)
+}
+
+func (router *Router) getOffer(w http.ResponseWriter, r *http.Request) {
+	offerId, err := pathVariable(r, "offerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	offer, err := router.offerService.GetOffer(router.authenticationMiddleware.GetPrincipal(r), offerId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, offer)
+}
+
+func (router *Router) counterOffer(w http.ResponseWriter, r *http.Request) {
+	offerId, err := pathVariable(r, "offerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var cor CounterOfferRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&cor); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	offer, err := router.offerService.CounterOffer(principal.AccountId, offerId, cor.Amount)
+	respondWithOffer(w, offer, err)
+}
+
+func (router *Router) acceptOffer(w http.ResponseWriter, r *http.Request) {
+	router.respondToOffer(w, r, router.offerService.AcceptOffer)
+}
+
+func (router *Router) rejectOffer(w http.ResponseWriter, r *http.Request) {
+	router.respondToOffer(w, r, router.offerService.RejectOffer)
+}
+
+func (router *Router) withdrawOffer(w http.ResponseWriter, r *http.Request) {
+	router.respondToOffer(w, r, router.offerService.WithdrawOffer)
+}
+
+func (router *Router) respondToOffer(w http.ResponseWriter, r *http.Request, respond func(accountId, offerId int) (*domain.Offer, error)) {
+	offerId, err := pathVariable(r, "offerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	offer, err := respond(principal.AccountId, offerId)
+	respondWithOffer(w, offer, err)
+}
+
+func respondWithOffer(w http.ResponseWriter, offer *domain.Offer, err error) {
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusConflict), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, offer)
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
 	"github.com/giancarlobastos/soccer-manager-api/security"
 	"github.com/giancarlobastos/soccer-manager-api/service"
 	"github.com/gorilla/mux"
@@ -10,6 +12,8 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Router struct {
@@ -17,27 +21,120 @@ type Router struct {
 	teamService              *service.TeamService
 	playerService            *service.PlayerService
 	transferService          *service.TransferService
+	adminService             *service.AdminService
+	matchService             *service.MatchService
+	leagueService            *service.LeagueService
+	offerService             *service.OfferService
+	loanService              *service.LoanService
+	swapService              *service.SwapService
+	windowService            *service.TransferWindowService
+	financeService           *service.FinanceService
+	contractService          *service.ContractService
+	freeAgentService         *service.FreeAgentService
 	authenticationMiddleware *security.AuthenticationMiddleware
 }
 
-func NewRouter(as *service.AccountService, ts *service.TeamService, ps *service.PlayerService, tfs *service.TransferService) *Router {
-	amw := security.NewAuthenticationMiddleware(as,
-		map[string]string{
-			"getAccount":      "USER",
-			"getPlayer":       "USER",
-			"updatePlayer":    "USER",
-			"getTeam":         "USER",
-			"updateTeam":      "USER",
-			"newTransfer":     "USER",
-			"getTransfers":    "USER",
-			"confirmTransfer": "USER",
-			"updateTransfer":  "USER",
-		})
+var routeProfiles = map[string]string{
+	"logout":             "USER",
+	"getAccount":         "USER",
+	"getPlayer":          "USER",
+	"updatePlayer":       "USER",
+	"getTeam":            "USER",
+	"updateTeam":         "USER",
+	"newTransfer":        "USER",
+	"getTransfers":       "USER",
+	"confirmTransfer":    "USER",
+	"updateTransfer":     "USER",
+	"withdrawTransfer":   "USER",
+	"getTransferHistory": "USER",
+	"getPlayerCareer":    "USER",
+	"placeBid":           "USER",
+	"getBids":            "USER",
+	"makeOffer":          "USER",
+	"getOffers":          "USER",
+	"getOffer":           "USER",
+	"counterOffer":       "USER",
+	"acceptOffer":        "USER",
+	"rejectOffer":        "USER",
+	"withdrawOffer":      "USER",
+	"proposeLoan":        "USER",
+	"getLoans":           "USER",
+	"getLoan":            "USER",
+	"acceptLoan":         "USER",
+	"rejectLoan":         "USER",
+	"withdrawLoan":       "USER",
+	"buyLoanedPlayer":    "USER",
+	"proposeSwap":        "USER",
+	"getSwaps":           "USER",
+	"getSwap":            "USER",
+	"acceptSwap":         "USER",
+	"rejectSwap":         "USER",
+	"withdrawSwap":       "USER",
+	"getTransferWindows": "USER",
+	"playMatch":          "USER",
+	"getMatch":           "USER",
+	"getTeamMatches":     "USER",
+	"getTeamFinances":    "USER",
+	"getContractDemand":  "USER",
+	"renewContract":      "USER",
+	"payReleaseClause":   "USER",
+	"getFreeAgents":      "USER",
+	"signFreeAgent":      "USER",
+	"releasePlayer":      "USER",
+	"getLeagues":         "USER",
+	"getLeague":          "USER",
+	"enrolTeam":          "USER",
+	"getFixtures":        "USER",
+	"getStandings":       "USER",
+
+	"adminGetAccounts":   "ADMIN",
+	"adminCreateAccount": "ADMIN",
+	"adminGetAccount":    "ADMIN",
+	"adminUpdateAccount": "ADMIN",
+	"adminDeleteAccount": "ADMIN",
+	"adminGetTeams":      "ADMIN",
+	"adminCreateTeam":    "ADMIN",
+	"adminGetTeam":       "ADMIN",
+	"adminUpdateTeam":    "ADMIN",
+	"adminDeleteTeam":    "ADMIN",
+	"adminGetPlayers":    "ADMIN",
+	"adminCreatePlayer":  "ADMIN",
+	"adminGetPlayer":     "ADMIN",
+	"adminUpdatePlayer":  "ADMIN",
+	"adminDeletePlayer":  "ADMIN",
+	"adminNewTransfer":   "ADMIN",
+	"adminLockAccount":   "ADMIN",
+	"adminUnlockAccount": "ADMIN",
+	"adminLockEvents":    "ADMIN",
+	"createLeague":       "ADMIN",
+	"startLeague":        "ADMIN",
+	"playLeagueRound":    "ADMIN",
+
+	"adminGetTransferWindows":   "ADMIN",
+	"adminCreateTransferWindow": "ADMIN",
+	"adminOpenTransferWindow":   "ADMIN",
+	"adminCloseTransferWindow":  "ADMIN",
+	"adminDeleteTransferWindow": "ADMIN",
+	"adminCreateFreeAgents":     "ADMIN",
+}
+
+func NewRouter(jwtConfig config.JWTConfig, as *service.AccountService, ss *service.SessionService, ts *service.TeamService, ps *service.PlayerService, tfs *service.TransferService, ads *service.AdminService, ms *service.MatchService, ls *service.LeagueService, ofs *service.OfferService, lns *service.LoanService, sws *service.SwapService, tws *service.TransferWindowService, fns *service.FinanceService, cns *service.ContractService, fas *service.FreeAgentService) *Router {
+	amw := security.NewAuthenticationMiddleware(as, ss, jwtConfig, routeProfiles)
 	return &Router{
 		accountService:           as,
 		teamService:              ts,
 		playerService:            ps,
 		transferService:          tfs,
+		adminService:             ads,
+		matchService:             ms,
+		leagueService:            ls,
+		offerService:             ofs,
+		loanService:              lns,
+		swapService:              sws,
+		windowService:            tws,
+		financeService:           fns,
+		contractService:          cns,
+		freeAgentService:         fas,
 		authenticationMiddleware: amw,
 	}
 }
@@ -49,29 +146,134 @@ type CreateAccountRequest struct {
 	LastName  string `json:"lastName"`
 }
 
+type UnlockAccountRequest struct {
+	Email string `json:"email"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
 type PutInTransferListRequest struct {
 	PlayerId   int
 	AskedPrice int
+	ExpiresAt  *time.Time
+	Auction    *AuctionRequest
+}
+
+type AuctionRequest struct {
+	Deadline time.Time
+	Sealed   bool
+}
+
+type PlaceBidRequest struct {
+	Amount   int
+	Contract *domain.ContractTerms `json:"contract"`
 }
 
 func (router *Router) Start(addr string) {
+	log.Fatal(http.ListenAndServe(addr, router.handler()))
+}
+
+func (router *Router) handler() *mux.Router {
 	r := mux.NewRouter()
 
 	r.HandleFunc("/accounts", router.createAccount).Methods("POST")
 	r.HandleFunc("/accounts/{accountId}", router.getAccount).Methods("GET").Name("getAccount")
 	r.HandleFunc("/verify-account", router.verifyAccount).Queries("token", "{token}").Methods("GET")
+	r.HandleFunc("/unlock-account", router.requestAccountUnlock).Methods("POST")
+	r.HandleFunc("/unlock-account", router.unlockAccount).Queries("token", "{token}").Methods("GET")
+	r.HandleFunc("/password-reset", router.requestPasswordReset).Methods("POST")
+	r.HandleFunc("/password-reset/confirm", router.confirmPasswordReset).Methods("POST")
 	r.HandleFunc("/players/{playerId}", router.getPlayer).Methods("GET").Name("getPlayer")
 	r.HandleFunc("/players/{playerId}", router.updatePlayer).Methods("PATCH").Name("updatePlayer")
+	r.HandleFunc("/players/{playerId}/career", router.getPlayerCareer).Methods("GET").Name("getPlayerCareer")
+	r.HandleFunc("/players/{playerId}/contract/demand", router.getContractDemand).Methods("GET").Name("getContractDemand")
+	r.HandleFunc("/players/{playerId}/contract/renew", router.renewContract).Methods("POST").Name("renewContract")
+	r.HandleFunc("/players/{playerId}/release-clause", router.payReleaseClause).Methods("POST").Name("payReleaseClause")
+	r.HandleFunc("/players/{playerId}/release", router.releasePlayer).Methods("POST").Name("releasePlayer")
 	r.HandleFunc("/teams/{teamId}", router.getTeam).Methods("GET").Name("getTeam")
 	r.HandleFunc("/teams/{teamId}", router.updateTeam).Methods("PATCH").Name("updateTeam")
+	r.HandleFunc("/teams/{teamId}/matches", router.getTeamMatches).Methods("GET").Name("getTeamMatches")
+	r.HandleFunc("/teams/{teamId}/finances", router.getTeamFinances).Methods("GET").Name("getTeamFinances")
+	r.HandleFunc("/free-agents", router.getFreeAgents).Methods("GET").Name("getFreeAgents")
+	r.HandleFunc("/free-agents/{playerId}/sign", router.signFreeAgent).Methods("POST").Name("signFreeAgent")
 	r.HandleFunc("/transfers", router.newTransfer).Methods("POST").Name("newTransfer")
 	r.HandleFunc("/transfers", router.getTransfers).Methods("GET").Name("getTransfers")
+	r.HandleFunc("/transfers/history", router.getTransferHistory).Methods("GET").Name("getTransferHistory")
 	r.HandleFunc("/transfers/{transferId}", router.confirmTransfer).Methods("PUT").Name("confirmTransfer")
 	r.HandleFunc("/transfers/{transferId}", router.updateTransfer).Methods("PATCH").Name("updateTransfer")
+	r.HandleFunc("/transfers/{transferId}", router.withdrawTransfer).Methods("DELETE").Name("withdrawTransfer")
+	r.HandleFunc("/transfers/{transferId}/bids", router.placeBid).Methods("POST").Name("placeBid")
+	r.HandleFunc("/transfers/{transferId}/bids", router.getBids).Methods("GET").Name("getBids")
+	r.HandleFunc("/offers", router.makeOffer).Methods("POST").Name("makeOffer")
+	r.HandleFunc("/offers", router.getOffers).Methods("GET").Name("getOffers")
+	r.HandleFunc("/offers/{offerId}", router.getOffer).Methods("GET").Name("getOffer")
+	r.HandleFunc("/offers/{offerId}/counter", router.counterOffer).Methods("POST").Name("counterOffer")
+	r.HandleFunc("/offers/{offerId}/accept", router.acceptOffer).Methods("POST").Name("acceptOffer")
+	r.HandleFunc("/offers/{offerId}/reject", router.rejectOffer).Methods("POST").Name("rejectOffer")
+	r.HandleFunc("/offers/{offerId}/withdraw", router.withdrawOffer).Methods("POST").Name("withdrawOffer")
+	r.HandleFunc("/loans", router.proposeLoan).Methods("POST").Name("proposeLoan")
+	r.HandleFunc("/loans", router.getLoans).Methods("GET").Name("getLoans")
+	r.HandleFunc("/loans/{loanId}", router.getLoan).Methods("GET").Name("getLoan")
+	r.HandleFunc("/loans/{loanId}/accept", router.acceptLoan).Methods("POST").Name("acceptLoan")
+	r.HandleFunc("/loans/{loanId}/reject", router.rejectLoan).Methods("POST").Name("rejectLoan")
+	r.HandleFunc("/loans/{loanId}/withdraw", router.withdrawLoan).Methods("POST").Name("withdrawLoan")
+	r.HandleFunc("/loans/{loanId}/buy", router.buyLoanedPlayer).Methods("POST").Name("buyLoanedPlayer")
+	r.HandleFunc("/swaps", router.proposeSwap).Methods("POST").Name("proposeSwap")
+	r.HandleFunc("/swaps", router.getSwaps).Methods("GET").Name("getSwaps")
+	r.HandleFunc("/swaps/{swapId}", router.getSwap).Methods("GET").Name("getSwap")
+	r.HandleFunc("/swaps/{swapId}/accept", router.acceptSwap).Methods("POST").Name("acceptSwap")
+	r.HandleFunc("/swaps/{swapId}/reject", router.rejectSwap).Methods("POST").Name("rejectSwap")
+	r.HandleFunc("/swaps/{swapId}/withdraw", router.withdrawSwap).Methods("POST").Name("withdrawSwap")
+	r.HandleFunc("/transfer-windows", router.getTransferWindowStatus).Methods("GET").Name("getTransferWindows")
+	r.HandleFunc("/matches", router.playMatch).Methods("POST").Name("playMatch")
+	r.HandleFunc("/matches/{matchId}", router.getMatch).Methods("GET").Name("getMatch")
+	r.HandleFunc("/leagues", router.createLeague).Methods("POST").Name("createLeague")
+	r.HandleFunc("/leagues", router.getLeagues).Methods("GET").Name("getLeagues")
+	r.HandleFunc("/leagues/{leagueId}", router.getLeague).Methods("GET").Name("getLeague")
+	r.HandleFunc("/leagues/{leagueId}/teams", router.enrolTeam).Methods("POST").Name("enrolTeam")
+	r.HandleFunc("/leagues/{leagueId}/start", router.startLeague).Methods("POST").Name("startLeague")
+	r.HandleFunc("/leagues/{leagueId}/rounds", router.playLeagueRound).Methods("POST").Name("playLeagueRound")
+	r.HandleFunc("/leagues/{leagueId}/fixtures", router.getFixtures).Methods("GET").Name("getFixtures")
+	r.HandleFunc("/leagues/{leagueId}/standings", router.getStandings).Methods("GET").Name("getStandings")
+
+	r.HandleFunc("/admin/accounts", router.adminGetAccounts).Methods("GET").Name("adminGetAccounts")
+	r.HandleFunc("/admin/accounts", router.adminCreateAccount).Methods("POST").Name("adminCreateAccount")
+	r.HandleFunc("/admin/accounts/{accountId}", router.adminGetAccount).Methods("GET").Name("adminGetAccount")
+	r.HandleFunc("/admin/accounts/{accountId}", router.adminUpdateAccount).Methods("PATCH").Name("adminUpdateAccount")
+	r.HandleFunc("/admin/accounts/{accountId}", router.adminDeleteAccount).Methods("DELETE").Name("adminDeleteAccount")
+	r.HandleFunc("/admin/accounts/{accountId}/lock", router.adminLockAccount).Methods("POST").Name("adminLockAccount")
+	r.HandleFunc("/admin/accounts/{accountId}/unlock", router.adminUnlockAccount).Methods("POST").Name("adminUnlockAccount")
+	r.HandleFunc("/admin/accounts/{accountId}/lock-events", router.adminLockEvents).Methods("GET").Name("adminLockEvents")
+	r.HandleFunc("/admin/teams", router.adminGetTeams).Methods("GET").Name("adminGetTeams")
+	r.HandleFunc("/admin/teams", router.adminCreateTeam).Methods("POST").Name("adminCreateTeam")
+	r.HandleFunc("/admin/teams/{teamId}", router.adminGetTeam).Methods("GET").Name("adminGetTeam")
+	r.HandleFunc("/admin/teams/{teamId}", router.adminUpdateTeam).Methods("PATCH").Name("adminUpdateTeam")
+	r.HandleFunc("/admin/teams/{teamId}", router.adminDeleteTeam).Methods("DELETE").Name("adminDeleteTeam")
+	r.HandleFunc("/admin/players", router.adminGetPlayers).Methods("GET").Name("adminGetPlayers")
+	r.HandleFunc("/admin/players", router.adminCreatePlayer).Methods("POST").Name("adminCreatePlayer")
+	r.HandleFunc("/admin/players/{playerId}", router.adminGetPlayer).Methods("GET").Name("adminGetPlayer")
+	r.HandleFunc("/admin/players/{playerId}", router.adminUpdatePlayer).Methods("PATCH").Name("adminUpdatePlayer")
+	r.HandleFunc("/admin/players/{playerId}", router.adminDeletePlayer).Methods("DELETE").Name("adminDeletePlayer")
+	r.HandleFunc("/admin/transfers", router.adminNewTransfer).Methods("POST").Name("adminNewTransfer")
+	r.HandleFunc("/admin/free-agents", router.adminCreateFreeAgents).Methods("POST").Name("adminCreateFreeAgents")
+	r.HandleFunc("/admin/transfer-windows", router.adminGetTransferWindows).Methods("GET").Name("adminGetTransferWindows")
+	r.HandleFunc("/admin/transfer-windows", router.adminCreateTransferWindow).Methods("POST").Name("adminCreateTransferWindow")
+	r.HandleFunc("/admin/transfer-windows/{windowId}/open", router.adminOpenTransferWindow).Methods("POST").Name("adminOpenTransferWindow")
+	r.HandleFunc("/admin/transfer-windows/{windowId}/close", router.adminCloseTransferWindow).Methods("POST").Name("adminCloseTransferWindow")
+	r.HandleFunc("/admin/transfer-windows/{windowId}", router.adminDeleteTransferWindow).Methods("DELETE").Name("adminDeleteTransferWindow")
 
 	r.HandleFunc("/authenticate", router.authenticationMiddleware.Authenticate).Methods("POST")
+	r.HandleFunc("/token/refresh", router.authenticationMiddleware.Refresh).Methods("POST")
+	r.HandleFunc("/logout", router.authenticationMiddleware.Logout).Methods("POST").Name("logout")
 	r.Use(router.authenticationMiddleware.Middleware)
-	log.Fatal(http.ListenAndServe(":8080", r))
+	return r
 }
 
 func (router *Router) createAccount(w http.ResponseWriter, r *http.Request) {
@@ -130,6 +332,68 @@ func (router *Router) verifyAccount(w http.ResponseWriter, r *http.Request) {
 	respondWithError(w, http.StatusBadRequest, "Invalid token")
 }
 
+func (router *Router) requestAccountUnlock(w http.ResponseWriter, r *http.Request) {
+	var uar UnlockAccountRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&uar); err != nil || len(uar.Email) == 0 {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := router.accountService.RequestAccountUnlock(uar.Email); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to send unlock email")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (router *Router) unlockAccount(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	if len(token) > 0 && router.accountService.UnlockAccountWithToken(token) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	respondWithError(w, http.StatusBadRequest, "Invalid token")
+}
+
+func (router *Router) requestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var prr PasswordResetRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&prr); err != nil || len(prr.Email) == 0 {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := router.accountService.RequestPasswordReset(prr.Email); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Unable to send password reset email")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (router *Router) confirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var cpr ConfirmPasswordResetRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&cpr); err != nil || len(cpr.Token) == 0 {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if err := router.accountService.ResetPassword(cpr.Token, cpr.Password); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (router *Router) getPlayer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	playerId, err := strconv.Atoi(vars["playerId"])
@@ -252,7 +516,19 @@ func (router *Router) newTransfer(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	principal := router.authenticationMiddleware.GetPrincipal(r)
-	transferId, err := router.transferService.NewTransfer(principal.AccountId, tr.PlayerId, tr.AskedPrice)
+	var transferId int
+	var err error
+
+	if tr.Auction != nil {
+		transferId, err = router.transferService.NewAuction(principal.AccountId, tr.PlayerId, tr.AskedPrice, tr.Auction.Deadline, tr.Auction.Sealed)
+	} else {
+		transferId, err = router.transferService.NewTransfer(principal.AccountId, tr.PlayerId, tr.AskedPrice, tr.ExpiresAt)
+	}
+
+	if err == service.ErrTransferWindowClosed {
+		respondWithError(w, http.StatusConflict, err.Error())
+		return
+	}
 
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
@@ -263,36 +539,247 @@ func (router *Router) newTransfer(w http.ResponseWriter, r *http.Request) {
 }
 
 func (router *Router) getTransfers(w http.ResponseWriter, r *http.Request) {
-	transfers, err := router.transferService.GetTransfers()
+	filter, err := parseTransferFilter(r)
 
 	if err != nil {
-		respondWithError(w, http.StatusNotFound, err.Error())
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := router.transferService.GetTransfers(filter)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, page)
+}
+
+func parseTransferFilter(r *http.Request) (domain.TransferFilter, error) {
+	query := r.URL.Query()
+	filter := domain.TransferFilter{
+		Country:    query.Get("country"),
+		TeamName:   query.Get("teamName"),
+		PlayerName: query.Get("playerName"),
+		Position:   domain.PlayerPosition(query.Get("position")),
+		Mode:       query.Get("mode"),
+		SortBy:     strings.TrimPrefix(query.Get("sort"), "-"),
+		Descending: strings.HasPrefix(query.Get("sort"), "-"),
+		Cursor:     query.Get("cursor"),
+	}
+
+	ints := map[string]*int{
+		"minAge":   &filter.MinAge,
+		"maxAge":   &filter.MaxAge,
+		"minPrice": &filter.MinAskedPrice,
+		"maxPrice": &filter.MaxAskedPrice,
+		"minValue": &filter.MinMarketValue,
+		"maxValue": &filter.MaxMarketValue,
+		"limit":    &filter.Limit,
+	}
+
+	for name, value := range ints {
+		if raw := query.Get(name); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %s", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	return filter, nil
+}
+
+func (router *Router) getTransferHistory(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTransferHistoryFilter(r)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transfers, err := router.transferService.GetTransferHistory(filter)
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	respondWithJSON(w, http.StatusOK, transfers)
 }
 
+func parseTransferHistoryFilter(r *http.Request) (domain.TransferHistoryFilter, error) {
+	query := r.URL.Query()
+	filter := domain.TransferHistoryFilter{Status: strings.ToUpper(query.Get("status"))}
+
+	ints := map[string]*int{
+		"team":   &filter.TeamId,
+		"player": &filter.PlayerId,
+		"limit":  &filter.Limit,
+	}
+
+	for name, value := range ints {
+		if raw := query.Get(name); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %s", name, raw)
+			}
+
+			*value = parsed
+		}
+	}
+
+	dates := map[string]**time.Time{
+		"from": &filter.From,
+		"to":   &filter.To,
+	}
+
+	for name, value := range dates {
+		if raw := query.Get(name); raw != "" {
+			parsed, err := parseDate(raw, name == "to")
+
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %s", name, raw)
+			}
+
+			*value = &parsed
+		}
+	}
+
+	return filter, nil
+}
+
+func parseDate(raw string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", raw)
+
+	if err == nil && endOfDay {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+
+	return parsed, err
+}
+
+func (router *Router) getPlayerCareer(w http.ResponseWriter, r *http.Request) {
+	playerId, err := pathVariable(r, "playerId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	career, err := router.transferService.GetPlayerCareer(playerId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, career)
+}
+
+type ConfirmTransferRequest struct {
+	Contract *domain.ContractTerms `json:"contract"`
+}
+
 func (router *Router) confirmTransfer(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	transferId, err := strconv.Atoi(vars["transferId"])
+	transferId, err := pathVariable(r, "transferId")
 
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	var request ConfirmTransferRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&request); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
 	principal := router.authenticationMiddleware.GetPrincipal(r)
-	err = router.transferService.ConfirmTransfer(principal.AccountId, transferId)
 
-	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Unable to confirm transfer")
+	if err = router.transferService.ConfirmTransfer(principal.AccountId, transferId, request.Contract); err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
+func (router *Router) withdrawTransfer(w http.ResponseWriter, r *http.Request) {
+	transferId, err := pathVariable(r, "transferId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+
+	if err = router.transferService.WithdrawTransfer(principal, transferId); err != nil {
+		respondWithError(w, statusFor(err, http.StatusConflict), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (router *Router) placeBid(w http.ResponseWriter, r *http.Request) {
+	transferId, err := pathVariable(r, "transferId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request PlaceBidRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&request); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	bid, err := router.transferService.PlaceBid(principal.AccountId, transferId, request.Amount, request.Contract)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusConflict), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, bid)
+}
+
+func (router *Router) getBids(w http.ResponseWriter, r *http.Request) {
+	transferId, err := pathVariable(r, "transferId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	bids, err := router.transferService.GetBids(principal, transferId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, bids)
+}
+
 func (router *Router) updateTransfer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	transferId, err := strconv.Atoi(vars["transferId"])
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/repository/memory"
+	"github.com/giancarlobastos/soccer-manager-api/service"
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPassword = "secret"
+
+var publicRoutes = map[string]bool{
+	"POST /accounts":               true,
+	"GET /verify-account":          true,
+	"POST /unlock-account":         true,
+	"GET /unlock-account":          true,
+	"POST /password-reset":         true,
+	"POST /password-reset/confirm": true,
+	"POST /authenticate":           true,
+	"POST /token/refresh":          true,
+}
+
+func newTestRouter() (http.Handler, repository.Repositories, *service.AccountService) {
+	repositories := memory.NewRepositories()
+	cfg := config.Default()
+	cfg.JWT.Key = "test-key"
+	as := service.NewAccountService(repositories.Accounts, repositories.Teams, repositories.Players, repositories.AccountTokens,
+		repositories.Sessions, nil, nil, cfg.Game)
+	ss := service.NewSessionService(repositories.Sessions, cfg.JWT.RefreshTTL.Duration)
+	ws := service.NewTransferWindowService(repositories.Windows, repositories.Transfers, repositories.Leagues, cfg.Game)
+	tfs := service.NewTransferService(repositories.Transfers, repositories.Players, repositories.Teams, ws, cfg.Game)
+	router := NewRouter(cfg.JWT, as, ss, nil, nil, tfs, nil, nil, nil, nil, nil, nil, ws, nil, nil, nil)
+
+	return router.handler(), repositories, as
+}
+
+func newTestAccount(t *testing.T, repositories repository.Repositories, username, profile string) *domain.Account {
+	password, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account := &domain.Account{
+		Username:  username,
+		Password:  string(password),
+		Confirmed: true,
+		Profile:   profile,
+		Team:      &domain.Team{Name: username},
+	}
+
+	if err = repositories.Accounts.CreateAccount(account, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	return account
+}
+
+func authenticate(t *testing.T, handler http.Handler, username string) string {
+	body, _ := json.Marshal(map[string]string{"username": username, "password": testPassword})
+	response := serve(handler, "POST", "/authenticate", "", body)
+
+	if response.Code != http.StatusOK {
+		t.Fatalf("expected %s to authenticate, got %d: %s", username, response.Code, response.Body.String())
+	}
+
+	var authentication struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.Unmarshal(response.Body.Bytes(), &authentication); err != nil {
+		t.Fatal(err)
+	}
+
+	return authentication.Token
+}
+
+func serve(handler http.Handler, method, path, token string, body []byte) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, bytes.NewReader(body))
+
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func TestRouteProfiles(t *testing.T) {
+	handler, _, _ := newTestRouter()
+
+	err := handler.(*mux.Router).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, _ := route.GetPathTemplate()
+		methods, _ := route.GetMethods()
+		name := route.GetName()
+		profile, protected := routeProfiles[name]
+
+		switch {
+		case name == "":
+			for _, method := range methods {
+				if !publicRoutes[method+" "+path] {
+					t.Errorf("route %s %s has no name and is not a known public route", method, path)
+				}
+			}
+		case !protected:
+			t.Errorf("route %s (%s) has no required profile", name, path)
+		case strings.HasPrefix(path, "/admin") && profile != domain.AdminProfile:
+			t.Errorf("admin route %s requires %s instead of %s", name, profile, domain.AdminProfile)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRoleChecks(t *testing.T) {
+	handler, repositories, _ := newTestRouter()
+	newTestAccount(t, repositories, "user@example.com", domain.UserProfile)
+	newTestAccount(t, repositories, "admin@example.com", domain.AdminProfile)
+	userToken := authenticate(t, handler, "user@example.com")
+	adminToken := authenticate(t, handler, "admin@example.com")
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"user route as a user", "/transfer-windows", userToken, http.StatusOK},
+		{"user route as an admin", "/transfer-windows", adminToken, http.StatusOK},
+		{"admin route as a user", "/admin/transfer-windows", userToken, http.StatusForbidden},
+		{"admin route as an admin", "/admin/transfer-windows", adminToken, http.StatusOK},
+		{"user route with a forged token", "/transfer-windows", userToken + "x", http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if response := serve(handler, "GET", test.path, test.token, nil); response.Code != test.status {
+				t.Fatalf("expected %d, got %d: %s", test.status, response.Code, response.Body.String())
+			}
+		})
+	}
+
+	if response := serve(handler, "GET", "/transfer-windows", "", nil); response.Code == http.StatusOK {
+		t.Fatal("expected anonymous requests to be refused")
+	}
+}
+
+func TestRevokedSessionsAreRefused(t *testing.T) {
+	tests := []struct {
+		name   string
+		revoke func(t *testing.T, handler http.Handler, as *service.AccountService, account *domain.Account, token string)
+	}{
+		{"logout", func(t *testing.T, handler http.Handler, as *service.AccountService, account *domain.Account, token string) {
+			if response := serve(handler, "POST", "/logout", token, nil); response.Code != http.StatusNoContent {
+				t.Fatalf("expected logout to succeed, got %d", response.Code)
+			}
+		}},
+		{"admin lock", func(t *testing.T, handler http.Handler, as *service.AccountService, account *domain.Account, token string) {
+			if err := as.LockAccount(account.Id, account.Id, "suspicious activity"); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler, repositories, as := newTestRouter()
+			account := newTestAccount(t, repositories, "user@example.com", domain.UserProfile)
+			token := authenticate(t, handler, account.Username)
+			test.revoke(t, handler, as, account, token)
+
+			if response := serve(handler, "GET", "/transfer-windows", token, nil); response.Code != http.StatusUnauthorized {
+				t.Fatalf("expected a revoked session to be refused, got %d", response.Code)
+			}
+		})
+	}
+}
+
+func TestConfirmTransferErrors(t *testing.T) {
+	handler, repositories, _ := newTestRouter()
+	newTestAccount(t, repositories, "buyer@example.com", domain.UserProfile)
+	token := authenticate(t, handler, "buyer@example.com")
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		status  int
+		message string
+	}{
+		{"malformed body", "/transfers/1", "{", http.StatusBadRequest, "Invalid request payload"},
+		{"empty body", "/transfers/1", "", http.StatusBadRequest, "Invalid request payload"},
+		{"missing contract", "/transfers/1", "{}", http.StatusBadRequest, "a contract must be agreed with the player"},
+		{"unknown transfer", "/transfers/1", `{"contract":{"wage":10000,"years":2}}`, http.StatusNotFound, ""},
+		{"invalid transfer id", "/transfers/x", "{}", http.StatusBadRequest, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := serve(handler, "PUT", test.path, token, []byte(test.body))
+
+			if response.Code != test.status {
+				t.Fatalf("expected %d, got %d: %s", test.status, response.Code, response.Body.String())
+			}
+
+			if !strings.Contains(response.Body.String(), test.message) {
+				t.Fatalf("expected %q in the response, got %s", test.message, response.Body.String())
+			}
+		})
+	}
+}
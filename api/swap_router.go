@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"net/http"
+)
+
+type ProposeSwapRequest struct {
+	TeamId             int   `json:"teamId"`
+	OfferedPlayerIds   []int `json:"offeredPlayerIds"`
+	RequestedPlayerIds []int `json:"requestedPlayerIds"`
+	CashAdjustment     int   `json:"cashAdjustment"`
+}
+
+func (router *Router) proposeSwap(w http.ResponseWriter, r *http.Request) {
+	var psr ProposeSwapRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&psr); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	swap, err := router.swapService.ProposeSwap(principal.AccountId, psr.TeamId, psr.OfferedPlayerIds, psr.RequestedPlayerIds, psr.CashAdjustment)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, swap)
+}
+
+func (router *Router) getSwaps(w http.ResponseWriter, r *http.Request) {
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	swaps, err := router.swapService.GetSwaps(principal.AccountId, r.URL.Query().Get("status"))
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, swaps)
+}
+
+func (router *Router) getSwap(w http.ResponseWriter, r *http.Request) {
+	swapId, err := pathVariable(r, "swapId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	swap, err := router.swapService.GetSwap(router.authenticationMiddleware.GetPrincipal(r), swapId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, swap)
+}
+
+func (router *Router) acceptSwap(w http.ResponseWriter, r *http.Request) {
+	router.respondToSwap(w, r, router.swapService.AcceptSwap)
+}
+
+func (router *Router) rejectSwap(w http.ResponseWriter, r *http.Request) {
+	router.respondToSwap(w, r, router.swapService.RejectSwap)
+}
+
+func (router *Router) withdrawSwap(w http.ResponseWriter, r *http.Request) {
+	router.respondToSwap(w, r, router.swapService.WithdrawSwap)
+}
+
+func (router *Router) respondToSwap(w http.ResponseWriter, r *http.Request, respond func(accountId, swapId int) (*domain.Swap, error)) {
+	swapId, err := pathVariable(r, "swapId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	principal := router.authenticationMiddleware.GetPrincipal(r)
+	swap, err := respond(principal.AccountId, swapId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusConflict), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, swap)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"net/http"
+	"time"
+)
+
+type CreateTransferWindowRequest struct {
+	Name             string     `json:"name"`
+	OpensAt          *time.Time `json:"opensAt"`
+	ClosesAt         *time.Time `json:"closesAt"`
+	LeagueId         *int       `json:"leagueId"`
+	OpensAfterRound  *int       `json:"opensAfterRound"`
+	ClosesAfterRound *int       `json:"closesAfterRound"`
+	ListingPolicy    string     `json:"listingPolicy"`
+}
+
+func (router *Router) getTransferWindowStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := router.windowService.GetStatus()
+
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, status)
+}
+
+func (router *Router) adminGetTransferWindows(w http.ResponseWriter, r *http.Request) {
+	windows, err := router.windowService.GetWindows()
+
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, windows)
+}
+
+func (router *Router) adminCreateTransferWindow(w http.ResponseWriter, r *http.Request) {
+	var ctr CreateTransferWindowRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ctr); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	window, err := router.windowService.CreateWindow(domain.TransferWindow{
+		Name:             ctr.Name,
+		OpensAt:          ctr.OpensAt,
+		ClosesAt:         ctr.ClosesAt,
+		LeagueId:         ctr.LeagueId,
+		OpensAfterRound:  ctr.OpensAfterRound,
+		ClosesAfterRound: ctr.ClosesAfterRound,
+		ListingPolicy:    ctr.ListingPolicy,
+	})
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusBadRequest), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, window)
+}
+
+func (router *Router) adminOpenTransferWindow(w http.ResponseWriter, r *http.Request) {
+	router.setTransferWindowState(w, r, router.windowService.OpenWindow)
+}
+
+func (router *Router) adminCloseTransferWindow(w http.ResponseWriter, r *http.Request) {
+	router.setTransferWindowState(w, r, router.windowService.CloseWindow)
+}
+
+func (router *Router) setTransferWindowState(w http.ResponseWriter, r *http.Request, action func(windowId int) (*domain.TransferWindow, error)) {
+	windowId, err := pathVariable(r, "windowId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	window, err := action(windowId)
+
+	if err != nil {
+		respondWithError(w, statusFor(err, http.StatusConflict), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, window)
+}
+
+func (router *Router) adminDeleteTransferWindow(w http.ResponseWriter, r *http.Request) {
+	windowId, err := pathVariable(r, "windowId")
+
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err = router.windowService.DeleteWindow(windowId); err != nil {
+		respondWithError(w, statusFor(err, http.StatusInternalServerError), err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,354 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const envPrefix = "SOCCER_MANAGER_"
+
+type Config struct {
+	Database DatabaseConfig `json:"database"`
+	JWT      JWTConfig      `json:"jwt"`
+	SMTP     SMTPConfig     `json:"smtp"`
+	Server   ServerConfig   `json:"server"`
+	Game     GameConfig     `json:"game"`
+}
+
+type DatabaseConfig struct {
+	Driver      string `json:"driver"`
+	DSN         string `json:"dsn"`
+	AutoMigrate bool   `json:"autoMigrate"`
+}
+
+type JWTConfig struct {
+	Key        string   `json:"key"`
+	Issuer     string   `json:"issuer"`
+	TTL        Duration `json:"ttl"`
+	RefreshTTL Duration `json:"refreshTtl"`
+}
+
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+}
+
+type ServerConfig struct {
+	Addr    string `json:"addr"`
+	BaseURL string `json:"baseUrl"`
+}
+
+type GameConfig struct {
+	Country                 string      `json:"country"`
+	StartingCash            int         `json:"startingCash"`
+	InitialPlayerValue      int         `json:"initialPlayerValue"`
+	Squad                   SquadConfig `json:"squad"`
+	AuctionInterval         Duration    `json:"auctionInterval"`
+	MaxAuctionDuration      Duration    `json:"maxAuctionDuration"`
+	OfferTTL                Duration    `json:"offerTtl"`
+	LoanInterval            Duration    `json:"loanInterval"`
+	TransferWindowsEnforced bool        `json:"transferWindowsEnforced"`
+	TransferWindowInterval  Duration    `json:"transferWindowInterval"`
+	ListingMaxDuration      Duration    `json:"listingMaxDuration"`
+	ListingInterval         Duration    `json:"listingInterval"`
+	ContractYears           int         `json:"contractYears"`
+	WagePeriod              Duration    `json:"wagePeriod"`
+	ContractInterval        Duration    `json:"contractInterval"`
+}
+
+type SquadConfig struct {
+	GoalKeepers int `json:"goalKeepers"`
+	Defenders   int `json:"defenders"`
+	Midfielders int `json:"midfielders"`
+	Forwards    int `json:"forwards"`
+}
+
+func (sc SquadConfig) Size() int {
+	return sc.GoalKeepers + sc.Defenders + sc.Midfielders + sc.Forwards
+}
+
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+
+	if err != nil {
+		return err
+	}
+
+	d.Duration = parsed
+	return nil
+}
+
+func Default() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			Driver:      "mysql",
+			DSN:         "root:secret@tcp(mysql:3306)/soccermanager?parseTime=true&clientFoundRows=true",
+			AutoMigrate: true,
+		},
+		JWT: JWTConfig{
+			Issuer:     "soccer-manager-api",
+			TTL:        Duration{time.Hour},
+			RefreshTTL: Duration{30 * 24 * time.Hour},
+		},
+		SMTP: SMTPConfig{
+			Host: "smtp.gmail.com",
+			Port: 587,
+		},
+		Server: ServerConfig{
+			Addr:    ":8080",
+			BaseURL: "http://localhost:8080",
+		},
+		Game: GameConfig{
+			Country:            "Brazil",
+			StartingCash:       5000000,
+			InitialPlayerValue: 1000000,
+			Squad: SquadConfig{
+				GoalKeepers: 3,
+				Defenders:   6,
+				Midfielders: 6,
+				Forwards:    5,
+			},
+			AuctionInterval:        Duration{time.Minute},
+			MaxAuctionDuration:     Duration{14 * 24 * time.Hour},
+			OfferTTL:               Duration{48 * time.Hour},
+			LoanInterval:           Duration{time.Minute},
+			TransferWindowInterval: Duration{time.Minute},
+			ListingMaxDuration:     Duration{30 * 24 * time.Hour},
+			ListingInterval:        Duration{time.Minute},
+			ContractYears:          3,
+			WagePeriod:             Duration{7 * 24 * time.Hour},
+			ContractInterval:       Duration{time.Minute},
+		},
+	}
+}
+
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		content, err := ioutil.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(content, cfg); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+		}
+	}
+
+	if err := cfg.loadEnvironment(); err != nil {
+		return nil, err
+	}
+
+	if cfg.SMTP.From == "" {
+		cfg.SMTP.From = cfg.SMTP.Username
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (cfg *Config) loadEnvironment() error {
+	strs := map[string]*string{
+		"DB_DRIVER":     &cfg.Database.Driver,
+		"DB_DSN":        &cfg.Database.DSN,
+		"JWT_KEY":       &cfg.JWT.Key,
+		"JWT_ISSUER":    &cfg.JWT.Issuer,
+		"SMTP_HOST":     &cfg.SMTP.Host,
+		"SMTP_USERNAME": &cfg.SMTP.Username,
+		"SMTP_PASSWORD": &cfg.SMTP.Password,
+		"SMTP_FROM":     &cfg.SMTP.From,
+		"LISTEN_ADDR":   &cfg.Server.Addr,
+		"BASE_URL":      &cfg.Server.BaseURL,
+		"TEAM_COUNTRY":  &cfg.Game.Country,
+	}
+
+	for name, target := range strs {
+		if value, ok := os.LookupEnv(envPrefix + name); ok {
+			*target = value
+		}
+	}
+
+	ints := map[string]*int{
+		"SMTP_PORT":            &cfg.SMTP.Port,
+		"STARTING_CASH":        &cfg.Game.StartingCash,
+		"INITIAL_PLAYER_VALUE": &cfg.Game.InitialPlayerValue,
+		"SQUAD_GOALKEEPERS":    &cfg.Game.Squad.GoalKeepers,
+		"SQUAD_DEFENDERS":      &cfg.Game.Squad.Defenders,
+		"SQUAD_MIDFIELDERS":    &cfg.Game.Squad.Midfielders,
+		"SQUAD_FORWARDS":       &cfg.Game.Squad.Forwards,
+		"CONTRACT_YEARS":       &cfg.Game.ContractYears,
+	}
+
+	for name, target := range ints {
+		if value, ok := os.LookupEnv(envPrefix + name); ok {
+			parsed, err := strconv.Atoi(value)
+
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %s", envPrefix, name, value)
+			}
+
+			*target = parsed
+		}
+	}
+
+	bools := map[string]*bool{
+		"DB_AUTO_MIGRATE":           &cfg.Database.AutoMigrate,
+		"TRANSFER_WINDOWS_ENFORCED": &cfg.Game.TransferWindowsEnforced,
+	}
+
+	for name, target := range bools {
+		if value, ok := os.LookupEnv(envPrefix + name); ok {
+			parsed, err := strconv.ParseBool(value)
+
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %s", envPrefix, name, value)
+			}
+
+			*target = parsed
+		}
+	}
+
+	durations := map[string]*Duration{
+		"JWT_TTL":                  &cfg.JWT.TTL,
+		"JWT_REFRESH_TTL":          &cfg.JWT.RefreshTTL,
+		"AUCTION_INTERVAL":         &cfg.Game.AuctionInterval,
+		"MAX_AUCTION_DURATION":     &cfg.Game.MaxAuctionDuration,
+		"OFFER_TTL":                &cfg.Game.OfferTTL,
+		"LOAN_INTERVAL":            &cfg.Game.LoanInterval,
+		"TRANSFER_WINDOW_INTERVAL": &cfg.Game.TransferWindowInterval,
+		"LISTING_MAX_DURATION":     &cfg.Game.ListingMaxDuration,
+		"LISTING_INTERVAL":         &cfg.Game.ListingInterval,
+		"WAGE_PERIOD":              &cfg.Game.WagePeriod,
+		"CONTRACT_INTERVAL":        &cfg.Game.ContractInterval,
+	}
+
+	for name, target := range durations {
+		if value, ok := os.LookupEnv(envPrefix + name); ok {
+			parsed, err := time.ParseDuration(value)
+
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %s", envPrefix, name, value)
+			}
+
+			target.Duration = parsed
+		}
+	}
+
+	return nil
+}
+
+func (cfg *Config) Validate() error {
+	var problems []string
+
+	switch cfg.Database.Driver {
+	case "mysql", "sqlite":
+		if cfg.Database.DSN == "" {
+			problems = append(problems, "database dsn is required")
+		}
+	case "memory":
+	default:
+		problems = append(problems, "database driver must be one of mysql, sqlite or memory")
+	}
+
+	if cfg.Game.Country == "" {
+		problems = append(problems, "team country is required")
+	}
+
+	if cfg.Game.StartingCash < 0 || cfg.Game.InitialPlayerValue < 0 {
+		problems = append(problems, "starting cash and initial player value cannot be negative")
+	}
+
+	if cfg.Game.AuctionInterval.Duration <= 0 || cfg.Game.OfferTTL.Duration <= 0 || cfg.Game.LoanInterval.Duration <= 0 {
+		problems = append(problems, "auction interval, offer ttl and loan interval must be positive")
+	}
+
+	if cfg.Game.MaxAuctionDuration.Duration <= 0 {
+		problems = append(problems, "max auction duration must be positive")
+	}
+
+	if cfg.Game.TransferWindowInterval.Duration <= 0 {
+		problems = append(problems, "transfer window interval must be positive")
+	}
+
+	if cfg.Game.ListingMaxDuration.Duration <= 0 || cfg.Game.ListingInterval.Duration <= 0 {
+		problems = append(problems, "listing max duration and listing interval must be positive")
+	}
+
+	if cfg.Game.ContractYears <= 0 || cfg.Game.WagePeriod.Duration <= 0 || cfg.Game.ContractInterval.Duration <= 0 {
+		problems = append(problems, "contract years, wage period and contract interval must be positive")
+	}
+
+	squad := cfg.Game.Squad
+
+	if squad.GoalKeepers < 0 || squad.Defenders < 0 || squad.Midfielders < 0 || squad.Forwards < 0 || squad.Size() == 0 {
+		problems = append(problems, "squad composition must be non-negative with at least one player")
+	}
+
+	return invalid(problems)
+}
+
+func (cfg *Config) ValidateServer() error {
+	var problems []string
+
+	if len(cfg.JWT.Key) < 32 {
+		problems = append(problems, "jwt key must have at least 32 characters")
+	}
+
+	if cfg.JWT.TTL.Duration <= 0 || cfg.JWT.RefreshTTL.Duration <= 0 {
+		problems = append(problems, "jwt ttl and refresh ttl must be positive")
+	}
+
+	if cfg.SMTP.Host == "" || cfg.SMTP.Port <= 0 {
+		problems = append(problems, "smtp host and port are required")
+	}
+
+	if cfg.SMTP.From == "" {
+		problems = append(problems, "smtp from address is required")
+	}
+
+	if cfg.Server.Addr == "" {
+		problems = append(problems, "listen address is required")
+	}
+
+	if !strings.HasPrefix(cfg.Server.BaseURL, "http://") && !strings.HasPrefix(cfg.Server.BaseURL, "https://") {
+		problems = append(problems, "base url must be an http(s) url")
+	}
+
+	return invalid(problems)
+}
+
+func invalid(problems []string) error {
+	if len(problems) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+
+	return nil
+}
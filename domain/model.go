@@ -1,23 +1,47 @@
 package domain
 
+import "time"
+
 type Player struct {
-	Id          int            `json:"id"`
-	FirstName   string         `json:"firstName"`
-	LastName    string         `json:"lastName"`
-	Country     string         `json:"country"`
-	Age         uint8          `json:"age"`
-	Position    PlayerPosition `json:"position"`
-	MarketValue int            `json:"marketValue"`
-	TeamId      *int           `json:"-"`
+	Id          int              `json:"id"`
+	FirstName   string           `json:"firstName"`
+	LastName    string           `json:"lastName"`
+	Country     string           `json:"country"`
+	Age         uint8            `json:"age"`
+	Position    PlayerPosition   `json:"position"`
+	MarketValue int              `json:"marketValue"`
+	Attributes  PlayerAttributes `json:"attributes"`
+	Contract    *Contract        `json:"contract,omitempty"`
+	TeamId      *int             `json:"-"`
+}
+
+type PlayerAttributes struct {
+	Pace        int `json:"pace"`
+	Shooting    int `json:"shooting"`
+	Passing     int `json:"passing"`
+	Defending   int `json:"defending"`
+	Goalkeeping int `json:"goalkeeping"`
+	Stamina     int `json:"stamina"`
+	Potential   int `json:"potential"`
 }
 
 type Team struct {
-	Id            int      `json:"id"`
-	Name          string   `json:"name"`
-	Country       string   `json:"country"`
-	AvailableCash int      `json:"availableCash"`
-	Players       []Player `json:"players"`
-	AccountId     int      `json:"-"`
+	Id            int           `json:"id"`
+	Name          string        `json:"name"`
+	Country       string        `json:"country"`
+	AvailableCash int           `json:"availableCash"`
+	Summary       *SquadSummary `json:"summary,omitempty"`
+	Players       []Player      `json:"players"`
+	AccountId     int           `json:"-"`
+}
+
+type SquadSummary struct {
+	Value             int                    `json:"value"`
+	Players           int                    `json:"players"`
+	AverageAge        float64                `json:"averageAge"`
+	Positions         map[PlayerPosition]int `json:"positions"`
+	ListedPlayers     int                    `json:"listedPlayers"`
+	ListedAskingPrice int                    `json:"listedAskingPrice"`
 }
 
 type Account struct {
@@ -31,21 +55,564 @@ type Account struct {
 	LoginAttempts     uint8  `json:"-"`
 	Locked            bool   `json:"-"`
 	Confirmed         bool   `json:"-"`
+	Profile           string `json:"profile"`
+	TokenVersion      int    `json:"-"`
 }
 
 type User struct {
+	AccountId      int
+	Username       string
+	Profile        string
+	TokenId        string
+	TokenExpiresAt time.Time
+}
+
+const (
+	UserProfile  = "USER"
+	AdminProfile = "ADMIN"
+)
+
+type AccountLockEvent struct {
+	Id             int       `json:"id"`
+	AccountId      int       `json:"accountId"`
+	ActorAccountId *int      `json:"actorAccountId"`
+	Action         string    `json:"action"`
+	Reason         string    `json:"reason"`
+	Source         string    `json:"source"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+const (
+	LockAction   = "LOCK"
+	UnlockAction = "UNLOCK"
+)
+
+const (
+	AdminLockSource         = "ADMIN"
+	FailedLoginsLockSource  = "FAILED_LOGINS"
+	SelfServiceLockSource   = "SELF_SERVICE"
+	PasswordResetLockSource = "PASSWORD_RESET"
+)
+
+type AccountToken struct {
+	Id        int
 	AccountId int
-	Username  string
+	Purpose   string
+	Token     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+const (
+	UnlockAccountPurpose = "UNLOCK_ACCOUNT"
+	PasswordResetPurpose = "PASSWORD_RESET"
+)
+
+type RefreshToken struct {
+	Id              int
+	AccountId       int
+	Family          string
+	Token           string
+	AccessTokenId   string
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	UsedAt          *time.Time
+	RevokedAt       *time.Time
+	CreatedAt       time.Time
+}
+
+type AdminAccount struct {
+	Id            int    `json:"id"`
+	Username      string `json:"email"`
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	Profile       string `json:"profile"`
+	Confirmed     bool   `json:"confirmed"`
+	Locked        bool   `json:"locked"`
+	LoginAttempts uint8  `json:"loginAttempts"`
+	TeamId        *int   `json:"teamId"`
+}
+
+type AdminTeam struct {
+	Id            int    `json:"id"`
+	Name          string `json:"name"`
+	Country       string `json:"country"`
+	AvailableCash int    `json:"availableCash"`
+	AccountId     int    `json:"accountId"`
+}
+
+type AdminPlayer struct {
+	Id          int              `json:"id"`
+	FirstName   string           `json:"firstName"`
+	LastName    string           `json:"lastName"`
+	Country     string           `json:"country"`
+	Age         uint8            `json:"age"`
+	Position    PlayerPosition   `json:"position"`
+	MarketValue int              `json:"marketValue"`
+	Attributes  PlayerAttributes `json:"attributes"`
+	TeamId      *int             `json:"teamId"`
 }
 
 type Transfer struct {
-	Id              int    `json:"id"`
-	Player          Player `json:"player"`
-	MarketValue     int    `json:"marketValue"`
-	AskedPrice      int    `json:"askedPrice"`
-	TransferredFrom *Team  `json:"-"`
-	TransferredTo   *Team  `json:"-"`
-	Transferred     bool   `json:"-"`
+	Id              int        `json:"id"`
+	Player          Player     `json:"player"`
+	TeamName        string     `json:"teamName"`
+	MarketValue     int        `json:"marketValue"`
+	AskedPrice      int        `json:"askedPrice"`
+	Status          string     `json:"status"`
+	Mode            string     `json:"mode"`
+	Auction         *Auction   `json:"auction,omitempty"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	TransferredFrom *Team      `json:"-"`
+	TransferredTo   *Team      `json:"-"`
+	Transferred     bool       `json:"-"`
+}
+
+const (
+	TransferListed      = "LISTED"
+	TransferTransferred = "TRANSFERRED"
+	TransferWithdrawn   = "WITHDRAWN"
+	TransferExpired     = "EXPIRED"
+)
+
+const (
+	TransferFixedPrice    = "FIXED_PRICE"
+	TransferAuction       = "AUCTION"
+	TransferDirectOffer   = "DIRECT_OFFER"
+	TransferLoanOption    = "LOAN_BUY_OPTION"
+	TransferSwap          = "SWAP"
+	TransferReleaseClause = "RELEASE_CLAUSE"
+)
+
+type Auction struct {
+	Deadline   time.Time `json:"deadline"`
+	Sealed     bool      `json:"sealed"`
+	HighestBid *int      `json:"highestBid,omitempty"`
+	Bids       int       `json:"bids"`
+}
+
+type Bid struct {
+	Id         int            `json:"id"`
+	TransferId int            `json:"transferId"`
+	TeamId     int            `json:"teamId"`
+	TeamName   string         `json:"teamName"`
+	Amount     int            `json:"amount"`
+	Status     string         `json:"status"`
+	PlacedAt   time.Time      `json:"placedAt"`
+	Contract   *ContractTerms `json:"contract,omitempty"`
+}
+
+const (
+	BidActive   = "ACTIVE"
+	BidWon      = "WON"
+	BidRefunded = "REFUNDED"
+)
+
+type Offer struct {
+	Id             int            `json:"id"`
+	PlayerId       int            `json:"playerId"`
+	PlayerName     string         `json:"playerName"`
+	BuyerId        int            `json:"buyerId"`
+	BuyerName      string         `json:"buyerName"`
+	SellerId       int            `json:"sellerId"`
+	SellerName     string         `json:"sellerName"`
+	Amount         int            `json:"amount"`
+	Status         string         `json:"status"`
+	AwaitingTeamId int            `json:"awaitingTeamId"`
+	ExpiresAt      time.Time      `json:"expiresAt"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+	TransferId     *int           `json:"transferId,omitempty"`
+	Contract       *ContractTerms `json:"contract,omitempty"`
+	Events         []OfferEvent   `json:"events,omitempty"`
+}
+
+type OfferEvent struct {
+	TeamId    int       `json:"teamId"`
+	Action    string    `json:"action"`
+	Amount    int       `json:"amount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+const (
+	OfferOpen      = "OPEN"
+	OfferAccepted  = "ACCEPTED"
+	OfferRejected  = "REJECTED"
+	OfferWithdrawn = "WITHDRAWN"
+	OfferExpired   = "EXPIRED"
+	OfferCancelled = "CANCELLED"
+)
+
+const (
+	OfferActionOffer    = "OFFER"
+	OfferActionCounter  = "COUNTER"
+	OfferActionAccept   = "ACCEPT"
+	OfferActionReject   = "REJECT"
+	OfferActionWithdraw = "WITHDRAW"
+)
+
+type Loan struct {
+	Id             int        `json:"id"`
+	PlayerId       int        `json:"playerId"`
+	PlayerName     string     `json:"playerName"`
+	LenderId       int        `json:"lenderId"`
+	LenderName     string     `json:"lenderName"`
+	BorrowerId     int        `json:"borrowerId"`
+	BorrowerName   string     `json:"borrowerName"`
+	Fee            int        `json:"fee"`
+	DurationDays   *int       `json:"durationDays,omitempty"`
+	DurationRounds *int       `json:"durationRounds,omitempty"`
+	RoundsPlayed   int        `json:"roundsPlayed"`
+	BuyOptionPrice *int       `json:"buyOptionPrice,omitempty"`
+	Status         string     `json:"status"`
+	ProposedAt     time.Time  `json:"proposedAt"`
+	StartedAt      *time.Time `json:"startedAt,omitempty"`
+	EndsAt         *time.Time `json:"endsAt,omitempty"`
+	EndedAt        *time.Time `json:"endedAt,omitempty"`
+	TransferId     *int       `json:"transferId,omitempty"`
+}
+
+const (
+	LoanProposed  = "PROPOSED"
+	LoanActive    = "ACTIVE"
+	LoanReturned  = "RETURNED"
+	LoanBought    = "BOUGHT"
+	LoanRejected  = "REJECTED"
+	LoanWithdrawn = "WITHDRAWN"
+	LoanCancelled = "CANCELLED"
+)
+
+type Swap struct {
+	Id             int          `json:"id"`
+	ProposerId     int          `json:"proposerId"`
+	ProposerName   string       `json:"proposerName"`
+	ReceiverId     int          `json:"receiverId"`
+	ReceiverName   string       `json:"receiverName"`
+	CashAdjustment int          `json:"cashAdjustment"`
+	Status         string       `json:"status"`
+	ProposedAt     time.Time    `json:"proposedAt"`
+	RespondedAt    *time.Time   `json:"respondedAt,omitempty"`
+	Players        []SwapPlayer `json:"players"`
+}
+
+type SwapPlayer struct {
+	PlayerId    int    `json:"playerId"`
+	PlayerName  string `json:"playerName"`
+	FromTeamId  int    `json:"fromTeamId"`
+	MarketValue int    `json:"marketValue"`
+	TransferId  *int   `json:"transferId,omitempty"`
+}
+
+const (
+	SwapProposed  = "PROPOSED"
+	SwapCompleted = "COMPLETED"
+	SwapRejected  = "REJECTED"
+	SwapWithdrawn = "WITHDRAWN"
+	SwapCancelled = "CANCELLED"
+)
+
+type TransferWindow struct {
+	Id               int        `json:"id"`
+	Name             string     `json:"name"`
+	OpensAt          *time.Time `json:"opensAt,omitempty"`
+	ClosesAt         *time.Time `json:"closesAt,omitempty"`
+	LeagueId         *int       `json:"leagueId,omitempty"`
+	LeagueRound      int        `json:"leagueRound"`
+	LeagueStatus     string     `json:"-"`
+	OpensAfterRound  *int       `json:"opensAfterRound,omitempty"`
+	ClosesAfterRound *int       `json:"closesAfterRound,omitempty"`
+	ListingPolicy    string     `json:"listingPolicy"`
+	ManualState      *string    `json:"manualState,omitempty"`
+	Open             bool       `json:"open"`
+	ClosedAt         *time.Time `json:"closedAt,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+}
+
+const (
+	WindowCarryOver = "CARRY_OVER"
+	WindowExpire    = "EXPIRE"
+)
+
+const (
+	WindowOpen   = "OPEN"
+	WindowClosed = "CLOSED"
+)
+
+type TransferWindowStatus struct {
+	Enforced bool             `json:"enforced"`
+	Open     bool             `json:"open"`
+	Windows  []TransferWindow `json:"windows"`
+}
+
+type Contract struct {
+	Id            int        `json:"id"`
+	PlayerId      int        `json:"playerId"`
+	Wage          int        `json:"wage"`
+	ReleaseClause *int       `json:"releaseClause"`
+	StartsAt      time.Time  `json:"startsAt"`
+	EndsAt        time.Time  `json:"endsAt"`
+	NextWageAt    time.Time  `json:"nextWageAt"`
+	Status        string     `json:"status"`
+	EndedAt       *time.Time `json:"endedAt,omitempty"`
+}
+
+const (
+	ContractActive     = "ACTIVE"
+	ContractRenewed    = "RENEWED"
+	ContractReplaced   = "REPLACED"
+	ContractExpired    = "EXPIRED"
+	ContractTerminated = "TERMINATED"
+)
+
+type ContractTerms struct {
+	Wage          int  `json:"wage"`
+	Years         int  `json:"years"`
+	ReleaseClause *int `json:"releaseClause"`
+}
+
+type ContractDemand struct {
+	PlayerId int `json:"playerId"`
+	Wage     int `json:"wage"`
+	MaxYears int `json:"maxYears"`
+}
+
+type FreeAgent struct {
+	Player     Player `json:"player"`
+	SigningFee int    `json:"signingFee"`
+	Wage       int    `json:"wage"`
+	MaxYears   int    `json:"maxYears"`
+}
+
+type FreeAgentFilter struct {
+	Country        string
+	PlayerName     string
+	Position       PlayerPosition
+	MinAge         int
+	MaxAge         int
+	MinMarketValue int
+	MaxMarketValue int
+	Limit          int
+}
+
+type LedgerTransaction struct {
+	Id            int       `json:"id"`
+	Category      string    `json:"category"`
+	ReferenceType string    `json:"referenceType"`
+	ReferenceId   *int      `json:"referenceId"`
+	FromAccount   string    `json:"fromAccount"`
+	FromTeamId    *int      `json:"fromTeamId"`
+	ToAccount     string    `json:"toAccount"`
+	ToTeamId      *int      `json:"toTeamId"`
+	Amount        int       `json:"amount"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+const (
+	LedgerTeam     = "TEAM"
+	LedgerEscrow   = "ESCROW"
+	LedgerExternal = "EXTERNAL"
+)
+
+const (
+	LedgerOpeningBalance  = "OPENING_BALANCE"
+	LedgerTransferFee     = "TRANSFER_FEE"
+	LedgerBidReserved     = "BID_RESERVED"
+	LedgerBidReleased     = "BID_RELEASED"
+	LedgerLoanFee         = "LOAN_FEE"
+	LedgerSwapAdjustment  = "SWAP_ADJUSTMENT"
+	LedgerAdminAdjustment = "ADMIN_ADJUSTMENT"
+	LedgerWages           = "WAGES"
+	LedgerSigningFee      = "SIGNING_FEE"
+)
+
+const (
+	ReferenceTeam     = "TEAM"
+	ReferenceTransfer = "TRANSFER"
+	ReferenceLoan     = "LOAN"
+	ReferenceSwap     = "SWAP"
+	ReferenceContract = "CONTRACT"
+)
+
+type FinanceEntry struct {
+	Id            int       `json:"id"`
+	Category      string    `json:"category"`
+	ReferenceType string    `json:"referenceType"`
+	ReferenceId   *int      `json:"referenceId"`
+	CounterpartId *int      `json:"counterpartTeamId"`
+	Amount        int       `json:"amount"`
+	Balance       int       `json:"balance"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type CategoryTotal struct {
+	Credits int `json:"credits"`
+	Debits  int `json:"debits"`
+	Net     int `json:"net"`
+}
+
+type TeamFinances struct {
+	TeamId        int                      `json:"teamId"`
+	AvailableCash int                      `json:"availableCash"`
+	Balance       int                      `json:"balance"`
+	Escrow        int                      `json:"escrow"`
+	Totals        map[string]CategoryTotal `json:"totals"`
+	Entries       []FinanceEntry           `json:"entries"`
+}
+
+type LedgerBalance struct {
+	TeamId        int    `json:"teamId"`
+	TeamName      string `json:"teamName"`
+	AvailableCash int    `json:"availableCash"`
+	LedgerCash    int    `json:"ledgerCash"`
+	ActiveBids    int    `json:"activeBids"`
+	LedgerEscrow  int    `json:"ledgerEscrow"`
+}
+
+type TransferFilter struct {
+	Country        string
+	TeamName       string
+	PlayerName     string
+	Position       PlayerPosition
+	Mode           string
+	MinAge         int
+	MaxAge         int
+	MinAskedPrice  int
+	MaxAskedPrice  int
+	MinMarketValue int
+	MaxMarketValue int
+	SortBy         string
+	Descending     bool
+	Cursor         string
+	After          *TransferCursor
+	Limit          int
+}
+
+type TransferHistoryFilter struct {
+	TeamId   int
+	PlayerId int
+	Status   string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+}
+
+type CompletedTransfer struct {
+	Id            int        `json:"id"`
+	PlayerId      int        `json:"playerId"`
+	PlayerName    string     `json:"playerName"`
+	FromTeamId    *int       `json:"fromTeamId"`
+	FromTeamName  string     `json:"fromTeamName"`
+	ToTeamId      *int       `json:"toTeamId"`
+	ToTeamName    string     `json:"toTeamName"`
+	Price         int        `json:"price"`
+	ValueBefore   int        `json:"valueBefore"`
+	ValueAfter    *int       `json:"valueAfter"`
+	TransferredAt *time.Time `json:"transferredAt"`
+	ExpiredAt     *time.Time `json:"expiredAt,omitempty"`
+	WithdrawnAt   *time.Time `json:"withdrawnAt,omitempty"`
+	Status        string     `json:"status"`
+}
+
+type PlayerCareer struct {
+	Player    Player              `json:"player"`
+	Transfers []CompletedTransfer `json:"transfers"`
+}
+
+type TransferCursor struct {
+	Value int
+	Id    int
+}
+
+type TransferPage struct {
+	Transfers  []Transfer `json:"transfers"`
+	Total      int        `json:"total"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
+
+type Match struct {
+	Id           int            `json:"id"`
+	HomeTeamId   *int           `json:"homeTeamId"`
+	HomeTeamName string         `json:"homeTeamName"`
+	AwayTeamId   *int           `json:"awayTeamId"`
+	AwayTeamName string         `json:"awayTeamName"`
+	HomeGoals    int            `json:"homeGoals"`
+	AwayGoals    int            `json:"awayGoals"`
+	Seed         int64          `json:"seed"`
+	PlayedAt     time.Time      `json:"playedAt"`
+	Events       []MatchEvent   `json:"events,omitempty"`
+	Ratings      []PlayerRating `json:"ratings,omitempty"`
+}
+
+type MatchEvent struct {
+	Minute          int    `json:"minute"`
+	Type            string `json:"type"`
+	TeamId          int    `json:"teamId"`
+	PlayerId        int    `json:"playerId"`
+	RelatedPlayerId *int   `json:"relatedPlayerId,omitempty"`
+}
+
+const (
+	GoalEvent         = "GOAL"
+	YellowCardEvent   = "YELLOW_CARD"
+	RedCardEvent      = "RED_CARD"
+	SubstitutionEvent = "SUBSTITUTION"
+)
+
+type PlayerRating struct {
+	PlayerId      int            `json:"playerId"`
+	TeamId        int            `json:"teamId"`
+	PlayerName    string         `json:"playerName"`
+	Position      PlayerPosition `json:"position"`
+	Started       bool           `json:"started"`
+	MinutesPlayed int            `json:"minutesPlayed"`
+	Rating        float64        `json:"rating"`
+}
+
+type League struct {
+	Id           int       `json:"id"`
+	Name         string    `json:"name"`
+	Country      string    `json:"country"`
+	Status       string    `json:"status"`
+	CurrentRound int       `json:"currentRound"`
+	Rounds       int       `json:"rounds"`
+	Seed         int64     `json:"-"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+const (
+	LeagueOpen     = "OPEN"
+	LeagueRunning  = "RUNNING"
+	LeagueFinished = "FINISHED"
+)
+
+type Fixture struct {
+	Id           int        `json:"id"`
+	LeagueId     int        `json:"leagueId"`
+	Round        int        `json:"round"`
+	HomeTeamId   int        `json:"homeTeamId"`
+	HomeTeamName string     `json:"homeTeamName"`
+	AwayTeamId   int        `json:"awayTeamId"`
+	AwayTeamName string     `json:"awayTeamName"`
+	MatchId      *int       `json:"matchId"`
+	HomeGoals    *int       `json:"homeGoals"`
+	AwayGoals    *int       `json:"awayGoals"`
+	PlayedAt     *time.Time `json:"playedAt"`
+	Match        *Match     `json:"-"`
+}
+
+type Standing struct {
+	Position       int    `json:"position"`
+	TeamId         int    `json:"teamId"`
+	TeamName       string `json:"teamName"`
+	Played         int    `json:"played"`
+	Won            int    `json:"won"`
+	Drawn          int    `json:"drawn"`
+	Lost           int    `json:"lost"`
+	GoalsFor       int    `json:"goalsFor"`
+	GoalsAgainst   int    `json:"goalsAgainst"`
+	GoalDifference int    `json:"goalDifference"`
+	Points         int    `json:"points"`
+	Form           string `json:"form"`
 }
 
 type PlayerPosition string
@@ -56,3 +623,12 @@ const (
 	Midfielder                = "MF"
 	Forward                   = "FW"
 )
+
+func IsValidPosition(position PlayerPosition) bool {
+	switch position {
+	case GoalKeeper, Defender, Midfielder, Forward:
+		return true
+	}
+
+	return false
+}
@@ -1,6 +1,6 @@
 module github.com/giancarlobastos/soccer-manager-api
 
-go 1.15
+go 1.16
 
 require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
@@ -8,5 +8,6 @@ require (
 	github.com/go-sql-driver/mysql v1.5.0
 	github.com/google/uuid v1.1.1
 	github.com/gorilla/mux v1.8.0
+	github.com/mattn/go-sqlite3 v1.14.6
 	golang.org/x/crypto v0.0.0-20200820211705-5c72a883971a
 )
@@ -0,0 +1,75 @@
+package league
+
+const formLength = 5
+
+type Pairing struct {
+	HomeTeamId int
+	AwayTeamId int
+}
+
+func DoubleRoundRobin(teamIds []int) [][]Pairing {
+	ids := append([]int(nil), teamIds...)
+
+	if len(ids)%2 == 1 {
+		ids = append(ids, 0)
+	}
+
+	n := len(ids)
+	var firstLeg [][]Pairing
+
+	for round := 0; round < n-1; round++ {
+		var pairings []Pairing
+
+		for i := 0; i < n/2; i++ {
+			home, away := ids[i], ids[n-1-i]
+
+			if (i == 0 && round%2 == 1) || (i > 0 && i%2 == 1) {
+				home, away = away, home
+			}
+
+			if home != 0 && away != 0 {
+				pairings = append(pairings, Pairing{HomeTeamId: home, AwayTeamId: away})
+			}
+		}
+
+		firstLeg = append(firstLeg, pairings)
+		last := ids[n-1]
+		copy(ids[2:], ids[1:n-1])
+		ids[1] = last
+	}
+
+	rounds := firstLeg
+
+	for _, pairings := range firstLeg {
+		var returnLeg []Pairing
+
+		for _, pairing := range pairings {
+			returnLeg = append(returnLeg, Pairing{HomeTeamId: pairing.AwayTeamId, AwayTeamId: pairing.HomeTeamId})
+		}
+
+		rounds = append(rounds, returnLeg)
+	}
+
+	return rounds
+}
+
+func Result(goalsFor, goalsAgainst int) (result string, points int) {
+	switch {
+	case goalsFor > goalsAgainst:
+		return "W", 3
+	case goalsFor < goalsAgainst:
+		return "L", 0
+	default:
+		return "D", 1
+	}
+}
+
+func AppendForm(form, result string) string {
+	form += result
+
+	if len(form) > formLength {
+		form = form[len(form)-formLength:]
+	}
+
+	return form
+}
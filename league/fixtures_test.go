@@ -0,0 +1,70 @@
+package league
+
+import "testing"
+
+func TestDoubleRoundRobin(t *testing.T) {
+	for teams := 2; teams <= 9; teams++ {
+		var teamIds []int
+
+		for i := 1; i <= teams; i++ {
+			teamIds = append(teamIds, i*10)
+		}
+
+		rounds := DoubleRoundRobin(teamIds)
+		expectedRounds := 2 * (teams - 1)
+
+		if teams%2 == 1 {
+			expectedRounds = 2 * teams
+		}
+
+		if len(rounds) != expectedRounds {
+			t.Fatalf("%d teams: expected %d rounds, got %d", teams, expectedRounds, len(rounds))
+		}
+
+		meetings := map[Pairing]int{}
+		homeGames := map[int]int{}
+
+		for r, pairings := range rounds {
+			playing := map[int]bool{}
+
+			for _, pairing := range pairings {
+				if playing[pairing.HomeTeamId] || playing[pairing.AwayTeamId] || pairing.HomeTeamId == pairing.AwayTeamId {
+					t.Fatalf("%d teams: team plays twice in round %d: %v", teams, r+1, pairings)
+				}
+
+				playing[pairing.HomeTeamId] = true
+				playing[pairing.AwayTeamId] = true
+				meetings[pairing]++
+				homeGames[pairing.HomeTeamId]++
+			}
+		}
+
+		for _, home := range teamIds {
+			if homeGames[home] != teams-1 {
+				t.Fatalf("%d teams: team %d plays %d home games, expected %d", teams, home, homeGames[home], teams-1)
+			}
+
+			for _, away := range teamIds {
+				if home != away && meetings[Pairing{HomeTeamId: home, AwayTeamId: away}] != 1 {
+					t.Fatalf("%d teams: %d should host %d exactly once", teams, home, away)
+				}
+			}
+		}
+	}
+}
+
+func TestAppendForm(t *testing.T) {
+	form := ""
+
+	for _, result := range []string{"W", "W", "D", "L", "W", "L"} {
+		form = AppendForm(form, result)
+	}
+
+	if form != "WDLWL" {
+		t.Fatalf("expected the last five results, got %s", form)
+	}
+
+	if result, points := Result(2, 2); result != "D" || points != 1 {
+		t.Fatalf("expected a draw worth 1 point, got %s %d", result, points)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/service"
+)
+
+func runLedgerCheckCommand(cfg config.DatabaseConfig) error {
+	if cfg.Driver == "memory" {
+		return errors.New("the memory driver keeps no ledger to check")
+	}
+
+	openDatabase(cfg)
+	repositories := openRepositories(cfg.Driver)
+	financeService := service.NewFinanceService(repositories.Ledger, repositories.Teams)
+	drifted, err := financeService.CheckLedger()
+
+	if err != nil {
+		return err
+	}
+
+	if len(drifted) == 0 {
+		fmt.Println("ledger is consistent with team balances")
+		return nil
+	}
+
+	for _, balance := range drifted {
+		fmt.Printf("team %d (%s): available cash %d, ledger cash %d, drift %d; active bids %d, ledger escrow %d, drift %d\n",
+			balance.TeamId, balance.TeamName, balance.AvailableCash, balance.LedgerCash, balance.AvailableCash-balance.LedgerCash,
+			balance.ActiveBids, balance.LedgerEscrow, balance.ActiveBids-balance.LedgerEscrow)
+	}
+
+	return fmt.Errorf("ledger drift found for %d teams", len(drifted))
+}
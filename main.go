@@ -2,45 +2,172 @@ package main
 
 import (
 	"database/sql"
+	"flag"
 	"github.com/giancarlobastos/soccer-manager-api/api"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/names"
 	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/repository/memory"
+	"github.com/giancarlobastos/soccer-manager-api/repository/mysql"
+	"github.com/giancarlobastos/soccer-manager-api/repository/sqlite"
 	"github.com/giancarlobastos/soccer-manager-api/service"
+	"github.com/giancarlobastos/soccer-manager-api/squad"
 	_ "github.com/go-sql-driver/mysql"
-	_ "golang.org/x/crypto/bcrypt"
+	_ "github.com/mattn/go-sqlite3"
+	"log"
+	"os"
 )
 
 var (
-	database *sql.DB
-	router   *api.Router
+	database   *sql.DB
+	router     *api.Router
+	schedulers []*service.Scheduler
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("SOCCER_MANAGER_CONFIG"), "path to an optional JSON configuration file")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if flag.Arg(0) == "migrate" {
+		err = runMigrateCommand(cfg.Database, flag.Args()[1:])
+		destroy()
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if flag.Arg(0) == "ledger-check" {
+		err = runLedgerCheckCommand(cfg.Database)
+		destroy()
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if err = cfg.ValidateServer(); err != nil {
+		log.Fatal(err)
+	}
+
+	initialize(cfg)
 	defer destroy()
-	router.Start(":8080")
+	router.Start(cfg.Server.Addr)
 }
 
-func init() {
-	var err error
-	database, err = sql.Open("mysql", "root:secret@tcp(mysql:3306)/soccermanager")
+func initialize(cfg *config.Config) {
+	if cfg.Database.Driver != "memory" {
+		openDatabase(cfg.Database)
+	}
+
+	if cfg.Database.Driver != "memory" && cfg.Database.AutoMigrate {
+		if err := migrateUp(cfg.Database.Driver); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	repositories := openRepositories(cfg.Database.Driver)
+
+	playerRepository := repositories.Players
+	teamRepository := repositories.Teams
+	accountRepository := repositories.Accounts
+	transferRepository := repositories.Transfers
+	accountTokenRepository := repositories.AccountTokens
+	sessionRepository := repositories.Sessions
+
+	nameGenerator, err := names.Load()
 
 	if err != nil {
-		panic(err.Error())
+		log.Fatal(err)
 	}
 
-	playerRepository := repository.NewPlayerRepository(database)
-	teamRepository := repository.NewTeamRepository(database)
-	accountRepository := repository.NewAccountRepository(database, teamRepository, playerRepository)
-	transferRepository := repository.NewTransferRepository(database)
+	squadGenerator := squad.NewGenerator(cfg.Game, nameGenerator)
+	emailService := service.NewEmailService(cfg.SMTP, cfg.Server.BaseURL)
+	accountService := service.NewAccountService(accountRepository, teamRepository, playerRepository, accountTokenRepository, sessionRepository, emailService, squadGenerator, cfg.Game)
+	sessionService := service.NewSessionService(sessionRepository, cfg.JWT.RefreshTTL.Duration)
+	playerService := service.NewPlayerService(playerRepository, repositories.Contracts)
+	teamService := service.NewTeamService(teamRepository, playerRepository, repositories.Contracts)
+	windowService := service.NewTransferWindowService(repositories.Windows, transferRepository, repositories.Leagues, cfg.Game)
+	transferService := service.NewTransferService(transferRepository, playerRepository, teamRepository, windowService, cfg.Game)
+	adminService := service.NewAdminService(accountService, accountRepository, teamRepository, playerRepository, transferRepository, cfg.Game)
+	matchService := service.NewMatchService(repositories.Matches, teamRepository, playerRepository)
+	leagueService := service.NewLeagueService(repositories.Leagues, teamRepository, matchService)
+	offerService := service.NewOfferService(repositories.Offers, playerRepository, teamRepository, windowService, cfg.Game)
+	loanService := service.NewLoanService(repositories.Loans, playerRepository, teamRepository, windowService, cfg.Game)
+	swapService := service.NewSwapService(repositories.Swaps, playerRepository, teamRepository, windowService, cfg.Game)
+	financeService := service.NewFinanceService(repositories.Ledger, teamRepository)
+	contractService := service.NewContractService(repositories.Contracts, playerRepository, teamRepository, windowService, cfg.Game)
+	freeAgentService := service.NewFreeAgentService(playerRepository, repositories.Contracts, teamRepository, cfg.Game)
 
-	accountService := service.NewAccountService(accountRepository, teamRepository, playerRepository)
-	playerService := service.NewPlayerService(playerRepository)
-	teamService := service.NewTeamService(teamRepository, playerRepository)
-	transferService := service.NewTransferService(transferRepository, playerRepository, teamRepository)
+	schedulers = []*service.Scheduler{
+		service.NewScheduler(service.Task{Report: "settled %d auctions", Run: transferService.SettleDueAuctions}, cfg.Game.AuctionInterval.Duration),
+		service.NewScheduler(service.Task{Report: "returned %d loaned players", Run: loanService.ReturnDueLoans}, cfg.Game.LoanInterval.Duration),
+		service.NewScheduler(service.Task{Report: "closed %d transfer windows", Run: windowService.CloseDueWindows}, cfg.Game.TransferWindowInterval.Duration),
+		service.NewScheduler(service.Task{Report: "expired %d listings", Run: transferService.ExpireDueListings}, cfg.Game.ListingInterval.Duration),
+		service.NewScheduler(service.Task{Report: "paid %d wages", Run: contractService.PayDueWages}, cfg.Game.ContractInterval.Duration),
+		service.NewScheduler(service.Task{Report: "released %d players with expired contracts", Run: contractService.ExpireDueContracts}, cfg.Game.ContractInterval.Duration),
+	}
+
+	for _, scheduler := range schedulers {
+		scheduler.Start()
+	}
 
-	router = api.NewRouter(accountService, teamService, playerService, transferService)
+	router = api.NewRouter(cfg.JWT, accountService, sessionService, teamService, playerService, transferService, adminService, matchService, leagueService, offerService, loanService, swapService, windowService, financeService, contractService, freeAgentService)
+}
+
+func openDatabase(cfg config.DatabaseConfig) {
+	driverName := cfg.Driver
+
+	if driverName == "sqlite" {
+		driverName = "sqlite3"
+	}
+
+	dsn := cfg.DSN
+	var err error
+
+	if driverName == "mysql" {
+		if dsn, err = mysql.DSN(dsn); err != nil {
+			panic(err.Error())
+		}
+	}
+
+	database, err = sql.Open(driverName, dsn)
+
+	if err != nil {
+		panic(err.Error())
+	}
+}
+
+func openRepositories(driver string) repository.Repositories {
+	switch driver {
+	case "memory":
+		return memory.NewRepositories()
+	case "sqlite":
+		return sqlite.NewRepositories(database)
+	default:
+		return mysql.NewRepositories(database)
+	}
 }
 
 func destroy() {
+	for _, scheduler := range schedulers {
+		scheduler.Stop()
+	}
+
+	if database == nil {
+		return
+	}
+
 	err := database.Close()
 
 	if err != nil {
@@ -0,0 +1,436 @@
+package match
+
+import (
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	LineUpSize       = 11
+	benchSize        = 7
+	maxSubstitutions = 3
+	matchMinutes     = 90
+
+	goalsPerMinute     = 1.35 / matchMinutes
+	homeAdvantage      = 1.1
+	yellowCardChance   = 0.016
+	redCardChance      = 0.0005
+	assistChance       = 0.7
+	baseRating         = 6.0
+	minRating          = 3.0
+	maxRating          = 10.0
+	strengthElasticity = 0.7
+	forfeitGoals       = 3
+)
+
+var formation = []struct {
+	position domain.PlayerPosition
+	count    int
+}{
+	{domain.GoalKeeper, 1},
+	{domain.Defender, 4},
+	{domain.Midfielder, 4},
+	{domain.Forward, 2},
+}
+
+var (
+	attackWeights  = map[domain.PlayerPosition]float64{domain.GoalKeeper: 0, domain.Defender: 0.1, domain.Midfielder: 0.3, domain.Forward: 0.6}
+	defenseWeights = map[domain.PlayerPosition]float64{domain.GoalKeeper: 0.8, domain.Defender: 0.35, domain.Midfielder: 0.15, domain.Forward: 0.05}
+	scorerWeights  = map[domain.PlayerPosition]float64{domain.GoalKeeper: 0, domain.Defender: 1, domain.Midfielder: 3, domain.Forward: 6}
+	assistWeights  = map[domain.PlayerPosition]float64{domain.GoalKeeper: 0.1, domain.Defender: 1, domain.Midfielder: 4, domain.Forward: 3}
+	foulWeights    = map[domain.PlayerPosition]float64{domain.GoalKeeper: 0.3, domain.Defender: 3, domain.Midfielder: 2, domain.Forward: 1}
+)
+
+type participant struct {
+	player    domain.Player
+	rating    float64
+	yellow    bool
+	sentOff   bool
+	started   bool
+	minuteIn  int
+	minuteOut int
+}
+
+type side struct {
+	team        domain.Team
+	onPitch     []*participant
+	bench       []*participant
+	played      []*participant
+	subMinutes  []int
+	goals       int
+	substitutes int
+}
+
+func Simulate(home, away domain.Team, seed int64) (*domain.Match, error) {
+	rng := rand.New(rand.NewSource(seed))
+	homeSide, err := newSide(home, rng)
+
+	if err != nil {
+		return nil, err
+	}
+
+	awaySide, err := newSide(away, rng)
+
+	if err != nil {
+		return nil, err
+	}
+
+	match := &domain.Match{
+		HomeTeamId:   &home.Id,
+		HomeTeamName: home.Name,
+		AwayTeamId:   &away.Id,
+		AwayTeamName: away.Name,
+		Seed:         seed,
+	}
+
+	sides := []*side{homeSide, awaySide}
+
+	for minute := 1; minute <= matchMinutes; minute++ {
+		for i, s := range sides {
+			opponent := sides[1-i]
+			chance := goalsPerMinute * math.Pow(s.attack()/opponent.defense(), strengthElasticity)
+
+			if i == 0 {
+				chance *= homeAdvantage
+			}
+
+			if rng.Float64() < chance {
+				match.Events = append(match.Events, s.score(minute, opponent, rng))
+			}
+		}
+
+		for _, s := range sides {
+			match.Events = append(match.Events, s.book(minute, rng)...)
+			match.Events = append(match.Events, s.substitute(minute, rng)...)
+		}
+	}
+
+	match.HomeGoals = homeSide.goals
+	match.AwayGoals = awaySide.goals
+	homeSide.finish(awaySide)
+	awaySide.finish(homeSide)
+	match.Ratings = append(homeSide.ratings(), awaySide.ratings()...)
+
+	return match, nil
+}
+
+func CanPlay(team domain.Team) bool {
+	return len(team.Players) >= LineUpSize
+}
+
+func Forfeit(home, away domain.Team, seed int64) *domain.Match {
+	match := &domain.Match{
+		HomeTeamId:   &home.Id,
+		HomeTeamName: home.Name,
+		AwayTeamId:   &away.Id,
+		AwayTeamName: away.Name,
+		Seed:         seed,
+	}
+
+	if CanPlay(home) {
+		match.HomeGoals = forfeitGoals
+	}
+
+	if CanPlay(away) {
+		match.AwayGoals = forfeitGoals
+	}
+
+	return match
+}
+
+func newSide(team domain.Team, rng *rand.Rand) (*side, error) {
+	if !CanPlay(team) {
+		return nil, fmt.Errorf("team %s needs at least %d players", team.Name, LineUpSize)
+	}
+
+	lineUp, bench := SelectLineUp(team.Players)
+	s := &side{team: team}
+
+	for _, player := range lineUp {
+		p := &participant{player: player, rating: baseRating + rng.NormFloat64()*0.3, started: true, minuteOut: matchMinutes}
+		s.onPitch = append(s.onPitch, p)
+		s.played = append(s.played, p)
+	}
+
+	for _, player := range bench {
+		s.bench = append(s.bench, &participant{player: player, minuteOut: matchMinutes})
+	}
+
+	for i := 0; i < maxSubstitutions && i < len(s.bench); i++ {
+		s.subMinutes = append(s.subMinutes, 46+rng.Intn(43))
+	}
+
+	sort.Ints(s.subMinutes)
+	return s, nil
+}
+
+func SelectLineUp(players []domain.Player) (lineUp, bench []domain.Player) {
+	remaining := make([]domain.Player, len(players))
+	copy(remaining, players)
+
+	sort.SliceStable(remaining, func(i, j int) bool {
+		if remaining[i].MarketValue != remaining[j].MarketValue {
+			return remaining[i].MarketValue > remaining[j].MarketValue
+		}
+
+		return remaining[i].Id < remaining[j].Id
+	})
+
+	take := func(accept func(domain.Player) bool) bool {
+		for i, player := range remaining {
+			if accept(player) {
+				lineUp = append(lineUp, player)
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, slot := range formation {
+		for i := 0; i < slot.count; i++ {
+			position := slot.position
+
+			if !take(func(p domain.Player) bool { return p.Position == position }) {
+				take(func(p domain.Player) bool { return p.Position != domain.GoalKeeper || position == domain.GoalKeeper })
+			}
+		}
+	}
+
+	for len(lineUp) < LineUpSize && len(remaining) > 0 {
+		take(func(domain.Player) bool { return true })
+	}
+
+	if len(remaining) > benchSize {
+		remaining = remaining[:benchSize]
+	}
+
+	return lineUp, remaining
+}
+
+func (s *side) strength(weights map[domain.PlayerPosition]float64) float64 {
+	total := 0.0
+
+	for _, p := range s.onPitch {
+		if !p.sentOff {
+			total += weights[p.player.Position] * float64(p.player.MarketValue+1)
+		}
+	}
+
+	return total + 1
+}
+
+func (s *side) attack() float64 {
+	return s.strength(attackWeights)
+}
+
+func (s *side) defense() float64 {
+	return s.strength(defenseWeights)
+}
+
+func (s *side) active() []*participant {
+	var active []*participant
+
+	for _, p := range s.onPitch {
+		if !p.sentOff {
+			active = append(active, p)
+		}
+	}
+
+	return active
+}
+
+func pick(candidates []*participant, weights map[domain.PlayerPosition]float64, rng *rand.Rand) *participant {
+	total := 0.0
+
+	for _, p := range candidates {
+		total += weights[p.player.Position]
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	target := rng.Float64() * total
+
+	for _, p := range candidates {
+		target -= weights[p.player.Position]
+
+		if target < 0 {
+			return p
+		}
+	}
+
+	return candidates[len(candidates)-1]
+}
+
+func (s *side) score(minute int, opponent *side, rng *rand.Rand) domain.MatchEvent {
+	s.goals++
+	active := s.active()
+	scorer := pick(active, scorerWeights, rng)
+
+	if scorer == nil {
+		scorer = active[0]
+	}
+
+	scorer.rating += 1.0
+	event := domain.MatchEvent{Minute: minute, Type: domain.GoalEvent, TeamId: s.team.Id, PlayerId: scorer.player.Id}
+
+	if rng.Float64() < assistChance {
+		var teammates []*participant
+
+		for _, p := range active {
+			if p != scorer {
+				teammates = append(teammates, p)
+			}
+		}
+
+		if assistant := pick(teammates, assistWeights, rng); assistant != nil {
+			assistant.rating += 0.5
+			event.RelatedPlayerId = &assistant.player.Id
+		}
+	}
+
+	for _, p := range opponent.active() {
+		if p.player.Position == domain.GoalKeeper || p.player.Position == domain.Defender {
+			p.rating -= 0.3
+		}
+	}
+
+	return event
+}
+
+func (s *side) book(minute int, rng *rand.Rand) []domain.MatchEvent {
+	var events []domain.MatchEvent
+
+	if rng.Float64() < yellowCardChance {
+		if p := pick(s.active(), foulWeights, rng); p != nil {
+			p.rating -= 0.5
+			events = append(events, domain.MatchEvent{Minute: minute, Type: domain.YellowCardEvent, TeamId: s.team.Id, PlayerId: p.player.Id})
+
+			if p.yellow {
+				events = append(events, s.sendOff(p, minute))
+			}
+
+			p.yellow = true
+		}
+	}
+
+	if rng.Float64() < redCardChance {
+		if p := pick(s.active(), foulWeights, rng); p != nil {
+			events = append(events, s.sendOff(p, minute))
+		}
+	}
+
+	return events
+}
+
+func (s *side) sendOff(p *participant, minute int) domain.MatchEvent {
+	p.sentOff = true
+	p.minuteOut = minute
+	p.rating -= 1.5
+	return domain.MatchEvent{Minute: minute, Type: domain.RedCardEvent, TeamId: s.team.Id, PlayerId: p.player.Id}
+}
+
+func (s *side) substitute(minute int, rng *rand.Rand) []domain.MatchEvent {
+	var events []domain.MatchEvent
+
+	for s.substitutes < len(s.subMinutes) && s.subMinutes[s.substitutes] == minute {
+		s.substitutes++
+		var out *participant
+
+		for _, p := range s.active() {
+			if p.player.Position != domain.GoalKeeper && (out == nil || p.rating < out.rating) {
+				out = p
+			}
+		}
+
+		in := s.replacementFor(out)
+
+		if out == nil || in == nil {
+			continue
+		}
+
+		for i, p := range s.onPitch {
+			if p == out {
+				s.onPitch[i] = in
+			}
+		}
+
+		out.minuteOut = minute
+		in.minuteIn = minute
+		in.rating = baseRating + rng.NormFloat64()*0.3
+		s.played = append(s.played, in)
+		events = append(events, domain.MatchEvent{Minute: minute, Type: domain.SubstitutionEvent, TeamId: s.team.Id, PlayerId: out.player.Id, RelatedPlayerId: &in.player.Id})
+	}
+
+	return events
+}
+
+func (s *side) replacementFor(out *participant) *participant {
+	if out == nil {
+		return nil
+	}
+
+	index := -1
+
+	for i, p := range s.bench {
+		if p.player.Position == out.player.Position {
+			index = i
+			break
+		}
+
+		if index < 0 && p.player.Position != domain.GoalKeeper {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		return nil
+	}
+
+	in := s.bench[index]
+	s.bench = append(s.bench[:index], s.bench[index+1:]...)
+	return in
+}
+
+func (s *side) finish(opponent *side) {
+	result := 0.0
+
+	if s.goals > opponent.goals {
+		result = 0.3
+	} else if s.goals < opponent.goals {
+		result = -0.3
+	}
+
+	for _, p := range s.played {
+		p.rating += result
+
+		if opponent.goals == 0 && (p.player.Position == domain.GoalKeeper || p.player.Position == domain.Defender) {
+			p.rating += 0.5
+		}
+	}
+}
+
+func (s *side) ratings() []domain.PlayerRating {
+	ratings := make([]domain.PlayerRating, 0, len(s.played))
+
+	for _, p := range s.played {
+		rating := math.Round(math.Max(minRating, math.Min(maxRating, p.rating))*10) / 10
+		ratings = append(ratings, domain.PlayerRating{
+			PlayerId:      p.player.Id,
+			TeamId:        s.team.Id,
+			PlayerName:    p.player.FirstName + " " + p.player.LastName,
+			Position:      p.player.Position,
+			Started:       p.started,
+			MinutesPlayed: p.minuteOut - p.minuteIn,
+			Rating:        rating,
+		})
+	}
+
+	return ratings
+}
@@ -0,0 +1,192 @@
+package match
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"reflect"
+	"testing"
+)
+
+func newTeam(id int, marketValue int) domain.Team {
+	team := domain.Team{Id: id, Name: "Team"}
+	positions := []domain.PlayerPosition{
+		domain.GoalKeeper, domain.GoalKeeper,
+		domain.Defender, domain.Defender, domain.Defender, domain.Defender, domain.Defender, domain.Defender,
+		domain.Midfielder, domain.Midfielder, domain.Midfielder, domain.Midfielder, domain.Midfielder, domain.Midfielder,
+		domain.Forward, domain.Forward, domain.Forward, domain.Forward,
+	}
+
+	for i, position := range positions {
+		team.Players = append(team.Players, domain.Player{
+			Id:          id*100 + i,
+			FirstName:   "Player",
+			LastName:    string(position),
+			Position:    position,
+			MarketValue: marketValue + i,
+		})
+	}
+
+	return team
+}
+
+func TestSimulateIsDeterministic(t *testing.T) {
+	home, away := newTeam(1, 1000000), newTeam(2, 1000000)
+
+	first, err := Simulate(home, away, 42)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, _ := Simulate(home, away, 42)
+
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("expected the same seed to produce the same match")
+	}
+
+	different := false
+
+	for seed := int64(0); seed < 20 && !different; seed++ {
+		other, _ := Simulate(home, away, seed)
+		different = !reflect.DeepEqual(first.Events, other.Events)
+	}
+
+	if !different {
+		t.Fatal("expected different seeds to produce different matches")
+	}
+}
+
+func TestSimulateProducesConsistentEvents(t *testing.T) {
+	home, away := newTeam(1, 1000000), newTeam(2, 1500000)
+
+	for seed := int64(0); seed < 200; seed++ {
+		match, err := Simulate(home, away, seed)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		goals := map[int]int{}
+		substitutions := map[int]int{}
+		lastMinute := 0
+
+		for _, event := range match.Events {
+			if event.Minute < lastMinute || event.Minute < 1 || event.Minute > matchMinutes {
+				t.Fatalf("seed %d: events out of order: %+v", seed, match.Events)
+			}
+
+			lastMinute = event.Minute
+
+			switch event.Type {
+			case domain.GoalEvent:
+				goals[event.TeamId]++
+			case domain.SubstitutionEvent:
+				substitutions[event.TeamId]++
+			}
+		}
+
+		if goals[1] != match.HomeGoals || goals[2] != match.AwayGoals {
+			t.Fatalf("seed %d: score %d-%d does not match goal events %v", seed, match.HomeGoals, match.AwayGoals, goals)
+		}
+
+		if substitutions[1] > maxSubstitutions || substitutions[2] > maxSubstitutions {
+			t.Fatalf("seed %d: too many substitutions %v", seed, substitutions)
+		}
+
+		if len(match.Ratings) != 2*LineUpSize+substitutions[1]+substitutions[2] {
+			t.Fatalf("seed %d: expected a rating per player used, got %d", seed, len(match.Ratings))
+		}
+
+		for _, rating := range match.Ratings {
+			if rating.Rating < minRating || rating.Rating > maxRating || rating.MinutesPlayed < 0 || rating.MinutesPlayed > matchMinutes {
+				t.Fatalf("seed %d: invalid rating %+v", seed, rating)
+			}
+		}
+	}
+}
+
+func TestStrongerTeamWinsMoreOften(t *testing.T) {
+	strong, weak := newTeam(1, 4000000), newTeam(2, 1000000)
+	wins, losses := 0, 0
+
+	for seed := int64(0); seed < 500; seed++ {
+		match, _ := Simulate(strong, weak, seed)
+
+		if match.HomeGoals > match.AwayGoals {
+			wins++
+		} else if match.HomeGoals < match.AwayGoals {
+			losses++
+		}
+	}
+
+	if wins <= 2*losses {
+		t.Fatalf("expected the stronger team to win clearly more often, got %d wins and %d losses", wins, losses)
+	}
+}
+
+func TestSelectLineUp(t *testing.T) {
+	lineUp, bench := SelectLineUp(newTeam(1, 1000000).Players)
+	counts := map[domain.PlayerPosition]int{}
+
+	for _, player := range lineUp {
+		counts[player.Position]++
+	}
+
+	expected := map[domain.PlayerPosition]int{domain.GoalKeeper: 1, domain.Defender: 4, domain.Midfielder: 4, domain.Forward: 2}
+
+	if !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("expected a 4-4-2 line-up, got %v", counts)
+	}
+
+	if len(bench) != benchSize {
+		t.Fatalf("expected %d substitutes, got %d", benchSize, len(bench))
+	}
+
+	if lineUp[0].Id != 101 {
+		t.Fatalf("expected the most valuable goalkeeper to start, got %d", lineUp[0].Id)
+	}
+}
+
+func TestSimulateRequiresFullLineUp(t *testing.T) {
+	short := newTeam(1, 1000000)
+	short.Players = short.Players[:LineUpSize-1]
+
+	if _, err := Simulate(short, newTeam(2, 1000000), 1); err == nil {
+		t.Fatal("expected a team without enough players to be rejected")
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	full := newTeam(1, 1000000)
+	short := newTeam(2, 1000000)
+	short.Players = short.Players[:LineUpSize-1]
+
+	tests := []struct {
+		name      string
+		home      domain.Team
+		away      domain.Team
+		homeGoals int
+		awayGoals int
+	}{
+		{"home short", short, full, 0, forfeitGoals},
+		{"away short", full, short, forfeitGoals, 0},
+		{"both short", short, short, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			match := Forfeit(test.home, test.away, 7)
+
+			if match.HomeGoals != test.homeGoals || match.AwayGoals != test.awayGoals {
+				t.Fatalf("expected %d-%d, got %d-%d", test.homeGoals, test.awayGoals, match.HomeGoals, match.AwayGoals)
+			}
+
+			if *match.HomeTeamId != test.home.Id || *match.AwayTeamId != test.away.Id || match.Seed != 7 {
+				t.Fatalf("unexpected forfeit %+v", match)
+			}
+
+			if len(match.Events) != 0 || len(match.Ratings) != 0 {
+				t.Fatal("expected a forfeit without events or ratings")
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/migration"
+	"log"
+	"strings"
+)
+
+func migrateUp(dialect string) error {
+	migrator, err := migration.NewMigrator(database, dialect)
+
+	if err != nil {
+		return err
+	}
+
+	baseline, err := migrator.BaselineExisting()
+
+	if err != nil {
+		return err
+	}
+
+	if baseline > 0 {
+		log.Printf("recorded the existing schema as version %d", baseline)
+	}
+
+	applied, err := migrator.Up(0, false)
+
+	for _, m := range applied {
+		log.Printf("applied migration %s", m)
+	}
+
+	return err
+}
+
+func runMigrateCommand(cfg config.DatabaseConfig, args []string) error {
+	if cfg.Driver == "memory" {
+		return errors.New("the memory driver has no schema to migrate")
+	}
+
+	action := "up"
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	target := flags.Int("to", -1, "target version (up: latest, down: previous, baseline: 1)")
+	dryRun := flags.Bool("dry-run", false, "print the scripts that would run without applying them")
+	_ = flags.Parse(args)
+
+	openDatabase(cfg)
+	migrator, err := migration.NewMigrator(database, cfg.Driver)
+
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		if *target < 0 {
+			*target = 0
+		}
+
+		migrations, err := migrator.Up(*target, *dryRun)
+		printMigrations(migrations, *dryRun, true)
+		return err
+	case "down":
+		if *target < 0 {
+			version, err := migrator.Version()
+
+			if err != nil {
+				return err
+			}
+
+			*target = version - 1
+		}
+
+		migrations, err := migrator.Down(*target, *dryRun)
+		printMigrations(migrations, *dryRun, false)
+		return err
+	case "baseline":
+		if *target < 0 {
+			*target = 1
+		}
+
+		if *dryRun {
+			fmt.Printf("would record migrations up to version %d as applied\n", *target)
+			return nil
+		}
+
+		return migrator.Baseline(*target)
+	case "status":
+		return printStatus(migrator)
+	default:
+		return fmt.Errorf("unknown migrate action %s, expected one of up, down, baseline or status", action)
+	}
+}
+
+func printMigrations(migrations []migration.Migration, dryRun, up bool) {
+	verb, done := "revert", "reverted"
+
+	if up {
+		verb, done = "apply", "applied"
+	}
+
+	if len(migrations) == 0 {
+		fmt.Printf("nothing to %s\n", verb)
+	}
+
+	for _, m := range migrations {
+		if !dryRun {
+			fmt.Printf("%s %s\n", done, m)
+			continue
+		}
+
+		script := m.Down
+
+		if up {
+			script = m.Up
+		}
+
+		fmt.Printf("-- would %s %s\n%s\n", verb, m, strings.TrimSpace(script))
+	}
+}
+
+func printStatus(migrator *migration.Migrator) error {
+	applied, err := migrator.Applied()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = migrator.Version(); err != nil {
+		fmt.Printf("warning: %v\n", err)
+	}
+
+	for i, m := range migrator.Migrations() {
+		status := "pending"
+
+		if i < len(applied) {
+			status = "applied " + applied[i].AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		fmt.Printf("%s\t%s\n", m, status)
+	}
+
+	return nil
+}
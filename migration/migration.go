@@ -0,0 +1,133 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed mysql sqlite
+var scripts embed.FS
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+func Load(dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(scripts, dialect)
+
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %s", dialect)
+	}
+
+	byVersion := map[int]*Migration{}
+
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(scripts, path.Join(dialect, entry.Name()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("conflicting names for migration %d: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %s has no up script", migration)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration versions must be sequential, expected %d but found %s", i+1, migration)
+		}
+	}
+
+	return migrations, nil
+}
+
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote rune
+
+	for _, line := range strings.Split(script, "\n") {
+		if quote == 0 && strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+
+		for _, c := range line {
+			switch {
+			case quote != 0 && c == quote:
+				quote = 0
+			case quote == 0 && (c == '\'' || c == '"' || c == '`'):
+				quote = c
+			case quote == 0 && c == ';':
+				if statement := strings.TrimSpace(current.String()); statement != "" {
+					statements = append(statements, statement)
+				}
+
+				current.Reset()
+				continue
+			}
+
+			current.WriteRune(c)
+		}
+
+		current.WriteRune('\n')
+	}
+
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+
+	return statements
+}
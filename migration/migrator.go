@@ -0,0 +1,322 @@
+package migration
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var tableExistsQueries = map[string]string{
+	"mysql":  "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?",
+	"sqlite": "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?",
+}
+
+var accountSecurityTables = []string{"account_lock_event", "account_token", "refresh_token", "revoked_token"}
+
+var errUnversionedSchema = errors.New("the database has tables but no recorded migrations, " +
+	"run `soccer-manager-api migrate baseline -to N` with the version it was created at")
+
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    string
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, dialect string) (*Migrator, error) {
+	migrations, err := Load(dialect)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+func (m *Migrator) Applied() ([]AppliedMigration, error) {
+	exists, err := m.tableExists("schema_version")
+
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version, name, checksum, applied_at FROM schema_version ORDER BY version")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	var applied []AppliedMigration
+
+	for rows.Next() {
+		var migration AppliedMigration
+
+		if err = rows.Scan(&migration.Version, &migration.Name, &migration.Checksum, &migration.AppliedAt); err != nil {
+			return nil, err
+		}
+
+		applied = append(applied, migration)
+	}
+
+	return applied, rows.Err()
+}
+
+func (m *Migrator) Version() (int, error) {
+	applied, err := m.verify()
+	return len(applied), err
+}
+
+func (m *Migrator) Up(target int, dryRun bool) ([]Migration, error) {
+	applied, err := m.verify()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(applied) == 0 {
+		unversioned, err := m.tableExists("account")
+
+		if err != nil {
+			return nil, err
+		}
+
+		if unversioned {
+			return nil, errUnversionedSchema
+		}
+	}
+
+	if target <= 0 {
+		target = m.Latest()
+	}
+
+	if target > m.Latest() {
+		return nil, fmt.Errorf("unknown target version %d, latest is %d", target, m.Latest())
+	}
+
+	pending := m.migrations[len(applied):]
+
+	if target < len(applied) {
+		pending = nil
+	} else {
+		pending = pending[:target-len(applied)]
+	}
+
+	if dryRun || len(pending) == 0 {
+		return pending, nil
+	}
+
+	if err = m.createVersionTable(); err != nil {
+		return nil, err
+	}
+
+	for i, migration := range pending {
+		err = m.run(migration, migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.Exec("INSERT INTO schema_version(version, name, checksum, applied_at) VALUES(?, ?, ?, ?)",
+				migration.Version, migration.Name, migration.Checksum, time.Now())
+			return err
+		})
+
+		if err != nil {
+			return pending[:i], err
+		}
+	}
+
+	return pending, nil
+}
+
+func (m *Migrator) Down(target int, dryRun bool) ([]Migration, error) {
+	applied, err := m.verify()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if target < 0 || target >= len(applied) {
+		return nil, fmt.Errorf("target version must be between 0 and %d", len(applied)-1)
+	}
+
+	var reverted []Migration
+
+	for version := len(applied); version > target; version-- {
+		migration := m.migrations[version-1]
+
+		if migration.Down == "" {
+			return nil, fmt.Errorf("migration %s cannot be reverted", migration)
+		}
+
+		reverted = append(reverted, migration)
+	}
+
+	if dryRun {
+		return reverted, nil
+	}
+
+	for i, migration := range reverted {
+		err = m.run(migration, migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.Exec("DELETE FROM schema_version WHERE version = ?", migration.Version)
+			return err
+		})
+
+		if err != nil {
+			return reverted[:i], err
+		}
+	}
+
+	return reverted, nil
+}
+
+func (m *Migrator) Baseline(version int) error {
+	applied, err := m.verify()
+
+	if err != nil {
+		return err
+	}
+
+	if len(applied) > 0 {
+		return errors.New("baseline can only be recorded on a database without applied migrations")
+	}
+
+	if version <= 0 || version > m.Latest() {
+		return fmt.Errorf("baseline version must be between 1 and %d", m.Latest())
+	}
+
+	if err = m.createVersionTable(); err != nil {
+		return err
+	}
+
+	tx, err := m.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range m.migrations[:version] {
+		_, err = tx.Exec("INSERT INTO schema_version(version, name, checksum, applied_at) VALUES(?, ?, ?, ?)",
+			migration.Version, migration.Name, migration.Checksum, time.Now())
+
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) BaselineExisting() (int, error) {
+	applied, err := m.verify()
+
+	if err != nil || len(applied) > 0 {
+		return 0, err
+	}
+
+	if exists, err := m.tableExists("account"); err != nil || !exists {
+		return 0, err
+	}
+
+	found := 0
+
+	for _, table := range accountSecurityTables {
+		exists, err := m.tableExists(table)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if exists {
+			found++
+		}
+	}
+
+	version := 0
+
+	switch found {
+	case 0:
+		version = 1
+	case len(accountSecurityTables):
+		version = 2
+	default:
+		return 0, errUnversionedSchema
+	}
+
+	return version, m.Baseline(version)
+}
+
+func (m *Migrator) tableExists(table string) (bool, error) {
+	var count int
+	err := m.db.QueryRow(tableExistsQueries[m.dialect], table).Scan(&count)
+	return count > 0, err
+}
+
+func (m *Migrator) verify() ([]AppliedMigration, error) {
+	applied, err := m.Applied()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range applied {
+		if migration.Version != i+1 || migration.Version > m.Latest() {
+			return nil, fmt.Errorf("applied migration %04d_%s is unknown to this binary", migration.Version, migration.Name)
+		}
+
+		if expected := m.migrations[i]; migration.Checksum != expected.Checksum {
+			return nil, fmt.Errorf("checksum mismatch for migration %s: the script was changed after being applied", expected)
+		}
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) createVersionTable() error {
+	_, err := m.db.Exec("CREATE TABLE IF NOT EXISTS schema_version (" +
+		"version INTEGER NOT NULL, " +
+		"name VARCHAR(255) NOT NULL, " +
+		"checksum VARCHAR(64) NOT NULL, " +
+		"applied_at DATETIME NOT NULL, " +
+		"PRIMARY KEY (version))")
+
+	return err
+}
+
+func (m *Migrator) run(migration Migration, script string, record func(tx *sql.Tx) error) error {
+	tx, err := m.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range splitStatements(script) {
+		if _, err = tx.Exec(statement); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %s failed: %v", migration, err)
+		}
+	}
+
+	if err = record(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,216 @@
+package migration
+
+import (
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func newTestMigrator(t *testing.T) (*Migrator, *sql.DB) {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+	migrator, err := NewMigrator(db, "sqlite")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return migrator, db
+}
+
+func TestDialectsHaveSameMigrations(t *testing.T) {
+	mysql, err := Load("mysql")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sqlite, err := Load("sqlite")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mysql) != len(sqlite) {
+		t.Fatalf("expected the same number of migrations, got %d for mysql and %d for sqlite", len(mysql), len(sqlite))
+	}
+
+	for i := range mysql {
+		if mysql[i].Name != sqlite[i].Name {
+			t.Errorf("migration %d is named %s for mysql and %s for sqlite", i+1, mysql[i].Name, sqlite[i].Name)
+		}
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+
+	pending, err := migrator.Up(0, true)
+
+	if err != nil || len(pending) != migrator.Latest() {
+		t.Fatalf("expected every migration to be pending, got %d: %v", len(pending), err)
+	}
+
+	if applied, _ := migrator.Applied(); len(applied) != 0 {
+		t.Fatal("expected dry run to leave the database untouched")
+	}
+
+	if _, err = migrator.Up(0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, err := migrator.Version(); err != nil || version != migrator.Latest() {
+		t.Fatalf("expected version %d, got %d: %v", migrator.Latest(), version, err)
+	}
+
+	if pending, _ = migrator.Up(0, false); len(pending) != 0 {
+		t.Fatalf("expected no pending migrations, got %d", len(pending))
+	}
+
+	if _, err = db.Exec("INSERT INTO account(confirmed, locked, username) VALUES(0, 0, 'a@b.c')"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = migrator.Down(0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, _ := migrator.Version(); version != 0 {
+		t.Fatalf("expected every migration to be reverted, got version %d", version)
+	}
+
+	if _, err = db.Exec("SELECT COUNT(*) FROM account"); err == nil {
+		t.Fatal("expected account table to be dropped")
+	}
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+
+	if _, err := migrator.Up(1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec("UPDATE schema_version SET checksum = 'tampered' WHERE version = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(0, false); err == nil {
+		t.Fatal("expected checksum mismatch to be reported")
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	migrator, _ := newTestMigrator(t)
+
+	if err := migrator.Baseline(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, _ := migrator.Version(); version != 1 {
+		t.Fatalf("expected baseline version 1, got %d", version)
+	}
+
+	if err := migrator.Baseline(1); err == nil {
+		t.Fatal("expected baseline on a migrated database to be rejected")
+	}
+}
+
+func TestUpOnUnversionedDatabase(t *testing.T) {
+	initScript, err := ioutil.ReadFile(filepath.Join("testdata", "init.sqlite.sql"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original, err := Load("sqlite")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		script   string
+		baseline int
+	}{
+		{"pre-migration init.sql", string(initScript), 2},
+		{"original init schema", original[0].Up, 1},
+		{"partial account security tables", original[0].Up + ";\nCREATE TABLE account_token (id INTEGER)", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrator, db := newTestMigrator(t)
+
+			for _, statement := range splitStatements(test.script) {
+				if _, err := db.Exec(statement); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := db.Exec("INSERT INTO account(confirmed, locked, username) VALUES(0, 0, 'a@b.c')"); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := migrator.Up(0, false); err != errUnversionedSchema {
+				t.Fatalf("expected up to refuse an unversioned schema, got %v", err)
+			}
+
+			baseline, err := migrator.BaselineExisting()
+
+			if test.baseline == 0 {
+				if err != errUnversionedSchema {
+					t.Fatalf("expected an unknown schema to be reported, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil || baseline != test.baseline {
+				t.Fatalf("expected baseline version %d, got %d: %v", test.baseline, baseline, err)
+			}
+
+			if _, err = migrator.Up(0, false); err != nil {
+				t.Fatal(err)
+			}
+
+			if version, err := migrator.Version(); err != nil || version != migrator.Latest() {
+				t.Fatalf("expected version %d, got %d: %v", migrator.Latest(), version, err)
+			}
+
+			var accounts int
+
+			if err = db.QueryRow("SELECT COUNT(*) FROM account").Scan(&accounts); err != nil || accounts != 1 {
+				t.Fatalf("expected the existing account to be kept, got %d: %v", accounts, err)
+			}
+
+			if baseline, err = migrator.BaselineExisting(); err != nil || baseline != 0 {
+				t.Fatalf("expected a versioned database not to be baselined again, got %d: %v", baseline, err)
+			}
+		})
+	}
+}
+
+func TestBaselineExistingOnEmptyDatabase(t *testing.T) {
+	migrator, _ := newTestMigrator(t)
+
+	if baseline, err := migrator.BaselineExisting(); err != nil || baseline != 0 {
+		t.Fatalf("expected an empty database not to be baselined, got %d: %v", baseline, err)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	statements := splitStatements("-- comment; ignored\nCREATE TABLE a (b VARCHAR(1) DEFAULT ';');\n\nDROP TABLE c;\n")
+
+	if len(statements) != 2 || statements[0] != "CREATE TABLE a (b VARCHAR(1) DEFAULT ';')" || statements[1] != "DROP TABLE c" {
+		t.Fatalf("unexpected statements: %q", statements)
+	}
+}
@@ -70,4 +70,4 @@ ALTER TABLE transfer_list
 ALTER TABLE transfer_list
    ADD CONSTRAINT FK5bpxwc2m8q5w6r1oy3a8x1t5g
    FOREIGN KEY (transferred_to)
-   REFERENCES team (id);
\ No newline at end of file
+   REFERENCES team (id);
@@ -0,0 +1,10 @@
+DROP TABLE revoked_token;
+
+DROP TABLE refresh_token;
+
+DROP TABLE account_token;
+
+DROP TABLE account_lock_event;
+
+ALTER TABLE account
+   DROP COLUMN token_version;
@@ -0,0 +1,83 @@
+ALTER TABLE account
+   ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE account_lock_event (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    account_id INTEGER NOT NULL,
+    actor_account_id INTEGER,
+    action VARCHAR(255) NOT NULL,
+    reason VARCHAR(255),
+    source VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+CREATE TABLE account_token (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    account_id INTEGER NOT NULL,
+    purpose VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+CREATE TABLE refresh_token (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    account_id INTEGER NOT NULL,
+    family VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL,
+    access_token_id VARCHAR(255) NOT NULL,
+    access_expires_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    revoked_at DATETIME,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+CREATE TABLE revoked_token (
+   token_id VARCHAR(255) NOT NULL,
+    account_id INTEGER,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NOT NULL,
+    PRIMARY KEY (token_id)
+) engine=InnoDB;
+
+ALTER TABLE account_lock_event
+   ADD CONSTRAINT FK_account_lock_event_account
+   FOREIGN KEY (account_id)
+   REFERENCES account (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE account_lock_event
+   ADD CONSTRAINT FK_account_lock_event_actor
+   FOREIGN KEY (actor_account_id)
+   REFERENCES account (id)
+   ON DELETE SET NULL;
+
+ALTER TABLE account_token
+   ADD CONSTRAINT UK_account_token_token UNIQUE (token);
+
+ALTER TABLE account_token
+   ADD CONSTRAINT FK_account_token_account
+   FOREIGN KEY (account_id)
+   REFERENCES account (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE refresh_token
+   ADD CONSTRAINT UK_refresh_token_token UNIQUE (token);
+
+CREATE INDEX IX_refresh_token_family ON refresh_token (family);
+
+CREATE INDEX IX_refresh_token_access_token_id ON refresh_token (access_token_id);
+
+ALTER TABLE refresh_token
+   ADD CONSTRAINT FK_refresh_token_account
+   FOREIGN KEY (account_id)
+   REFERENCES account (id)
+   ON DELETE CASCADE;
+
+-- Before account locks were audited the only way to get locked was failing to log in too many times.
+INSERT INTO account_lock_event(account_id, action, reason, source, created_at)
+SELECT id, 'LOCK', 'too many failed login attempts', 'FAILED_LOGINS', UTC_TIMESTAMP() FROM account WHERE locked = 1;
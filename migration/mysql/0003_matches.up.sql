@@ -0,0 +1,59 @@
+CREATE TABLE match_result (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    home_team_id INTEGER,
+    away_team_id INTEGER,
+    home_goals INTEGER NOT NULL,
+    away_goals INTEGER NOT NULL,
+    seed BIGINT NOT NULL,
+    played_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+CREATE TABLE match_event (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    match_id INTEGER NOT NULL,
+    minute INTEGER NOT NULL,
+    type VARCHAR(255) NOT NULL,
+    team_id INTEGER NOT NULL,
+    player_id INTEGER NOT NULL,
+    related_player_id INTEGER,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+CREATE TABLE match_rating (
+   match_id INTEGER NOT NULL,
+    player_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    player_name VARCHAR(255) NOT NULL,
+    position VARCHAR(255) NOT NULL,
+    started TINYINT NOT NULL,
+    minutes_played INTEGER NOT NULL,
+    rating DECIMAL(3,1) NOT NULL,
+    PRIMARY KEY (match_id, player_id)
+) engine=InnoDB;
+
+ALTER TABLE match_result
+   ADD CONSTRAINT FK_match_result_home_team
+   FOREIGN KEY (home_team_id)
+   REFERENCES team (id)
+   ON DELETE SET NULL;
+
+ALTER TABLE match_result
+   ADD CONSTRAINT FK_match_result_away_team
+   FOREIGN KEY (away_team_id)
+   REFERENCES team (id)
+   ON DELETE SET NULL;
+
+CREATE INDEX IX_match_result_played_at ON match_result (played_at);
+
+ALTER TABLE match_event
+   ADD CONSTRAINT FK_match_event_match
+   FOREIGN KEY (match_id)
+   REFERENCES match_result (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE match_rating
+   ADD CONSTRAINT FK_match_rating_match
+   FOREIGN KEY (match_id)
+   REFERENCES match_result (id)
+   ON DELETE CASCADE;
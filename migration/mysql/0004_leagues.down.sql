@@ -0,0 +1,5 @@
+DROP TABLE fixture;
+
+DROP TABLE league_team;
+
+DROP TABLE league;
@@ -0,0 +1,74 @@
+CREATE TABLE league (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    country VARCHAR(255),
+    status VARCHAR(255) NOT NULL,
+    current_round INTEGER NOT NULL,
+    rounds INTEGER NOT NULL,
+    seed BIGINT NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+CREATE TABLE league_team (
+   league_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    enrolled_at DATETIME NOT NULL,
+    played INTEGER NOT NULL DEFAULT 0,
+    won INTEGER NOT NULL DEFAULT 0,
+    drawn INTEGER NOT NULL DEFAULT 0,
+    lost INTEGER NOT NULL DEFAULT 0,
+    goals_for INTEGER NOT NULL DEFAULT 0,
+    goals_against INTEGER NOT NULL DEFAULT 0,
+    points INTEGER NOT NULL DEFAULT 0,
+    form VARCHAR(5) NOT NULL DEFAULT '',
+    PRIMARY KEY (league_id, team_id)
+) engine=InnoDB;
+
+CREATE TABLE fixture (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    league_id INTEGER NOT NULL,
+    round INTEGER NOT NULL,
+    home_team_id INTEGER NOT NULL,
+    away_team_id INTEGER NOT NULL,
+    match_id INTEGER,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+ALTER TABLE league_team
+   ADD CONSTRAINT FK_league_team_league
+   FOREIGN KEY (league_id)
+   REFERENCES league (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE league_team
+   ADD CONSTRAINT FK_league_team_team
+   FOREIGN KEY (team_id)
+   REFERENCES team (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE fixture
+   ADD CONSTRAINT FK_fixture_league
+   FOREIGN KEY (league_id)
+   REFERENCES league (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE fixture
+   ADD CONSTRAINT FK_fixture_home_team
+   FOREIGN KEY (home_team_id)
+   REFERENCES team (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE fixture
+   ADD CONSTRAINT FK_fixture_away_team
+   FOREIGN KEY (away_team_id)
+   REFERENCES team (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE fixture
+   ADD CONSTRAINT FK_fixture_match
+   FOREIGN KEY (match_id)
+   REFERENCES match_result (id)
+   ON DELETE SET NULL;
+
+CREATE INDEX IX_fixture_league_round ON fixture (league_id, round);
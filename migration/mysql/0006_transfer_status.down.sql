@@ -0,0 +1,5 @@
+DELETE FROM transfer_list WHERE status = 'WITHDRAWN';
+
+DROP INDEX IX_transfer_list_status ON transfer_list;
+
+ALTER TABLE transfer_list DROP COLUMN withdrawn_at, DROP COLUMN status;
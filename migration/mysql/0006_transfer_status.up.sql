@@ -0,0 +1,7 @@
+ALTER TABLE transfer_list ADD COLUMN status VARCHAR(255) NOT NULL DEFAULT 'LISTED';
+
+ALTER TABLE transfer_list ADD COLUMN withdrawn_at DATETIME;
+
+UPDATE transfer_list SET status = 'TRANSFERRED' WHERE transferred = 1;
+
+CREATE INDEX IX_transfer_list_status ON transfer_list (status, player_id);
@@ -0,0 +1,3 @@
+DROP INDEX IX_transfer_list_transferred_at ON transfer_list;
+
+ALTER TABLE transfer_list DROP COLUMN value_after, DROP COLUMN transferred_at;
@@ -0,0 +1,11 @@
+UPDATE team SET available_cash = available_cash +
+    (SELECT COALESCE(SUM(b.amount), 0) FROM transfer_bid b WHERE b.team_id = team.id AND b.status = 'ACTIVE');
+
+UPDATE transfer_list SET status = 'WITHDRAWN', withdrawn_at = COALESCE(deadline, CURRENT_TIMESTAMP)
+WHERE mode = 'AUCTION' AND status IN ('LISTED', 'EXPIRED');
+
+DROP TABLE transfer_bid;
+
+DROP INDEX IX_transfer_list_deadline ON transfer_list;
+
+ALTER TABLE transfer_list DROP COLUMN bid_count, DROP COLUMN highest_bid, DROP COLUMN sealed, DROP COLUMN deadline, DROP COLUMN mode;
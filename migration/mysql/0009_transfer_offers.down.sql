@@ -0,0 +1,3 @@
+DROP TABLE transfer_offer_event;
+
+DROP TABLE transfer_offer;
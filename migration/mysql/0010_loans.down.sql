@@ -0,0 +1,4 @@
+UPDATE player SET team_id = (SELECT l.lender_id FROM loan l WHERE l.player_id = player.id AND l.status = 'ACTIVE')
+WHERE id IN (SELECT player_id FROM loan WHERE status = 'ACTIVE');
+
+DROP TABLE loan;
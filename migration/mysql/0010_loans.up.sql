@@ -0,0 +1,45 @@
+CREATE TABLE loan (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    player_id INTEGER NOT NULL,
+    lender_id INTEGER NOT NULL,
+    borrower_id INTEGER NOT NULL,
+    fee INTEGER NOT NULL,
+    duration_days INTEGER,
+    duration_rounds INTEGER,
+    buy_option_price INTEGER,
+    status VARCHAR(255) NOT NULL,
+    proposed_at DATETIME NOT NULL,
+    started_at DATETIME,
+    ends_at DATETIME,
+    ended_at DATETIME,
+    transfer_id INTEGER,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+ALTER TABLE loan
+   ADD CONSTRAINT FK_loan_player
+   FOREIGN KEY (player_id)
+   REFERENCES player (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE loan
+   ADD CONSTRAINT FK_loan_lender
+   FOREIGN KEY (lender_id)
+   REFERENCES team (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE loan
+   ADD CONSTRAINT FK_loan_borrower
+   FOREIGN KEY (borrower_id)
+   REFERENCES team (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE loan
+   ADD CONSTRAINT FK_loan_transfer
+   FOREIGN KEY (transfer_id)
+   REFERENCES transfer_list (id)
+   ON DELETE SET NULL;
+
+CREATE INDEX IX_loan_player_status ON loan (player_id, status);
+
+CREATE INDEX IX_loan_status_ends_at ON loan (status, ends_at);
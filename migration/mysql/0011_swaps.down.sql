@@ -0,0 +1,3 @@
+DROP TABLE swap_player;
+
+DROP TABLE swap_deal;
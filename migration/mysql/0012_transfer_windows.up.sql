@@ -0,0 +1,22 @@
+CREATE TABLE transfer_window (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    opens_at DATETIME,
+    closes_at DATETIME,
+    league_id INTEGER,
+    opens_after_round INTEGER,
+    closes_after_round INTEGER,
+    listing_policy VARCHAR(255) NOT NULL,
+    manual_state VARCHAR(255),
+    closed_at DATETIME,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+ALTER TABLE transfer_window
+   ADD CONSTRAINT FK_transfer_window_league
+   FOREIGN KEY (league_id)
+   REFERENCES league (id)
+   ON DELETE CASCADE;
+
+CREATE INDEX IX_transfer_window_closed_at ON transfer_window (closed_at);
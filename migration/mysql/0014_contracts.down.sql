@@ -0,0 +1,5 @@
+ALTER TABLE transfer_offer DROP COLUMN contract_release_clause, DROP COLUMN contract_years, DROP COLUMN contract_wage;
+
+ALTER TABLE transfer_bid DROP COLUMN contract_release_clause, DROP COLUMN contract_years, DROP COLUMN contract_wage;
+
+DROP TABLE contract;
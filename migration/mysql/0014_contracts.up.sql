@@ -0,0 +1,33 @@
+CREATE TABLE contract (
+   id INTEGER NOT NULL AUTO_INCREMENT,
+    player_id INTEGER NOT NULL,
+    wage INTEGER NOT NULL,
+    release_clause INTEGER,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    next_wage_at DATETIME NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    ended_at DATETIME,
+    PRIMARY KEY (id)
+) engine=InnoDB;
+
+ALTER TABLE contract
+   ADD CONSTRAINT FK_contract_player
+   FOREIGN KEY (player_id)
+   REFERENCES player (id)
+   ON DELETE CASCADE;
+
+CREATE INDEX IX_contract_player_status ON contract (player_id, status);
+
+CREATE INDEX IX_contract_status_next_wage_at ON contract (status, next_wage_at);
+
+CREATE INDEX IX_contract_status_ends_at ON contract (status, ends_at);
+
+INSERT INTO contract(player_id, wage, starts_at, ends_at, next_wage_at, status)
+SELECT id, GREATEST(100, ROUND(market_value / 10000) * 100), UTC_TIMESTAMP(), DATE_ADD(UTC_TIMESTAMP(), INTERVAL 3 YEAR),
+    DATE_ADD(UTC_TIMESTAMP(), INTERVAL 7 DAY), 'ACTIVE'
+FROM player WHERE team_id IS NOT NULL;
+
+ALTER TABLE transfer_bid ADD COLUMN contract_wage INTEGER, ADD COLUMN contract_years INTEGER, ADD COLUMN contract_release_clause INTEGER;
+
+ALTER TABLE transfer_offer ADD COLUMN contract_wage INTEGER, ADD COLUMN contract_years INTEGER, ADD COLUMN contract_release_clause INTEGER;
@@ -0,0 +1,26 @@
+ALTER TABLE league_team
+   DROP FOREIGN KEY FK_league_team_team;
+
+ALTER TABLE league_team
+   ADD CONSTRAINT FK_league_team_team
+   FOREIGN KEY (team_id)
+   REFERENCES team (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE fixture
+   DROP FOREIGN KEY FK_fixture_home_team;
+
+ALTER TABLE fixture
+   ADD CONSTRAINT FK_fixture_home_team
+   FOREIGN KEY (home_team_id)
+   REFERENCES team (id)
+   ON DELETE CASCADE;
+
+ALTER TABLE fixture
+   DROP FOREIGN KEY FK_fixture_away_team;
+
+ALTER TABLE fixture
+   ADD CONSTRAINT FK_fixture_away_team
+   FOREIGN KEY (away_team_id)
+   REFERENCES team (id)
+   ON DELETE CASCADE;
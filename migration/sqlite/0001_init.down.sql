@@ -0,0 +1,7 @@
+DROP TABLE transfer_list;
+
+DROP TABLE player;
+
+DROP TABLE team;
+
+DROP TABLE account;
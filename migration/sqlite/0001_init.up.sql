@@ -0,0 +1,48 @@
+CREATE TABLE account (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    confirmed TINYINT NOT NULL,
+    first_name VARCHAR(255) COLLATE NOCASE,
+    last_name VARCHAR(255) COLLATE NOCASE,
+    locked TINYINT NOT NULL,
+    login_attempts INTEGER,
+    password VARCHAR(255),
+    profile VARCHAR(255),
+    token_version INTEGER NOT NULL DEFAULT 0,
+    username VARCHAR(255) COLLATE NOCASE,
+    verification_token VARCHAR(255),
+    CONSTRAINT UK_gex1lmaqpg0ir5g1f5eftyaa1 UNIQUE (username)
+);
+
+CREATE TABLE team (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    available_cash INTEGER,
+    country VARCHAR(255) COLLATE NOCASE,
+    name VARCHAR(255) COLLATE NOCASE,
+    account_id INTEGER,
+    CONSTRAINT FK3p8m6jcr5un0gcq49y0ento02 FOREIGN KEY (account_id) REFERENCES account (id)
+);
+
+CREATE TABLE player (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    age INTEGER,
+    country VARCHAR(255) COLLATE NOCASE,
+    first_name VARCHAR(255) COLLATE NOCASE,
+    last_name VARCHAR(255) COLLATE NOCASE,
+    market_value INTEGER,
+    position VARCHAR(255),
+    team_id INTEGER,
+    CONSTRAINT FKdvd6ljes11r44igawmpm1mc5s FOREIGN KEY (team_id) REFERENCES team (id)
+);
+
+CREATE TABLE transfer_list (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    asked_price INTEGER,
+    market_value INTEGER,
+    transferred TINYINT NOT NULL,
+    player_id INTEGER,
+    transferred_from INTEGER,
+    transferred_to INTEGER,
+    CONSTRAINT FK5ta1ls744ss66fvgwagecsros FOREIGN KEY (player_id) REFERENCES player (id),
+    CONSTRAINT FKgoyf6slgja6unsb9g0xkbgfjm FOREIGN KEY (transferred_from) REFERENCES team (id),
+    CONSTRAINT FK5bpxwc2m8q5w6r1oy3a8x1t5g FOREIGN KEY (transferred_to) REFERENCES team (id)
+);
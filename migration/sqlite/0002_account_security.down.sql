@@ -0,0 +1,7 @@
+DROP TABLE revoked_token;
+
+DROP TABLE refresh_token;
+
+DROP TABLE account_token;
+
+DROP TABLE account_lock_event;
@@ -0,0 +1,55 @@
+-- account.token_version is created by 0001_init on SQLite: no SQLite database predates it,
+-- and SQLite cannot drop the column again on the way down.
+CREATE TABLE account_lock_event (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    actor_account_id INTEGER,
+    action VARCHAR(255) NOT NULL,
+    reason VARCHAR(255),
+    source VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT FK_account_lock_event_account FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE,
+    CONSTRAINT FK_account_lock_event_actor FOREIGN KEY (actor_account_id) REFERENCES account (id) ON DELETE SET NULL
+);
+
+CREATE TABLE account_token (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    purpose VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    CONSTRAINT UK_account_token_token UNIQUE (token),
+    CONSTRAINT FK_account_token_account FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE TABLE refresh_token (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    family VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL,
+    access_token_id VARCHAR(255) NOT NULL,
+    access_expires_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    revoked_at DATETIME,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT UK_refresh_token_token UNIQUE (token),
+    CONSTRAINT FK_refresh_token_account FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IX_refresh_token_family ON refresh_token (family);
+
+CREATE INDEX IX_refresh_token_access_token_id ON refresh_token (access_token_id);
+
+CREATE TABLE revoked_token (
+    token_id VARCHAR(255) NOT NULL,
+    account_id INTEGER,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NOT NULL,
+    PRIMARY KEY (token_id)
+);
+
+-- Before account locks were audited the only way to get locked was failing to log in too many times.
+INSERT INTO account_lock_event(account_id, action, reason, source, created_at)
+SELECT id, 'LOCK', 'too many failed login attempts', 'FAILED_LOGINS', datetime('now') FROM account WHERE locked = 1;
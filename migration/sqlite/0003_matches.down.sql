@@ -0,0 +1,5 @@
+DROP TABLE match_rating;
+
+DROP TABLE match_event;
+
+DROP TABLE match_result;
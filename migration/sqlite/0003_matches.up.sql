@@ -0,0 +1,37 @@
+CREATE TABLE match_result (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    home_team_id INTEGER,
+    away_team_id INTEGER,
+    home_goals INTEGER NOT NULL,
+    away_goals INTEGER NOT NULL,
+    seed BIGINT NOT NULL,
+    played_at DATETIME NOT NULL,
+    CONSTRAINT FK_match_result_home_team FOREIGN KEY (home_team_id) REFERENCES team (id) ON DELETE SET NULL,
+    CONSTRAINT FK_match_result_away_team FOREIGN KEY (away_team_id) REFERENCES team (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IX_match_result_played_at ON match_result (played_at);
+
+CREATE TABLE match_event (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    match_id INTEGER NOT NULL,
+    minute INTEGER NOT NULL,
+    type VARCHAR(255) NOT NULL,
+    team_id INTEGER NOT NULL,
+    player_id INTEGER NOT NULL,
+    related_player_id INTEGER,
+    CONSTRAINT FK_match_event_match FOREIGN KEY (match_id) REFERENCES match_result (id) ON DELETE CASCADE
+);
+
+CREATE TABLE match_rating (
+    match_id INTEGER NOT NULL,
+    player_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    player_name VARCHAR(255) NOT NULL,
+    position VARCHAR(255) NOT NULL,
+    started TINYINT NOT NULL,
+    minutes_played INTEGER NOT NULL,
+    rating DECIMAL(3,1) NOT NULL,
+    PRIMARY KEY (match_id, player_id),
+    CONSTRAINT FK_match_rating_match FOREIGN KEY (match_id) REFERENCES match_result (id) ON DELETE CASCADE
+);
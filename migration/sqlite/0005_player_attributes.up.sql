@@ -0,0 +1,14 @@
+CREATE TABLE player_attributes (
+    player_id INTEGER PRIMARY KEY,
+    pace INTEGER NOT NULL,
+    shooting INTEGER NOT NULL,
+    passing INTEGER NOT NULL,
+    defending INTEGER NOT NULL,
+    goalkeeping INTEGER NOT NULL,
+    stamina INTEGER NOT NULL,
+    potential INTEGER NOT NULL,
+    CONSTRAINT FK_player_attributes_player FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE
+);
+
+INSERT INTO player_attributes(player_id, pace, shooting, passing, defending, goalkeeping, stamina, potential)
+SELECT id, 50, 50, 50, 50, CASE WHEN position = 'GK' THEN 50 ELSE 10 END, 50, 50 FROM player;
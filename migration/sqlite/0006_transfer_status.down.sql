@@ -0,0 +1,22 @@
+-- SQLite cannot drop columns, so the table is rebuilt with its original definition.
+CREATE TABLE transfer_list_previous (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    asked_price INTEGER,
+    market_value INTEGER,
+    transferred TINYINT NOT NULL,
+    player_id INTEGER,
+    transferred_from INTEGER,
+    transferred_to INTEGER,
+    CONSTRAINT FK5ta1ls744ss66fvgwagecsros FOREIGN KEY (player_id) REFERENCES player (id),
+    CONSTRAINT FKgoyf6slgja6unsb9g0xkbgfjm FOREIGN KEY (transferred_from) REFERENCES team (id),
+    CONSTRAINT FK5bpxwc2m8q5w6r1oy3a8x1t5g FOREIGN KEY (transferred_to) REFERENCES team (id)
+);
+
+INSERT INTO transfer_list_previous(id, asked_price, market_value, transferred, player_id, transferred_from, transferred_to)
+SELECT id, asked_price, market_value, transferred, player_id, transferred_from, transferred_to
+FROM transfer_list
+WHERE status != 'WITHDRAWN';
+
+DROP TABLE transfer_list;
+
+ALTER TABLE transfer_list_previous RENAME TO transfer_list;
@@ -0,0 +1,25 @@
+-- SQLite cannot drop columns, so the table is rebuilt with its previous definition.
+CREATE TABLE transfer_list_previous (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    asked_price INTEGER,
+    market_value INTEGER,
+    transferred TINYINT NOT NULL,
+    player_id INTEGER,
+    transferred_from INTEGER,
+    transferred_to INTEGER,
+    status VARCHAR(255) NOT NULL DEFAULT 'LISTED',
+    withdrawn_at DATETIME,
+    CONSTRAINT FK5ta1ls744ss66fvgwagecsros FOREIGN KEY (player_id) REFERENCES player (id),
+    CONSTRAINT FKgoyf6slgja6unsb9g0xkbgfjm FOREIGN KEY (transferred_from) REFERENCES team (id),
+    CONSTRAINT FK5bpxwc2m8q5w6r1oy3a8x1t5g FOREIGN KEY (transferred_to) REFERENCES team (id)
+);
+
+INSERT INTO transfer_list_previous(id, asked_price, market_value, transferred, player_id, transferred_from, transferred_to, status, withdrawn_at)
+SELECT id, asked_price, market_value, transferred, player_id, transferred_from, transferred_to, status, withdrawn_at
+FROM transfer_list;
+
+DROP TABLE transfer_list;
+
+ALTER TABLE transfer_list_previous RENAME TO transfer_list;
+
+CREATE INDEX IX_transfer_list_status ON transfer_list (status, player_id);
@@ -0,0 +1,5 @@
+ALTER TABLE transfer_list ADD COLUMN transferred_at DATETIME;
+
+ALTER TABLE transfer_list ADD COLUMN value_after INTEGER;
+
+CREATE INDEX IX_transfer_list_transferred_at ON transfer_list (transferred_at);
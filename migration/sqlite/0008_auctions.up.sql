@@ -0,0 +1,23 @@
+ALTER TABLE transfer_list ADD COLUMN mode VARCHAR(255) NOT NULL DEFAULT 'FIXED_PRICE';
+
+ALTER TABLE transfer_list ADD COLUMN deadline DATETIME;
+
+ALTER TABLE transfer_list ADD COLUMN sealed TINYINT NOT NULL DEFAULT 0;
+
+ALTER TABLE transfer_list ADD COLUMN highest_bid INTEGER;
+
+ALTER TABLE transfer_list ADD COLUMN bid_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE transfer_bid (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transfer_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    placed_at DATETIME NOT NULL,
+    CONSTRAINT UK_transfer_bid_transfer_team UNIQUE (transfer_id, team_id),
+    CONSTRAINT FK_transfer_bid_transfer FOREIGN KEY (transfer_id) REFERENCES transfer_list (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_bid_team FOREIGN KEY (team_id) REFERENCES team (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IX_transfer_list_deadline ON transfer_list (mode, status, deadline);
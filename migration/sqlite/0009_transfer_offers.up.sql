@@ -0,0 +1,32 @@
+CREATE TABLE transfer_offer (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    player_id INTEGER NOT NULL,
+    buyer_id INTEGER NOT NULL,
+    seller_id INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    awaiting_id INTEGER NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    transfer_id INTEGER,
+    CONSTRAINT FK_transfer_offer_player FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_offer_buyer FOREIGN KEY (buyer_id) REFERENCES team (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_offer_seller FOREIGN KEY (seller_id) REFERENCES team (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_offer_transfer FOREIGN KEY (transfer_id) REFERENCES transfer_list (id) ON DELETE SET NULL
+);
+
+CREATE TABLE transfer_offer_event (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    offer_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    amount INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT FK_transfer_offer_event_offer FOREIGN KEY (offer_id) REFERENCES transfer_offer (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_offer_event_team FOREIGN KEY (team_id) REFERENCES team (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IX_transfer_offer_player_status ON transfer_offer (player_id, status);
+
+CREATE INDEX IX_transfer_offer_event_offer ON transfer_offer_event (offer_id, created_at);
@@ -0,0 +1,24 @@
+CREATE TABLE loan (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    player_id INTEGER NOT NULL,
+    lender_id INTEGER NOT NULL,
+    borrower_id INTEGER NOT NULL,
+    fee INTEGER NOT NULL,
+    duration_days INTEGER,
+    duration_rounds INTEGER,
+    buy_option_price INTEGER,
+    status VARCHAR(255) NOT NULL,
+    proposed_at DATETIME NOT NULL,
+    started_at DATETIME,
+    ends_at DATETIME,
+    ended_at DATETIME,
+    transfer_id INTEGER,
+    CONSTRAINT FK_loan_player FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE,
+    CONSTRAINT FK_loan_lender FOREIGN KEY (lender_id) REFERENCES team (id) ON DELETE CASCADE,
+    CONSTRAINT FK_loan_borrower FOREIGN KEY (borrower_id) REFERENCES team (id) ON DELETE CASCADE,
+    CONSTRAINT FK_loan_transfer FOREIGN KEY (transfer_id) REFERENCES transfer_list (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IX_loan_player_status ON loan (player_id, status);
+
+CREATE INDEX IX_loan_status_ends_at ON loan (status, ends_at);
@@ -0,0 +1,24 @@
+CREATE TABLE swap_deal (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    proposer_id INTEGER NOT NULL,
+    receiver_id INTEGER NOT NULL,
+    cash_adjustment INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(255) NOT NULL,
+    proposed_at DATETIME NOT NULL,
+    responded_at DATETIME,
+    CONSTRAINT FK_swap_deal_proposer FOREIGN KEY (proposer_id) REFERENCES team (id) ON DELETE CASCADE,
+    CONSTRAINT FK_swap_deal_receiver FOREIGN KEY (receiver_id) REFERENCES team (id) ON DELETE CASCADE
+);
+
+CREATE TABLE swap_player (
+    swap_id INTEGER NOT NULL,
+    player_id INTEGER NOT NULL,
+    from_team_id INTEGER NOT NULL,
+    transfer_id INTEGER,
+    PRIMARY KEY (swap_id, player_id),
+    CONSTRAINT FK_swap_player_swap FOREIGN KEY (swap_id) REFERENCES swap_deal (id) ON DELETE CASCADE,
+    CONSTRAINT FK_swap_player_player FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE,
+    CONSTRAINT FK_swap_player_transfer FOREIGN KEY (transfer_id) REFERENCES transfer_list (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IX_swap_player_player ON swap_player (player_id);
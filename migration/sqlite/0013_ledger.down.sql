@@ -0,0 +1 @@
+DROP TABLE ledger_transaction;
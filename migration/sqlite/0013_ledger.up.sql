@@ -0,0 +1,27 @@
+CREATE TABLE ledger_transaction (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category VARCHAR(255) NOT NULL,
+    reference_type VARCHAR(255) NOT NULL,
+    reference_id INTEGER,
+    from_account VARCHAR(255) NOT NULL,
+    from_team_id INTEGER,
+    to_account VARCHAR(255) NOT NULL,
+    to_team_id INTEGER,
+    amount INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT FK_ledger_transaction_from_team FOREIGN KEY (from_team_id) REFERENCES team (id) ON DELETE SET NULL,
+    CONSTRAINT FK_ledger_transaction_to_team FOREIGN KEY (to_team_id) REFERENCES team (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IX_ledger_transaction_from_team ON ledger_transaction (from_team_id);
+
+CREATE INDEX IX_ledger_transaction_to_team ON ledger_transaction (to_team_id);
+
+INSERT INTO ledger_transaction(category, reference_type, reference_id, from_account, from_team_id, to_account, to_team_id, amount, created_at)
+SELECT 'OPENING_BALANCE', 'TEAM', id, 'EXTERNAL', NULL, 'TEAM', id, available_cash, CURRENT_TIMESTAMP FROM team WHERE available_cash > 0;
+
+INSERT INTO ledger_transaction(category, reference_type, reference_id, from_account, from_team_id, to_account, to_team_id, amount, created_at)
+SELECT 'OPENING_BALANCE', 'TEAM', id, 'TEAM', id, 'EXTERNAL', NULL, -available_cash, CURRENT_TIMESTAMP FROM team WHERE available_cash < 0;
+
+INSERT INTO ledger_transaction(category, reference_type, reference_id, from_account, from_team_id, to_account, to_team_id, amount, created_at)
+SELECT 'OPENING_BALANCE', 'TRANSFER', transfer_id, 'EXTERNAL', NULL, 'ESCROW', team_id, amount, CURRENT_TIMESTAMP FROM transfer_bid WHERE status = 'ACTIVE';
@@ -0,0 +1,81 @@
+-- SQLite cannot drop columns, so transfer_bid and transfer_offer are rebuilt with their previous definitions.
+-- transfer_offer_event is set aside first: dropping transfer_offer would otherwise cascade to its rows.
+CREATE TABLE transfer_bid_previous (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    transfer_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    placed_at DATETIME NOT NULL,
+    CONSTRAINT UK_transfer_bid_transfer_team UNIQUE (transfer_id, team_id),
+    CONSTRAINT FK_transfer_bid_transfer FOREIGN KEY (transfer_id) REFERENCES transfer_list (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_bid_team FOREIGN KEY (team_id) REFERENCES team (id) ON DELETE CASCADE
+);
+
+INSERT INTO transfer_bid_previous(id, transfer_id, team_id, amount, status, placed_at)
+SELECT id, transfer_id, team_id, amount, status, placed_at FROM transfer_bid;
+
+DROP TABLE transfer_bid;
+
+ALTER TABLE transfer_bid_previous RENAME TO transfer_bid;
+
+CREATE TABLE transfer_offer_event_previous (
+    id INTEGER PRIMARY KEY,
+    offer_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    amount INTEGER NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+INSERT INTO transfer_offer_event_previous(id, offer_id, team_id, action, amount, created_at)
+SELECT id, offer_id, team_id, action, amount, created_at FROM transfer_offer_event;
+
+DROP TABLE transfer_offer_event;
+
+CREATE TABLE transfer_offer_previous (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    player_id INTEGER NOT NULL,
+    buyer_id INTEGER NOT NULL,
+    seller_id INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    awaiting_id INTEGER NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    transfer_id INTEGER,
+    CONSTRAINT FK_transfer_offer_player FOREIGN KEY (player_id) REFERENCES player (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_offer_buyer FOREIGN KEY (buyer_id) REFERENCES team (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_offer_seller FOREIGN KEY (seller_id) REFERENCES team (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_offer_transfer FOREIGN KEY (transfer_id) REFERENCES transfer_list (id) ON DELETE SET NULL
+);
+
+INSERT INTO transfer_offer_previous(id, player_id, buyer_id, seller_id, amount, status, awaiting_id, expires_at, created_at, updated_at, transfer_id)
+SELECT id, player_id, buyer_id, seller_id, amount, status, awaiting_id, expires_at, created_at, updated_at, transfer_id FROM transfer_offer;
+
+DROP TABLE transfer_offer;
+
+ALTER TABLE transfer_offer_previous RENAME TO transfer_offer;
+
+CREATE INDEX IX_transfer_offer_player_status ON transfer_offer (player_id, status);
+
+CREATE TABLE transfer_offer_event (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    offer_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    action VARCHAR(255) NOT NULL,
+    amount INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT FK_transfer_offer_event_offer FOREIGN KEY (offer_id) REFERENCES transfer_offer (id) ON DELETE CASCADE,
+    CONSTRAINT FK_transfer_offer_event_team FOREIGN KEY (team_id) REFERENCES team (id) ON DELETE CASCADE
+);
+
+INSERT INTO transfer_offer_event(id, offer_id, team_id, action, amount, created_at)
+SELECT id, offer_id, team_id, action, amount, created_at FROM transfer_offer_event_previous;
+
+DROP TABLE transfer_offer_event_previous;
+
+CREATE INDEX IX_transfer_offer_event_offer ON transfer_offer_event (offer_id, created_at);
+
+DROP TABLE contract;
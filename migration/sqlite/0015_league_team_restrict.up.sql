@@ -0,0 +1,46 @@
+-- SQLite cannot alter constraints, so league_team and fixture are rebuilt with the new team foreign keys.
+CREATE TABLE league_team_previous (
+    league_id INTEGER NOT NULL,
+    team_id INTEGER NOT NULL,
+    enrolled_at DATETIME NOT NULL,
+    played INTEGER NOT NULL DEFAULT 0,
+    won INTEGER NOT NULL DEFAULT 0,
+    drawn INTEGER NOT NULL DEFAULT 0,
+    lost INTEGER NOT NULL DEFAULT 0,
+    goals_for INTEGER NOT NULL DEFAULT 0,
+    goals_against INTEGER NOT NULL DEFAULT 0,
+    points INTEGER NOT NULL DEFAULT 0,
+    form VARCHAR(5) NOT NULL DEFAULT '',
+    PRIMARY KEY (league_id, team_id),
+    CONSTRAINT FK_league_team_league FOREIGN KEY (league_id) REFERENCES league (id) ON DELETE CASCADE,
+    CONSTRAINT FK_league_team_team FOREIGN KEY (team_id) REFERENCES team (id) ON DELETE RESTRICT
+);
+
+INSERT INTO league_team_previous(league_id, team_id, enrolled_at, played, won, drawn, lost, goals_for, goals_against, points, form)
+SELECT league_id, team_id, enrolled_at, played, won, drawn, lost, goals_for, goals_against, points, form FROM league_team;
+
+DROP TABLE league_team;
+
+ALTER TABLE league_team_previous RENAME TO league_team;
+
+CREATE TABLE fixture_previous (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    league_id INTEGER NOT NULL,
+    round INTEGER NOT NULL,
+    home_team_id INTEGER NOT NULL,
+    away_team_id INTEGER NOT NULL,
+    match_id INTEGER,
+    CONSTRAINT FK_fixture_league FOREIGN KEY (league_id) REFERENCES league (id) ON DELETE CASCADE,
+    CONSTRAINT FK_fixture_home_team FOREIGN KEY (home_team_id) REFERENCES team (id) ON DELETE RESTRICT,
+    CONSTRAINT FK_fixture_away_team FOREIGN KEY (away_team_id) REFERENCES team (id) ON DELETE RESTRICT,
+    CONSTRAINT FK_fixture_match FOREIGN KEY (match_id) REFERENCES match_result (id) ON DELETE SET NULL
+);
+
+INSERT INTO fixture_previous(id, league_id, round, home_team_id, away_team_id, match_id)
+SELECT id, league_id, round, home_team_id, away_team_id, match_id FROM fixture;
+
+DROP TABLE fixture;
+
+ALTER TABLE fixture_previous RENAME TO fixture;
+
+CREATE INDEX IX_fixture_league_round ON fixture (league_id, round);
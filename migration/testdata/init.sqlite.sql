@@ -0,0 +1,97 @@
+CREATE TABLE account (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    confirmed TINYINT NOT NULL,
+    first_name VARCHAR(255) COLLATE NOCASE,
+    last_name VARCHAR(255) COLLATE NOCASE,
+    locked TINYINT NOT NULL,
+    login_attempts INTEGER,
+    password VARCHAR(255),
+    profile VARCHAR(255),
+    token_version INTEGER NOT NULL DEFAULT 0,
+    username VARCHAR(255) COLLATE NOCASE,
+    verification_token VARCHAR(255),
+    CONSTRAINT UK_gex1lmaqpg0ir5g1f5eftyaa1 UNIQUE (username)
+);
+
+CREATE TABLE team (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    available_cash INTEGER,
+    country VARCHAR(255) COLLATE NOCASE,
+    name VARCHAR(255) COLLATE NOCASE,
+    account_id INTEGER,
+    CONSTRAINT FK3p8m6jcr5un0gcq49y0ento02 FOREIGN KEY (account_id) REFERENCES account (id)
+);
+
+CREATE TABLE player (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    age INTEGER,
+    country VARCHAR(255) COLLATE NOCASE,
+    first_name VARCHAR(255) COLLATE NOCASE,
+    last_name VARCHAR(255) COLLATE NOCASE,
+    market_value INTEGER,
+    position VARCHAR(255),
+    team_id INTEGER,
+    CONSTRAINT FKdvd6ljes11r44igawmpm1mc5s FOREIGN KEY (team_id) REFERENCES team (id)
+);
+
+CREATE TABLE transfer_list (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    asked_price INTEGER,
+    market_value INTEGER,
+    transferred TINYINT NOT NULL,
+    player_id INTEGER,
+    transferred_from INTEGER,
+    transferred_to INTEGER,
+    CONSTRAINT FK5ta1ls744ss66fvgwagecsros FOREIGN KEY (player_id) REFERENCES player (id),
+    CONSTRAINT FKgoyf6slgja6unsb9g0xkbgfjm FOREIGN KEY (transferred_from) REFERENCES team (id),
+    CONSTRAINT FK5bpxwc2m8q5w6r1oy3a8x1t5g FOREIGN KEY (transferred_to) REFERENCES team (id)
+);
+
+CREATE TABLE account_lock_event (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    actor_account_id INTEGER,
+    action VARCHAR(255) NOT NULL,
+    reason VARCHAR(255),
+    created_at DATETIME NOT NULL,
+    CONSTRAINT FK_account_lock_event_account FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE,
+    CONSTRAINT FK_account_lock_event_actor FOREIGN KEY (actor_account_id) REFERENCES account (id) ON DELETE SET NULL
+);
+
+CREATE TABLE account_token (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    purpose VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    CONSTRAINT UK_account_token_token UNIQUE (token),
+    CONSTRAINT FK_account_token_account FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE TABLE refresh_token (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id INTEGER NOT NULL,
+    family VARCHAR(255) NOT NULL,
+    token VARCHAR(255) NOT NULL,
+    access_token_id VARCHAR(255) NOT NULL,
+    access_expires_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    revoked_at DATETIME,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT UK_refresh_token_token UNIQUE (token),
+    CONSTRAINT FK_refresh_token_account FOREIGN KEY (account_id) REFERENCES account (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IX_refresh_token_family ON refresh_token (family);
+
+CREATE INDEX IX_refresh_token_access_token_id ON refresh_token (access_token_id);
+
+CREATE TABLE revoked_token (
+    token_id VARCHAR(255) NOT NULL,
+    account_id INTEGER,
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NOT NULL,
+    PRIMARY KEY (token_id)
+);
@@ -0,0 +1,118 @@
+package names
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+//go:embed data
+var data embed.FS
+
+type Generator interface {
+	Countries() []string
+	Nationality(teamCountry string, rng *rand.Rand) string
+	Name(country string, rng *rand.Rand) (firstName, lastName string)
+}
+
+type Dataset struct {
+	Country       string         `json:"country"`
+	FirstNames    []string       `json:"firstNames"`
+	LastNames     []string       `json:"lastNames"`
+	Nationalities map[string]int `json:"nationalities"`
+}
+
+type Datasets struct {
+	countries []string
+	datasets  map[string]Dataset
+}
+
+func Load() (*Datasets, error) {
+	files, err := fs.Glob(data, "data/*.json")
+
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &Datasets{datasets: make(map[string]Dataset)}
+
+	for _, file := range files {
+		content, err := data.ReadFile(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		var dataset Dataset
+
+		if err = json.Unmarshal(content, &dataset); err != nil {
+			return nil, fmt.Errorf("invalid name dataset %s: %v", file, err)
+		}
+
+		if dataset.Country == "" || len(dataset.FirstNames) == 0 || len(dataset.LastNames) == 0 {
+			return nil, fmt.Errorf("name dataset %s needs a country, first names and last names", file)
+		}
+
+		ds.countries = append(ds.countries, dataset.Country)
+		ds.datasets[strings.ToLower(dataset.Country)] = dataset
+	}
+
+	sort.Strings(ds.countries)
+
+	for _, dataset := range ds.datasets {
+		for nationality, weight := range dataset.Nationalities {
+			if _, ok := ds.datasets[strings.ToLower(nationality)]; !ok || weight <= 0 {
+				return nil, fmt.Errorf("name dataset %s has an invalid nationality %s", dataset.Country, nationality)
+			}
+		}
+	}
+
+	return ds, nil
+}
+
+func (ds *Datasets) Countries() []string {
+	return append([]string(nil), ds.countries...)
+}
+
+func (ds *Datasets) Nationality(teamCountry string, rng *rand.Rand) string {
+	dataset, ok := ds.datasets[strings.ToLower(teamCountry)]
+
+	if !ok || len(dataset.Nationalities) == 0 {
+		return ds.countries[rng.Intn(len(ds.countries))]
+	}
+
+	nationalities := make([]string, 0, len(dataset.Nationalities))
+	total := 0
+
+	for nationality, weight := range dataset.Nationalities {
+		nationalities = append(nationalities, nationality)
+		total += weight
+	}
+
+	sort.Strings(nationalities)
+	target := rng.Intn(total)
+
+	for _, nationality := range nationalities {
+		target -= dataset.Nationalities[nationality]
+
+		if target < 0 {
+			return ds.datasets[strings.ToLower(nationality)].Country
+		}
+	}
+
+	return dataset.Country
+}
+
+func (ds *Datasets) Name(country string, rng *rand.Rand) (firstName, lastName string) {
+	dataset, ok := ds.datasets[strings.ToLower(country)]
+
+	if !ok {
+		dataset = ds.datasets[strings.ToLower(ds.countries[rng.Intn(len(ds.countries))])]
+	}
+
+	return dataset.FirstNames[rng.Intn(len(dataset.FirstNames))], dataset.LastNames[rng.Intn(len(dataset.LastNames))]
+}
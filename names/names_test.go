@@ -0,0 +1,53 @@
+package names
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestLoadEmbeddedDatasets(t *testing.T) {
+	ds, err := Load()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ds.Countries()) < 2 {
+		t.Fatalf("expected several countries, got %v", ds.Countries())
+	}
+
+	for _, country := range ds.Countries() {
+		if first, last := ds.Name(country, rand.New(rand.NewSource(1))); first == "" || last == "" {
+			t.Fatalf("expected a name for %s", country)
+		}
+	}
+}
+
+func TestNationalityFollowsTeamCountry(t *testing.T) {
+	ds, _ := Load()
+	rng := rand.New(rand.NewSource(3))
+	counts := map[string]int{}
+
+	for i := 0; i < 1000; i++ {
+		counts[ds.Nationality("brazil", rng)]++
+	}
+
+	if counts["Brazil"] < 750 || counts["Brazil"] > 950 || len(counts) < 2 {
+		t.Fatalf("expected mostly Brazilian players with a few foreigners, got %v", counts)
+	}
+
+	if nationality := ds.Nationality("Atlantis", rng); ds.datasets[strings.ToLower(nationality)].Country != nationality {
+		t.Fatalf("expected a known nationality for an unknown team country, got %s", nationality)
+	}
+}
+
+func TestNameIsReproducible(t *testing.T) {
+	ds, _ := Load()
+	first, last := ds.Name("Italy", rand.New(rand.NewSource(9)))
+	otherFirst, otherLast := ds.Name("Italy", rand.New(rand.NewSource(9)))
+
+	if first != otherFirst || last != otherLast {
+		t.Fatal("expected the same seed to generate the same name")
+	}
+}
@@ -0,0 +1,326 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"time"
+)
+
+type AccountRepository struct {
+	store            *Store
+	teamRepository   *TeamRepository
+	playerRepository *PlayerRepository
+}
+
+func NewAccountRepository(
+	store *Store,
+	tr *TeamRepository,
+	pr *PlayerRepository) *AccountRepository {
+	return &AccountRepository{
+		store:            store,
+		teamRepository:   tr,
+		playerRepository: pr,
+	}
+}
+
+func (ar *AccountRepository) CreateAccount(account *domain.Account, now time.Time) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	if _, ok := ar.store.accountByUsername(account.Username); ok {
+		return errors.New("duplicate username")
+	}
+
+	account.Id = ar.store.nextId("account")
+	stored := *account
+	stored.Team = nil
+	ar.store.accounts[account.Id] = stored
+
+	account.Team.AccountId = account.Id
+	ar.store.createTeam(account.Team, now)
+
+	for i := 0; i < len(account.Team.Players); i++ {
+		account.Team.Players[i].TeamId = &account.Team.Id
+		ar.store.createPlayer(&account.Team.Players[i])
+	}
+
+	return nil
+}
+
+func (ar *AccountRepository) UpdateAccount(account *domain.Account) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	stored, ok := ar.store.accounts[account.Id]
+
+	if !ok {
+		return nil
+	}
+
+	if other, ok := ar.store.accountByUsername(account.Username); ok && other.Id != account.Id {
+		return errors.New("duplicate username")
+	}
+
+	stored.Username = account.Username
+	stored.FirstName = account.FirstName
+	stored.LastName = account.LastName
+	stored.Profile = account.Profile
+	stored.Confirmed = account.Confirmed
+	ar.store.accounts[account.Id] = stored
+	return nil
+}
+
+func (ar *AccountRepository) DeleteAccount(id int, now time.Time) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	if _, ok := ar.store.accounts[id]; !ok {
+		return sql.ErrNoRows
+	}
+
+	if team, ok := ar.store.teamByAccountId(id); ok {
+		if err := ar.store.deleteTeam(team.Id, now); err != nil {
+			return err
+		}
+	}
+
+	events := ar.store.lockEvents[:0]
+
+	for _, event := range ar.store.lockEvents {
+		if event.AccountId == id {
+			continue
+		}
+
+		if sameInt(event.ActorAccountId, id) {
+			event.ActorAccountId = nil
+		}
+
+		events = append(events, event)
+	}
+
+	ar.store.lockEvents = events
+
+	for tokenId, token := range ar.store.accountTokens {
+		if token.AccountId == id {
+			delete(ar.store.accountTokens, tokenId)
+		}
+	}
+
+	for tokenId, token := range ar.store.refreshTokens {
+		if token.AccountId == id {
+			delete(ar.store.refreshTokens, tokenId)
+		}
+	}
+
+	delete(ar.store.accounts, id)
+	return nil
+}
+
+func (ar *AccountRepository) GetAccountById(id int) (domain.Account, error) {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	account, ok := ar.store.accounts[id]
+
+	if !ok {
+		return domain.Account{}, sql.ErrNoRows
+	}
+
+	return account, nil
+}
+
+func (ar *AccountRepository) GetAccountByUsername(username string) (domain.Account, error) {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	account, ok := ar.store.accountByUsername(username)
+
+	if !ok {
+		return domain.Account{}, sql.ErrNoRows
+	}
+
+	return account, nil
+}
+
+func (ar *AccountRepository) FindAccounts() (accounts []domain.Account, err error) {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	for _, account := range ar.store.accounts {
+		account.Password = ""
+		account.VerificationToken = ""
+
+		if team, ok := ar.store.teamByAccountId(account.Id); ok {
+			account.Team = &domain.Team{Id: team.Id, AccountId: account.Id}
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Id < accounts[j].Id })
+	return accounts, nil
+}
+
+func (ar *AccountRepository) VerifyAccount(token string) (bool, error) {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	for id, account := range ar.store.accounts {
+		if account.VerificationToken == token && !account.Confirmed {
+			account.Confirmed = true
+			ar.store.accounts[id] = account
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (ar *AccountRepository) RegisterFailedLoginAttempt(username string, event *domain.AccountLockEvent) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	account, ok := ar.store.accountByUsername(username)
+
+	if !ok || account.Locked {
+		return nil
+	}
+
+	account.LoginAttempts++
+
+	if account.LoginAttempts > 2 {
+		account.Locked = true
+		event.AccountId = account.Id
+		ar.store.createLockEvent(event)
+	}
+
+	ar.store.accounts[account.Id] = account
+	return nil
+}
+
+func (ar *AccountRepository) ResetLoginAttempts(username string) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	if account, ok := ar.store.accountByUsername(username); ok {
+		account.LoginAttempts = 0
+		ar.store.accounts[account.Id] = account
+	}
+
+	return nil
+}
+
+func (ar *AccountRepository) ResetPassword(token, password string, event *domain.AccountLockEvent, now time.Time) (int, error) {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	accountId, err := ar.store.useToken(domain.PasswordResetPurpose, token, now)
+
+	if err != nil {
+		return 0, err
+	}
+
+	account, ok := ar.store.accounts[accountId]
+
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+
+	account.Password = password
+	account.TokenVersion++
+
+	if ar.store.lockedByFailedLogins(account) {
+		account.Locked = false
+		event.AccountId, event.ActorAccountId = accountId, &accountId
+		ar.store.createLockEvent(event)
+	}
+
+	if !account.Locked {
+		account.LoginAttempts = 0
+	}
+
+	ar.store.accounts[accountId] = account
+	ar.store.revokeTokens(accountId, domain.PasswordResetPurpose, now)
+	return accountId, nil
+}
+
+func (ar *AccountRepository) SetLocked(accountId int, locked bool, event *domain.AccountLockEvent) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	account, ok := ar.store.accounts[accountId]
+
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	account.Locked = locked
+	account.LoginAttempts = 0
+	ar.store.accounts[accountId] = account
+	ar.store.createLockEvent(event)
+	return nil
+}
+
+func (ar *AccountRepository) UnlockFailedLogins(accountId int, event *domain.AccountLockEvent) error {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	account, ok := ar.store.accounts[accountId]
+
+	if !ok || !ar.store.lockedByFailedLogins(account) {
+		return sql.ErrNoRows
+	}
+
+	account.Locked = false
+	account.LoginAttempts = 0
+	ar.store.accounts[accountId] = account
+	ar.store.createLockEvent(event)
+	return nil
+}
+
+func (ar *AccountRepository) FindLockEvents(accountId int) (events []domain.AccountLockEvent, err error) {
+	ar.store.mu.Lock()
+	defer ar.store.mu.Unlock()
+
+	for _, event := range ar.store.lockEvents {
+		if event.AccountId == accountId {
+			event.ActorAccountId = copyInt(event.ActorAccountId)
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (s *Store) accountByUsername(username string) (domain.Account, bool) {
+	for _, account := range s.accounts {
+		if account.Username == username {
+			return account, true
+		}
+	}
+
+	return domain.Account{}, false
+}
+
+func (s *Store) lockedByFailedLogins(account domain.Account) bool {
+	if !account.Locked {
+		return false
+	}
+
+	for i := len(s.lockEvents) - 1; i >= 0; i-- {
+		if event := s.lockEvents[i]; event.AccountId == account.Id && event.Action == domain.LockAction {
+			return event.Source == domain.FailedLoginsLockSource
+		}
+	}
+
+	return false
+}
+
+func (s *Store) createLockEvent(event *domain.AccountLockEvent) {
+	event.Id = s.nextId("account_lock_event")
+	stored := *event
+	stored.ActorAccountId = copyInt(event.ActorAccountId)
+	s.lockEvents = append(s.lockEvents, stored)
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+type AccountTokenRepository struct {
+	store *Store
+}
+
+func NewAccountTokenRepository(store *Store) *AccountTokenRepository {
+	return &AccountTokenRepository{
+		store: store,
+	}
+}
+
+func (atr *AccountTokenRepository) CreateToken(token *domain.AccountToken) error {
+	atr.store.mu.Lock()
+	defer atr.store.mu.Unlock()
+
+	for _, stored := range atr.store.accountTokens {
+		if stored.Token == token.Token {
+			return errors.New("duplicate token")
+		}
+	}
+
+	token.Id = atr.store.nextId("account_token")
+	stored := *token
+	stored.UsedAt = copyTime(token.UsedAt)
+	atr.store.accountTokens[token.Id] = stored
+	return nil
+}
+
+func (atr *AccountTokenRepository) UseToken(purpose, token string, now time.Time) (int, error) {
+	atr.store.mu.Lock()
+	defer atr.store.mu.Unlock()
+
+	return atr.store.useToken(purpose, token, now)
+}
+
+func (atr *AccountTokenRepository) RevokeTokens(accountId int, purpose string, now time.Time) error {
+	atr.store.mu.Lock()
+	defer atr.store.mu.Unlock()
+
+	atr.store.revokeTokens(accountId, purpose, now)
+	return nil
+}
+
+func (s *Store) useToken(purpose, token string, now time.Time) (int, error) {
+	for id, stored := range s.accountTokens {
+		if stored.Token == token && stored.Purpose == purpose && stored.UsedAt == nil && stored.ExpiresAt.After(now) {
+			usedAt := now
+			stored.UsedAt = &usedAt
+			s.accountTokens[id] = stored
+			return stored.AccountId, nil
+		}
+	}
+
+	return 0, sql.ErrNoRows
+}
+
+func (s *Store) revokeTokens(accountId int, purpose string, now time.Time) {
+	for id, stored := range s.accountTokens {
+		if stored.AccountId == accountId && stored.Purpose == purpose && stored.UsedAt == nil {
+			usedAt := now
+			stored.UsedAt = &usedAt
+			s.accountTokens[id] = stored
+		}
+	}
+}
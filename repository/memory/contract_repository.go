@@ -0,0 +1,304 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"time"
+)
+
+type ContractRepository struct {
+	store *Store
+}
+
+func NewContractRepository(store *Store) *ContractRepository {
+	return &ContractRepository{
+		store: store,
+	}
+}
+
+func (cr *ContractRepository) GetContract(playerId int) (domain.Contract, error) {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	contract, ok := cr.store.activeContract(playerId)
+
+	if !ok {
+		return domain.Contract{}, sql.ErrNoRows
+	}
+
+	return contract, nil
+}
+
+func (cr *ContractRepository) FindTeamContracts(teamId int) ([]domain.Contract, error) {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	contracts := cr.store.findContracts(func(contract domain.Contract) bool {
+		return contract.Status == domain.ContractActive && sameInt(cr.store.players[contract.PlayerId].TeamId, teamId)
+	})
+
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i].PlayerId < contracts[j].PlayerId })
+	return contracts, nil
+}
+
+func (cr *ContractRepository) FindDueWages(now time.Time) ([]domain.Contract, error) {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	contracts := cr.store.findContracts(func(contract domain.Contract) bool {
+		return contract.Status == domain.ContractActive && !contract.NextWageAt.After(now) && contract.NextWageAt.Before(contract.EndsAt)
+	})
+
+	sort.Slice(contracts, func(i, j int) bool {
+		if !contracts[i].NextWageAt.Equal(contracts[j].NextWageAt) {
+			return contracts[i].NextWageAt.Before(contracts[j].NextWageAt)
+		}
+
+		return contracts[i].Id < contracts[j].Id
+	})
+
+	return contracts, nil
+}
+
+func (cr *ContractRepository) RenewContract(teamId int, contract *domain.Contract, now time.Time) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	current, ok := cr.store.activeContract(contract.PlayerId)
+
+	if !ok || !sameInt(cr.store.players[contract.PlayerId].TeamId, teamId) {
+		return errors.New("contract not renewed")
+	}
+
+	cr.store.endContract(current, domain.ContractRenewed, now)
+	cr.store.signContract(contract)
+	return nil
+}
+
+func (cr *ContractRepository) PayWage(contract domain.Contract, nextWageAt, now time.Time) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	stored, ok := cr.store.contracts[contract.Id]
+
+	if !ok || stored.Status != domain.ContractActive || !stored.NextWageAt.Before(nextWageAt) {
+		return errors.New("wage already paid")
+	}
+
+	stored.NextWageAt = nextWageAt
+	cr.store.contracts[stored.Id] = stored
+
+	teamId := cr.store.players[stored.PlayerId].TeamId
+
+	if teamId == nil || stored.Wage <= 0 {
+		return nil
+	}
+
+	team := cr.store.teams[*teamId]
+	team.AvailableCash -= stored.Wage
+	cr.store.teams[team.Id] = team
+	cr.store.post(domain.LedgerWages, domain.ReferenceContract, stored.Id, teamAccount(team.Id), externalAccount, stored.Wage, now)
+	return nil
+}
+
+func (cr *ContractRepository) FindExpiredContractIds(now time.Time) (ids []int, err error) {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	expired := cr.store.findContracts(func(contract domain.Contract) bool {
+		return contract.Status == domain.ContractActive && !contract.EndsAt.After(now) && !cr.store.onLoan(contract.PlayerId)
+	})
+
+	sort.Slice(expired, func(i, j int) bool {
+		if !expired[i].EndsAt.Equal(expired[j].EndsAt) {
+			return expired[i].EndsAt.Before(expired[j].EndsAt)
+		}
+
+		return expired[i].Id < expired[j].Id
+	})
+
+	for _, contract := range expired {
+		ids = append(ids, contract.Id)
+	}
+
+	return ids, nil
+}
+
+func (cr *ContractRepository) ExpireContract(contractId int, now time.Time) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	contract, ok := cr.store.contracts[contractId]
+
+	if !ok || contract.Status != domain.ContractActive || contract.EndsAt.After(now) {
+		return errors.New("contract has not expired")
+	}
+
+	cr.store.expireListings(now, func(record transferRecord) bool { return record.playerId == contract.PlayerId })
+	cr.store.cancelOffers(contract.PlayerId, now)
+	cr.store.endContract(contract, domain.ContractExpired, now)
+
+	player := cr.store.players[contract.PlayerId]
+	player.TeamId = nil
+	cr.store.players[player.Id] = player
+	return nil
+}
+
+func (cr *ContractRepository) SignFreeAgent(teamId int, contract *domain.Contract, fee int, now time.Time) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	player, ok := cr.store.players[contract.PlayerId]
+	team, found := cr.store.teams[teamId]
+
+	if !ok || player.TeamId != nil || !found || team.AvailableCash < fee {
+		return errors.New("free agent not signed")
+	}
+
+	player.TeamId = &teamId
+	cr.store.players[player.Id] = player
+	cr.store.replaceContract(contract, now)
+
+	if fee == 0 {
+		return nil
+	}
+
+	team.AvailableCash -= fee
+	cr.store.teams[team.Id] = team
+	cr.store.post(domain.LedgerSigningFee, domain.ReferenceContract, contract.Id, teamAccount(team.Id), externalAccount, fee, now)
+	return nil
+}
+
+func (cr *ContractRepository) PayReleaseClause(buyerId, releaseClause, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	player, ok := cr.store.players[contract.PlayerId]
+	current, found := cr.store.activeContract(contract.PlayerId)
+
+	if !ok || !found || player.TeamId == nil || *player.TeamId == buyerId || !sameInt(current.ReleaseClause, releaseClause) ||
+		cr.store.onLoan(player.Id) {
+		return errors.New("release clause not paid")
+	}
+
+	if buyer, found := cr.store.teams[buyerId]; !found || buyer.AvailableCash < releaseClause {
+		return errors.New("transfer not executed")
+	}
+
+	if err := cr.store.withdrawListings(player.Id, now); err != nil {
+		return err
+	}
+
+	transferId := cr.store.nextId("transfer_list")
+	cr.store.transfers[transferId] = transferRecord{
+		id:          transferId,
+		playerId:    player.Id,
+		askedPrice:  releaseClause,
+		marketValue: player.MarketValue,
+		status:      domain.TransferListed,
+		mode:        domain.TransferReleaseClause,
+	}
+
+	if err := cr.store.completeTransfer(transferId, buyerId, releaseClause, newMarketValue, true, now); err != nil {
+		return err
+	}
+
+	cr.store.replaceContract(contract, now)
+	cr.store.cancelOffers(player.Id, now)
+	return nil
+}
+
+func (cr *ContractRepository) ReleasePlayer(teamId, playerId int, now time.Time) error {
+	cr.store.mu.Lock()
+	defer cr.store.mu.Unlock()
+
+	player, ok := cr.store.players[playerId]
+
+	if !ok || !sameInt(player.TeamId, teamId) {
+		return errors.New("player not in your team")
+	}
+
+	if cr.store.onLoan(playerId) {
+		return errors.New("loaned players cannot be released")
+	}
+
+	if err := cr.store.withdrawListings(playerId, now); err != nil {
+		return err
+	}
+
+	cr.store.cancelOffers(playerId, now)
+
+	for id, swap := range cr.store.swaps {
+		if swap.Status == domain.SwapProposed && sharesPlayer(swap, domain.Swap{Players: []domain.SwapPlayer{{PlayerId: playerId}}}) {
+			swap.Status = domain.SwapCancelled
+			swap.RespondedAt = &now
+			cr.store.swaps[id] = swap
+		}
+	}
+
+	if contract, ok := cr.store.activeContract(playerId); ok {
+		cr.store.endContract(contract, domain.ContractTerminated, now)
+	}
+
+	player.TeamId = nil
+	cr.store.players[playerId] = player
+	return nil
+}
+
+func (s *Store) activeContract(playerId int) (domain.Contract, bool) {
+	for _, contract := range s.contracts {
+		if contract.PlayerId == playerId && contract.Status == domain.ContractActive {
+			return copyContract(contract), true
+		}
+	}
+
+	return domain.Contract{}, false
+}
+
+func (s *Store) findContracts(match func(domain.Contract) bool) (contracts []domain.Contract) {
+	for _, contract := range s.contracts {
+		if match(contract) {
+			contracts = append(contracts, copyContract(contract))
+		}
+	}
+
+	return contracts
+}
+
+func (s *Store) signContract(contract *domain.Contract) {
+	contract.Id = s.nextId("contract")
+	contract.Status = domain.ContractActive
+	s.contracts[contract.Id] = copyContract(*contract)
+}
+
+func (s *Store) replaceContract(contract *domain.Contract, now time.Time) {
+	if current, ok := s.activeContract(contract.PlayerId); ok {
+		s.endContract(current, domain.ContractReplaced, now)
+	}
+
+	s.signContract(contract)
+}
+
+func (s *Store) endContract(contract domain.Contract, status string, now time.Time) {
+	contract.Status = status
+	contract.EndedAt = &now
+	s.contracts[contract.Id] = contract
+}
+
+func copyContract(contract domain.Contract) domain.Contract {
+	contract.ReleaseClause = copyInt(contract.ReleaseClause)
+	contract.EndedAt = copyTime(contract.EndedAt)
+	return contract
+}
+
+func copyTerms(terms *domain.ContractTerms) *domain.ContractTerms {
+	if terms == nil {
+		return nil
+	}
+
+	copied := *terms
+	copied.ReleaseClause = copyInt(terms.ReleaseClause)
+	return &copied
+}
@@ -0,0 +1,302 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/league"
+	"sort"
+	"strings"
+	"time"
+)
+
+type LeagueRepository struct {
+	store *Store
+}
+
+type leagueTeam struct {
+	leagueId   int
+	teamId     int
+	enrolledAt time.Time
+	standing   domain.Standing
+}
+
+func NewLeagueRepository(store *Store) *LeagueRepository {
+	return &LeagueRepository{
+		store: store,
+	}
+}
+
+func (lr *LeagueRepository) CreateLeague(l *domain.League) error {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	l.Id = lr.store.nextId("league")
+	lr.store.leagues[l.Id] = *l
+	return nil
+}
+
+func (lr *LeagueRepository) GetLeague(id int) (domain.League, error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	l, ok := lr.store.leagues[id]
+
+	if !ok {
+		return domain.League{}, sql.ErrNoRows
+	}
+
+	return l, nil
+}
+
+func (lr *LeagueRepository) FindLeagues() (leagues []domain.League, err error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	for _, l := range lr.store.leagues {
+		leagues = append(leagues, l)
+	}
+
+	sort.Slice(leagues, func(i, j int) bool { return leagues[i].Id < leagues[j].Id })
+	return leagues, nil
+}
+
+func (lr *LeagueRepository) EnrolTeam(leagueId, teamId int, now time.Time) error {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	if _, ok := lr.store.teams[teamId]; !ok {
+		return errors.New("team not found")
+	}
+
+	for _, lt := range lr.store.leagueTeams {
+		if lt.leagueId == leagueId && lt.teamId == teamId {
+			return errors.New("team already enrolled")
+		}
+	}
+
+	if l, ok := lr.store.leagues[leagueId]; !ok || l.Status != domain.LeagueOpen {
+		return errors.New("league is not open for enrolment")
+	}
+
+	lr.store.leagueTeams = append(lr.store.leagueTeams, leagueTeam{
+		leagueId:   leagueId,
+		teamId:     teamId,
+		enrolledAt: now,
+		standing:   domain.Standing{TeamId: teamId},
+	})
+
+	return nil
+}
+
+func (lr *LeagueRepository) FindLeagueTeamIds(leagueId int) (teamIds []int, err error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	for _, lt := range lr.store.leagueTeamsOf(leagueId) {
+		teamIds = append(teamIds, lt.teamId)
+	}
+
+	return teamIds, nil
+}
+
+func (s *Store) leagueTeamsOf(leagueId int) []*leagueTeam {
+	var teams []*leagueTeam
+
+	for i := range s.leagueTeams {
+		if s.leagueTeams[i].leagueId == leagueId {
+			teams = append(teams, &s.leagueTeams[i])
+		}
+	}
+
+	sort.SliceStable(teams, func(i, j int) bool {
+		if !teams[i].enrolledAt.Equal(teams[j].enrolledAt) {
+			return teams[i].enrolledAt.Before(teams[j].enrolledAt)
+		}
+
+		return teams[i].teamId < teams[j].teamId
+	})
+
+	return teams
+}
+
+func (lr *LeagueRepository) StartLeague(leagueId, rounds int, fixtures []domain.Fixture) error {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	l, ok := lr.store.leagues[leagueId]
+
+	if !ok || l.Status != domain.LeagueOpen {
+		return errors.New("league is not open")
+	}
+
+	for _, fixture := range fixtures {
+		if _, ok := lr.store.teams[fixture.HomeTeamId]; !ok {
+			return errors.New("team not found")
+		}
+
+		if _, ok := lr.store.teams[fixture.AwayTeamId]; !ok {
+			return errors.New("team not found")
+		}
+	}
+
+	for _, fixture := range fixtures {
+		fixture.Id = lr.store.nextId("fixture")
+		fixture.LeagueId = leagueId
+		lr.store.fixtures[fixture.Id] = domain.Fixture{
+			Id:         fixture.Id,
+			LeagueId:   leagueId,
+			Round:      fixture.Round,
+			HomeTeamId: fixture.HomeTeamId,
+			AwayTeamId: fixture.AwayTeamId,
+		}
+	}
+
+	l.Status = domain.LeagueRunning
+	l.Rounds = rounds
+	lr.store.leagues[leagueId] = l
+	return nil
+}
+
+func (lr *LeagueRepository) FindFixtures(leagueId, round int) (fixtures []domain.Fixture, err error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	for _, fixture := range lr.store.fixtures {
+		if fixture.LeagueId != leagueId || (round > 0 && fixture.Round != round) {
+			continue
+		}
+
+		fixture.HomeTeamName = lr.store.teams[fixture.HomeTeamId].Name
+		fixture.AwayTeamName = lr.store.teams[fixture.AwayTeamId].Name
+
+		if fixture.MatchId != nil {
+			if match, ok := lr.store.matches[*fixture.MatchId]; ok {
+				matchId, home, away, playedAt := match.Id, match.HomeGoals, match.AwayGoals, match.PlayedAt
+				fixture.MatchId, fixture.HomeGoals, fixture.AwayGoals, fixture.PlayedAt = &matchId, &home, &away, &playedAt
+			} else {
+				fixture.MatchId = nil
+			}
+		}
+
+		fixtures = append(fixtures, fixture)
+	}
+
+	sort.Slice(fixtures, func(i, j int) bool {
+		if fixtures[i].Round != fixtures[j].Round {
+			return fixtures[i].Round < fixtures[j].Round
+		}
+
+		return fixtures[i].Id < fixtures[j].Id
+	})
+
+	return fixtures, nil
+}
+
+func (lr *LeagueRepository) RecordRound(leagueId, round int, fixtures []domain.Fixture, finished bool) error {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	l, ok := lr.store.leagues[leagueId]
+
+	if !ok || l.Status != domain.LeagueRunning || l.CurrentRound != round-1 {
+		return errors.New("round already played")
+	}
+
+	for _, fixture := range fixtures {
+		stored, ok := lr.store.fixtures[fixture.Id]
+
+		if !ok || stored.MatchId != nil {
+			return errors.New("fixture already played")
+		}
+	}
+
+	for _, fixture := range fixtures {
+		if err := lr.store.createMatch(fixture.Match); err != nil {
+			return err
+		}
+
+		stored := lr.store.fixtures[fixture.Id]
+		stored.MatchId = &fixture.Match.Id
+		lr.store.fixtures[fixture.Id] = stored
+
+		home, away := fixture.Match.HomeGoals, fixture.Match.AwayGoals
+		lr.store.updateStanding(leagueId, fixture.HomeTeamId, home, away)
+		lr.store.updateStanding(leagueId, fixture.AwayTeamId, away, home)
+	}
+
+	l.CurrentRound = round
+	l.Status = domain.LeagueRunning
+
+	if finished {
+		l.Status = domain.LeagueFinished
+	}
+
+	lr.store.leagues[leagueId] = l
+	return nil
+}
+
+func (s *Store) updateStanding(leagueId, teamId, goalsFor, goalsAgainst int) {
+	for _, lt := range s.leagueTeamsOf(leagueId) {
+		if lt.teamId != teamId {
+			continue
+		}
+
+		result, points := league.Result(goalsFor, goalsAgainst)
+		standing := &lt.standing
+		standing.Played++
+		standing.GoalsFor += goalsFor
+		standing.GoalsAgainst += goalsAgainst
+		standing.Points += points
+		standing.Form = league.AppendForm(standing.Form, result)
+
+		switch result {
+		case "W":
+			standing.Won++
+		case "D":
+			standing.Drawn++
+		default:
+			standing.Lost++
+		}
+	}
+}
+
+func (lr *LeagueRepository) FindStandings(leagueId int) (standings []domain.Standing, err error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	for _, lt := range lr.store.leagueTeamsOf(leagueId) {
+		standing := lt.standing
+		standing.TeamName = lr.store.teams[lt.teamId].Name
+		standing.GoalDifference = standing.GoalsFor - standing.GoalsAgainst
+		standings = append(standings, standing)
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+
+		if a.GoalDifference != b.GoalDifference {
+			return a.GoalDifference > b.GoalDifference
+		}
+
+		if a.GoalsFor != b.GoalsFor {
+			return a.GoalsFor > b.GoalsFor
+		}
+
+		if !strings.EqualFold(a.TeamName, b.TeamName) {
+			return strings.ToLower(a.TeamName) < strings.ToLower(b.TeamName)
+		}
+
+		return a.TeamId < b.TeamId
+	})
+
+	for i := range standings {
+		standings[i].Position = i + 1
+	}
+
+	return standings, nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"time"
+)
+
+type LedgerRepository struct {
+	store *Store
+}
+
+func NewLedgerRepository(store *Store) *LedgerRepository {
+	return &LedgerRepository{
+		store: store,
+	}
+}
+
+func (lr *LedgerRepository) FindTeamTransactions(teamId int) (transactions []domain.LedgerTransaction, err error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	for _, transaction := range lr.store.ledger {
+		if sameInt(transaction.FromTeamId, teamId) || sameInt(transaction.ToTeamId, teamId) {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	return transactions, nil
+}
+
+func (lr *LedgerRepository) FindBalances() (balances []domain.LedgerBalance, err error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	for _, team := range lr.store.teams {
+		balance := domain.LedgerBalance{TeamId: team.Id, TeamName: team.Name, AvailableCash: team.AvailableCash}
+
+		for _, transaction := range lr.store.ledger {
+			if sameInt(transaction.ToTeamId, team.Id) {
+				balance.LedgerCash, balance.LedgerEscrow = credit(transaction.ToAccount, transaction.Amount, balance.LedgerCash, balance.LedgerEscrow)
+			}
+
+			if sameInt(transaction.FromTeamId, team.Id) {
+				balance.LedgerCash, balance.LedgerEscrow = credit(transaction.FromAccount, -transaction.Amount, balance.LedgerCash, balance.LedgerEscrow)
+			}
+		}
+
+		for _, bid := range lr.store.bids {
+			if bid.TeamId == team.Id && bid.Status == domain.BidActive {
+				balance.ActiveBids += bid.Amount
+			}
+		}
+
+		balances = append(balances, balance)
+	}
+
+	sort.Slice(balances, func(i, j int) bool { return balances[i].TeamId < balances[j].TeamId })
+	return balances, nil
+}
+
+func credit(account string, amount, cash, escrow int) (int, int) {
+	switch account {
+	case domain.LedgerTeam:
+		return cash + amount, escrow
+	case domain.LedgerEscrow:
+		return cash, escrow + amount
+	}
+
+	return cash, escrow
+}
+
+type ledgerAccount struct {
+	kind   string
+	teamId *int
+}
+
+func teamAccount(teamId int) ledgerAccount {
+	return ledgerAccount{kind: domain.LedgerTeam, teamId: &teamId}
+}
+
+func escrowAccount(teamId int) ledgerAccount {
+	return ledgerAccount{kind: domain.LedgerEscrow, teamId: &teamId}
+}
+
+var externalAccount = ledgerAccount{kind: domain.LedgerExternal}
+
+func (s *Store) post(category, referenceType string, referenceId int, from, to ledgerAccount, amount int, now time.Time) {
+	s.ledger = append(s.ledger, domain.LedgerTransaction{
+		Id:            s.nextId("ledger_transaction"),
+		Category:      category,
+		ReferenceType: referenceType,
+		ReferenceId:   &referenceId,
+		FromAccount:   from.kind,
+		FromTeamId:    from.teamId,
+		ToAccount:     to.kind,
+		ToTeamId:      to.teamId,
+		Amount:        amount,
+		CreatedAt:     now,
+	})
+}
+
+func (s *Store) detachLedger(teamId int) {
+	for i, transaction := range s.ledger {
+		if sameInt(transaction.FromTeamId, teamId) {
+			s.ledger[i].FromTeamId = nil
+		}
+
+		if sameInt(transaction.ToTeamId, teamId) {
+			s.ledger[i].ToTeamId = nil
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+type LoanRepository struct {
+	store *Store
+}
+
+func NewLoanRepository(store *Store) *LoanRepository {
+	return &LoanRepository{
+		store: store,
+	}
+}
+
+func (lr *LoanRepository) CreateLoan(loan *domain.Loan) error {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	player, ok := lr.store.players[loan.PlayerId]
+
+	if _, borrowerFound := lr.store.teams[loan.BorrowerId]; !ok || !borrowerFound || !sameInt(player.TeamId, loan.LenderId) ||
+		loan.LenderId == loan.BorrowerId || lr.store.onLoan(loan.PlayerId) {
+		return errors.New("player cannot be loaned by the lending team")
+	}
+
+	loan.Id = lr.store.nextId("loan")
+	loan.Status = domain.LoanProposed
+	loan.StartedAt, loan.EndsAt, loan.EndedAt, loan.TransferId = nil, nil, nil, nil
+
+	stored := *loan
+	stored.DurationDays = copyInt(loan.DurationDays)
+	stored.DurationRounds = copyInt(loan.DurationRounds)
+	stored.BuyOptionPrice = copyInt(loan.BuyOptionPrice)
+	lr.store.loans[loan.Id] = stored
+	return nil
+}
+
+func (lr *LoanRepository) GetLoan(id int) (domain.Loan, error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	loan, ok := lr.store.loans[id]
+
+	if !ok {
+		return domain.Loan{}, sql.ErrNoRows
+	}
+
+	return lr.store.describeLoan(loan), nil
+}
+
+func (lr *LoanRepository) FindLoans(teamId int, status string) (loans []domain.Loan, err error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	for _, loan := range lr.store.loans {
+		if (loan.LenderId == teamId || loan.BorrowerId == teamId) && (status == "" || loan.Status == status) {
+			loans = append(loans, lr.store.describeLoan(loan))
+		}
+	}
+
+	sort.Slice(loans, func(i, j int) bool {
+		if !loans[i].ProposedAt.Equal(loans[j].ProposedAt) {
+			return loans[i].ProposedAt.After(loans[j].ProposedAt)
+		}
+
+		return loans[i].Id > loans[j].Id
+	})
+
+	return loans, nil
+}
+
+func (lr *LoanRepository) StartLoan(loanId, lenderId int, now time.Time) error {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	loan, ok := lr.store.loans[loanId]
+	player := lr.store.players[loan.PlayerId]
+
+	if !ok || loan.Status != domain.LoanProposed || loan.LenderId != lenderId || !sameInt(player.TeamId, lenderId) {
+		return errors.New("loan is no longer open")
+	}
+
+	borrower, lender := lr.store.teams[loan.BorrowerId], lr.store.teams[loan.LenderId]
+
+	if borrower.AvailableCash < loan.Fee {
+		return errors.New("loan not started")
+	}
+
+	if err := lr.store.withdrawListings(loan.PlayerId, now); err != nil {
+		return err
+	}
+
+	lr.store.cancelOffers(loan.PlayerId, now)
+
+	borrower.AvailableCash -= loan.Fee
+	lr.store.teams[borrower.Id] = borrower
+	lender.AvailableCash += loan.Fee
+	lr.store.teams[lender.Id] = lender
+
+	if loan.Fee > 0 {
+		lr.store.post(domain.LedgerLoanFee, domain.ReferenceLoan, loan.Id, teamAccount(borrower.Id), teamAccount(lender.Id), loan.Fee, now)
+	}
+
+	player.TeamId = &borrower.Id
+	lr.store.players[player.Id] = player
+
+	for id, other := range lr.store.loans {
+		if other.PlayerId == loan.PlayerId && other.Status == domain.LoanProposed {
+			other.Status = domain.LoanCancelled
+			other.EndedAt = &now
+			lr.store.loans[id] = other
+		}
+	}
+
+	loan.Status = domain.LoanActive
+	loan.StartedAt = &now
+	loan.EndedAt = nil
+
+	if loan.DurationDays != nil {
+		end := now.AddDate(0, 0, *loan.DurationDays)
+		loan.EndsAt = &end
+	}
+
+	lr.store.loans[loanId] = loan
+	return nil
+}
+
+var loanCloseStatuses = map[string]bool{
+	domain.LoanRejected:  true,
+	domain.LoanWithdrawn: true,
+}
+
+func (lr *LoanRepository) CloseLoan(loanId, teamId int, status string, now time.Time) error {
+	if !loanCloseStatuses[status] {
+		return errors.New("invalid loan status")
+	}
+
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	loan, ok := lr.store.loans[loanId]
+
+	if !ok || loan.Status != domain.LoanProposed || (loan.LenderId != teamId && loan.BorrowerId != teamId) {
+		return errors.New("loan is no longer open")
+	}
+
+	loan.Status = status
+	loan.EndedAt = &now
+	lr.store.loans[loanId] = loan
+	return nil
+}
+
+func (lr *LoanRepository) ReturnLoan(loanId int, now time.Time) error {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	loan, ok := lr.store.loans[loanId]
+
+	if !ok || loan.Status != domain.LoanActive {
+		return errors.New("loan is not active")
+	}
+
+	if player := lr.store.players[loan.PlayerId]; sameInt(player.TeamId, loan.BorrowerId) {
+		lenderId := loan.LenderId
+		player.TeamId = &lenderId
+		lr.store.players[player.Id] = player
+	}
+
+	loan.Status = domain.LoanReturned
+	loan.EndedAt = &now
+	lr.store.loans[loanId] = loan
+	return nil
+}
+
+func (lr *LoanRepository) BuyLoanedPlayer(loanId, borrowerId, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	loan, ok := lr.store.loans[loanId]
+	player := lr.store.players[loan.PlayerId]
+
+	if !ok || loan.Status != domain.LoanActive || loan.BorrowerId != borrowerId || loan.BuyOptionPrice == nil ||
+		!sameInt(player.TeamId, borrowerId) {
+		return errors.New("loan has no buy option to exercise")
+	}
+
+	if lr.store.teams[borrowerId].AvailableCash < *loan.BuyOptionPrice {
+		return errors.New("transfer not executed")
+	}
+
+	lenderId := loan.LenderId
+	player.TeamId = &lenderId
+	lr.store.players[player.Id] = player
+
+	transferId := lr.store.nextId("transfer_list")
+	lr.store.transfers[transferId] = transferRecord{
+		id:          transferId,
+		playerId:    loan.PlayerId,
+		askedPrice:  *loan.BuyOptionPrice,
+		marketValue: player.MarketValue,
+		status:      domain.TransferListed,
+		mode:        domain.TransferLoanOption,
+	}
+
+	if err := lr.store.completeTransfer(transferId, borrowerId, *loan.BuyOptionPrice, newMarketValue, true, now); err != nil {
+		return err
+	}
+
+	contract.PlayerId = loan.PlayerId
+	lr.store.replaceContract(contract, now)
+
+	loan.Status = domain.LoanBought
+	loan.EndedAt = &now
+	loan.TransferId = &transferId
+	lr.store.loans[loanId] = loan
+	return nil
+}
+
+func (lr *LoanRepository) FindDueLoanIds(now time.Time) (ids []int, err error) {
+	lr.store.mu.Lock()
+	defer lr.store.mu.Unlock()
+
+	for id, loan := range lr.store.loans {
+		if loan.Status != domain.LoanActive {
+			continue
+		}
+
+		if (loan.EndsAt != nil && !loan.EndsAt.After(now)) ||
+			(loan.DurationRounds != nil && lr.store.roundsPlayed(loan) >= *loan.DurationRounds) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (s *Store) onLoan(playerId int) bool {
+	for _, loan := range s.loans {
+		if loan.PlayerId == playerId && loan.Status == domain.LoanActive {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Store) roundsPlayed(loan domain.Loan) (rounds int) {
+	if loan.StartedAt == nil {
+		return 0
+	}
+
+	for _, match := range s.matches {
+		if (sameInt(match.HomeTeamId, loan.BorrowerId) || sameInt(match.AwayTeamId, loan.BorrowerId)) && !match.PlayedAt.Before(*loan.StartedAt) &&
+			(loan.EndedAt == nil || match.PlayedAt.Before(*loan.EndedAt)) {
+			rounds++
+		}
+	}
+
+	return rounds
+}
+
+func (s *Store) describeLoan(loan domain.Loan) domain.Loan {
+	player := s.players[loan.PlayerId]
+	loan.PlayerName = strings.TrimSpace(player.FirstName + " " + player.LastName)
+	loan.LenderName = s.teams[loan.LenderId].Name
+	loan.BorrowerName = s.teams[loan.BorrowerId].Name
+	loan.RoundsPlayed = s.roundsPlayed(loan)
+	loan.DurationDays = copyInt(loan.DurationDays)
+	loan.DurationRounds = copyInt(loan.DurationRounds)
+	loan.BuyOptionPrice = copyInt(loan.BuyOptionPrice)
+	loan.TransferId = copyInt(loan.TransferId)
+	loan.StartedAt = copyTime(loan.StartedAt)
+	loan.EndsAt = copyTime(loan.EndsAt)
+	loan.EndedAt = copyTime(loan.EndedAt)
+	return loan
+}
+
+func (s *Store) deleteLoans(match func(domain.Loan) bool) {
+	for id, loan := range s.loans {
+		if match(loan) {
+			delete(s.loans, id)
+		}
+	}
+}
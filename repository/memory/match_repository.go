@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+)
+
+type MatchRepository struct {
+	store *Store
+}
+
+func NewMatchRepository(store *Store) *MatchRepository {
+	return &MatchRepository{
+		store: store,
+	}
+}
+
+func (mr *MatchRepository) CreateMatch(match *domain.Match) error {
+	mr.store.mu.Lock()
+	defer mr.store.mu.Unlock()
+
+	return mr.store.createMatch(match)
+}
+
+func (s *Store) createMatch(match *domain.Match) error {
+	if _, ok := s.teams[*match.HomeTeamId]; match.HomeTeamId == nil || !ok {
+		return errors.New("home team not found")
+	}
+
+	if _, ok := s.teams[*match.AwayTeamId]; match.AwayTeamId == nil || !ok {
+		return errors.New("away team not found")
+	}
+
+	match.Id = s.nextId("match_result")
+	s.matches[match.Id] = copyMatch(*match)
+	return nil
+}
+
+func (mr *MatchRepository) GetMatch(id int) (domain.Match, error) {
+	mr.store.mu.Lock()
+	defer mr.store.mu.Unlock()
+
+	match, ok := mr.store.matches[id]
+
+	if !ok {
+		return domain.Match{}, sql.ErrNoRows
+	}
+
+	return mr.store.withTeamNames(copyMatch(match)), nil
+}
+
+func (mr *MatchRepository) FindMatchesByTeamId(teamId int) (matches []domain.Match, err error) {
+	mr.store.mu.Lock()
+	defer mr.store.mu.Unlock()
+
+	for _, match := range mr.store.matches {
+		if sameInt(match.HomeTeamId, teamId) || sameInt(match.AwayTeamId, teamId) {
+			match.Events = nil
+			match.Ratings = nil
+			matches = append(matches, mr.store.withTeamNames(match))
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].PlayedAt.Equal(matches[j].PlayedAt) {
+			return matches[i].PlayedAt.After(matches[j].PlayedAt)
+		}
+
+		return matches[i].Id > matches[j].Id
+	})
+
+	return matches, nil
+}
+
+func (s *Store) withTeamNames(match domain.Match) domain.Match {
+	match.HomeTeamName = s.teamName(match.HomeTeamId)
+	match.AwayTeamName = s.teamName(match.AwayTeamId)
+	return match
+}
+
+func (s *Store) teamName(teamId *int) string {
+	if teamId == nil {
+		return ""
+	}
+
+	return s.teams[*teamId].Name
+}
+
+func copyMatch(match domain.Match) domain.Match {
+	var events []domain.MatchEvent
+
+	for _, event := range match.Events {
+		event.RelatedPlayerId = copyInt(event.RelatedPlayerId)
+		events = append(events, event)
+	}
+
+	match.HomeTeamId = copyInt(match.HomeTeamId)
+	match.AwayTeamId = copyInt(match.AwayTeamId)
+	match.Events = events
+	match.Ratings = append([]domain.PlayerRating(nil), match.Ratings...)
+	return match
+}
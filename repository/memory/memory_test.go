@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/repository/repositorytest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.Repositories {
+		return NewRepositories()
+	})
+}
@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+type OfferRepository struct {
+	store *Store
+}
+
+func NewOfferRepository(store *Store) *OfferRepository {
+	return &OfferRepository{
+		store: store,
+	}
+}
+
+func (ofr *OfferRepository) CreateOffer(offer *domain.Offer) error {
+	ofr.store.mu.Lock()
+	defer ofr.store.mu.Unlock()
+
+	player, ok := ofr.store.players[offer.PlayerId]
+
+	if _, buyerFound := ofr.store.teams[offer.BuyerId]; !ok || !buyerFound || !sameInt(player.TeamId, offer.SellerId) || offer.SellerId == offer.BuyerId ||
+		ofr.store.onLoan(offer.PlayerId) {
+		return errors.New("player does not belong to the selling team")
+	}
+
+	offer.Id = ofr.store.nextId("transfer_offer")
+	offer.Status = domain.OfferOpen
+	offer.AwaitingTeamId = offer.SellerId
+	offer.UpdatedAt = offer.CreatedAt
+	offer.TransferId = nil
+
+	stored := *offer
+	stored.Contract = copyTerms(offer.Contract)
+	stored.Events = []domain.OfferEvent{{TeamId: offer.BuyerId, Action: domain.OfferActionOffer, Amount: offer.Amount, CreatedAt: offer.CreatedAt}}
+	ofr.store.offers[offer.Id] = stored
+	return nil
+}
+
+func (ofr *OfferRepository) GetOffer(id int) (domain.Offer, error) {
+	ofr.store.mu.Lock()
+	defer ofr.store.mu.Unlock()
+
+	offer, ok := ofr.store.offers[id]
+
+	if !ok {
+		return domain.Offer{}, sql.ErrNoRows
+	}
+
+	return ofr.store.describeOffer(offer, true), nil
+}
+
+func (ofr *OfferRepository) FindOffers(teamId int, status string) (offers []domain.Offer, err error) {
+	ofr.store.mu.Lock()
+	defer ofr.store.mu.Unlock()
+
+	for _, offer := range ofr.store.offers {
+		if (offer.BuyerId == teamId || offer.SellerId == teamId) && (status == "" || offer.Status == status) {
+			offers = append(offers, ofr.store.describeOffer(offer, false))
+		}
+	}
+
+	sort.Slice(offers, func(i, j int) bool {
+		if !offers[i].UpdatedAt.Equal(offers[j].UpdatedAt) {
+			return offers[i].UpdatedAt.After(offers[j].UpdatedAt)
+		}
+
+		return offers[i].Id > offers[j].Id
+	})
+
+	return offers, nil
+}
+
+func (ofr *OfferRepository) CounterOffer(offerId, teamId, amount int, expiresAt, now time.Time) error {
+	ofr.store.mu.Lock()
+	defer ofr.store.mu.Unlock()
+
+	offer, ok := ofr.store.openOffer(offerId, now)
+
+	if !ok || offer.AwaitingTeamId != teamId {
+		return errors.New("offer is no longer open")
+	}
+
+	offer.Amount = amount
+	offer.ExpiresAt = expiresAt
+	offer.UpdatedAt = now
+	offer.AwaitingTeamId = offer.BuyerId
+
+	if teamId == offer.BuyerId {
+		offer.AwaitingTeamId = offer.SellerId
+	}
+
+	offer.Events = append(offer.Events, domain.OfferEvent{TeamId: teamId, Action: domain.OfferActionCounter, Amount: amount, CreatedAt: now})
+	ofr.store.offers[offerId] = offer
+	return nil
+}
+
+var offerCloseActions = map[string]string{
+	domain.OfferRejected:  domain.OfferActionReject,
+	domain.OfferWithdrawn: domain.OfferActionWithdraw,
+}
+
+func (ofr *OfferRepository) CloseOffer(offerId, teamId int, status string, now time.Time) error {
+	action, ok := offerCloseActions[status]
+
+	if !ok {
+		return errors.New("invalid offer status")
+	}
+
+	ofr.store.mu.Lock()
+	defer ofr.store.mu.Unlock()
+
+	offer, ok := ofr.store.openOffer(offerId, now)
+
+	if !ok || (offer.BuyerId != teamId && offer.SellerId != teamId) {
+		return errors.New("offer is no longer open")
+	}
+
+	offer.Status = status
+	offer.UpdatedAt = now
+	offer.Events = append(offer.Events, domain.OfferEvent{TeamId: teamId, Action: action, Amount: offer.Amount, CreatedAt: now})
+	ofr.store.offers[offerId] = offer
+	return nil
+}
+
+func (ofr *OfferRepository) AcceptOffer(offerId, teamId, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	ofr.store.mu.Lock()
+	defer ofr.store.mu.Unlock()
+
+	offer, ok := ofr.store.openOffer(offerId, now)
+
+	if !ok || offer.AwaitingTeamId != teamId || !sameInt(ofr.store.players[offer.PlayerId].TeamId, offer.SellerId) {
+		return errors.New("offer is no longer open")
+	}
+
+	if buyer := ofr.store.teams[offer.BuyerId]; buyer.AvailableCash < offer.Amount {
+		return errors.New("transfer not executed")
+	}
+
+	if err := ofr.store.withdrawListings(offer.PlayerId, now); err != nil {
+		return err
+	}
+
+	transferId := ofr.store.nextId("transfer_list")
+	ofr.store.transfers[transferId] = transferRecord{
+		id:          transferId,
+		playerId:    offer.PlayerId,
+		askedPrice:  offer.Amount,
+		marketValue: ofr.store.players[offer.PlayerId].MarketValue,
+		status:      domain.TransferListed,
+		mode:        domain.TransferDirectOffer,
+	}
+
+	if err := ofr.store.completeTransfer(transferId, offer.BuyerId, offer.Amount, newMarketValue, true, now); err != nil {
+		return err
+	}
+
+	contract.PlayerId = offer.PlayerId
+	ofr.store.replaceContract(contract, now)
+
+	offer.Status = domain.OfferAccepted
+	offer.TransferId = &transferId
+	offer.UpdatedAt = now
+	offer.Events = append(offer.Events, domain.OfferEvent{TeamId: teamId, Action: domain.OfferActionAccept, Amount: offer.Amount, CreatedAt: now})
+	ofr.store.offers[offerId] = offer
+	ofr.store.cancelOffers(offer.PlayerId, now)
+	return nil
+}
+
+func (s *Store) withdrawListings(playerId int, now time.Time) error {
+	for _, record := range s.transfers {
+		if record.playerId == playerId && record.status == domain.TransferListed && record.mode == domain.TransferAuction {
+			return errors.New("player is being auctioned")
+		}
+	}
+
+	s.markListingsWithdrawn(playerId, now)
+	return nil
+}
+
+func (s *Store) releaseListings(playerId int, now time.Time) {
+	for transferId, record := range s.transfers {
+		if record.playerId == playerId && record.status == domain.TransferListed && record.mode == domain.TransferAuction {
+			s.refundBids(transferId, now)
+		}
+	}
+
+	s.markListingsWithdrawn(playerId, now)
+}
+
+func (s *Store) markListingsWithdrawn(playerId int, now time.Time) {
+	for transferId, record := range s.transfers {
+		if record.playerId == playerId && record.status == domain.TransferListed {
+			record.status = domain.TransferWithdrawn
+			record.withdrawnAt = &now
+			record.transferredFrom = copyInt(s.players[playerId].TeamId)
+			s.transfers[transferId] = record
+		}
+	}
+}
+
+func (s *Store) cancelOffers(playerId int, now time.Time) {
+	for id, offer := range s.offers {
+		if offer.PlayerId == playerId && offer.Status == domain.OfferOpen {
+			offer.Status = domain.OfferCancelled
+			offer.UpdatedAt = now
+			s.offers[id] = offer
+		}
+	}
+}
+
+func (s *Store) openOffer(offerId int, now time.Time) (domain.Offer, bool) {
+	offer, ok := s.offers[offerId]
+	return offer, ok && offer.Status == domain.OfferOpen && offer.ExpiresAt.After(now)
+}
+
+func (s *Store) describeOffer(offer domain.Offer, withEvents bool) domain.Offer {
+	player := s.players[offer.PlayerId]
+	offer.PlayerName = strings.TrimSpace(player.FirstName + " " + player.LastName)
+	offer.BuyerName = s.teams[offer.BuyerId].Name
+	offer.SellerName = s.teams[offer.SellerId].Name
+	offer.TransferId = copyInt(offer.TransferId)
+	offer.Contract = copyTerms(offer.Contract)
+	offer.Events = append([]domain.OfferEvent(nil), offer.Events...)
+
+	if !withEvents {
+		offer.Events = nil
+	}
+
+	return offer
+}
+
+func (s *Store) deleteOffers(match func(domain.Offer) bool) {
+	for id, offer := range s.offers {
+		if match(offer) {
+			delete(s.offers, id)
+		}
+	}
+}
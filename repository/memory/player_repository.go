@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+type PlayerRepository struct {
+	store *Store
+}
+
+func NewPlayerRepository(store *Store) *PlayerRepository {
+	return &PlayerRepository{
+		store: store,
+	}
+}
+
+func (pr *PlayerRepository) GetPlayer(id int) (domain.Player, error) {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	player, ok := pr.store.players[id]
+
+	if !ok {
+		return domain.Player{}, sql.ErrNoRows
+	}
+
+	return copyPlayer(player), nil
+}
+
+func (pr *PlayerRepository) GetPlayerOutOfTransferList(accountId, playerId int) (domain.Player, error) {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	player, ok := pr.store.players[playerId]
+
+	if !ok || player.TeamId == nil || pr.store.teams[*player.TeamId].AccountId != accountId || pr.store.isListed(playerId) ||
+		pr.store.onLoan(playerId) {
+		return domain.Player{}, errors.New("Invalid player id or it is already in the transfer list")
+	}
+
+	return copyPlayer(player), nil
+}
+
+func (pr *PlayerRepository) FindPlayers() ([]domain.Player, error) {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	return pr.store.findPlayers(func(domain.Player) bool { return true }), nil
+}
+
+func (pr *PlayerRepository) GetPlayersByTeamId(teamId int) ([]domain.Player, error) {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	return pr.store.findPlayers(func(player domain.Player) bool { return sameInt(player.TeamId, teamId) }), nil
+}
+
+func (pr *PlayerRepository) FindFreeAgents(filter domain.FreeAgentFilter) ([]domain.Player, error) {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	players := pr.store.findPlayers(func(player domain.Player) bool {
+		return player.TeamId == nil && matchesFreeAgentFilter(player, filter)
+	})
+
+	sort.SliceStable(players, func(i, j int) bool { return players[i].MarketValue > players[j].MarketValue })
+
+	if len(players) > filter.Limit {
+		players = players[:filter.Limit]
+	}
+
+	return players, nil
+}
+
+func (pr *PlayerRepository) NewPlayer(player *domain.Player) error {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	pr.store.createPlayer(player)
+	return nil
+}
+
+func (pr *PlayerRepository) UpdatePlayer(accountId int, player *domain.Player) error {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	stored, ok := pr.store.players[player.Id]
+
+	if !ok || stored.TeamId == nil || pr.store.teams[*stored.TeamId].AccountId != accountId {
+		return nil
+	}
+
+	stored.FirstName = player.FirstName
+	stored.LastName = player.LastName
+	stored.Country = player.Country
+	pr.store.players[player.Id] = stored
+	return nil
+}
+
+func (pr *PlayerRepository) UpdatePlayerById(player *domain.Player) error {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	if _, ok := pr.store.players[player.Id]; !ok {
+		return nil
+	}
+
+	pr.store.players[player.Id] = copyPlayer(*player)
+	return nil
+}
+
+func (pr *PlayerRepository) DeletePlayer(id int, now time.Time) error {
+	pr.store.mu.Lock()
+	defer pr.store.mu.Unlock()
+
+	if _, ok := pr.store.players[id]; !ok {
+		return sql.ErrNoRows
+	}
+
+	pr.store.releaseListings(id, now)
+
+	for transferId, transfer := range pr.store.transfers {
+		if transfer.playerId == id {
+			transfer.playerId = 0
+			pr.store.transfers[transferId] = transfer
+		}
+	}
+
+	pr.store.deleteOffers(func(offer domain.Offer) bool { return offer.PlayerId == id })
+	pr.store.deleteLoans(func(loan domain.Loan) bool { return loan.PlayerId == id })
+	pr.store.deleteSwapPlayers(id)
+
+	for contractId, contract := range pr.store.contracts {
+		if contract.PlayerId == id {
+			delete(pr.store.contracts, contractId)
+		}
+	}
+
+	delete(pr.store.players, id)
+	return nil
+}
+
+func (s *Store) createPlayer(player *domain.Player) {
+	player.Id = s.nextId("player")
+	s.players[player.Id] = copyPlayer(*player)
+
+	if player.Contract != nil && player.TeamId != nil {
+		player.Contract.PlayerId = player.Id
+		s.signContract(player.Contract)
+	}
+}
+
+func (s *Store) findPlayers(match func(domain.Player) bool) (players []domain.Player) {
+	for _, player := range s.players {
+		if match(player) {
+			players = append(players, copyPlayer(player))
+		}
+	}
+
+	sort.Slice(players, func(i, j int) bool { return players[i].Id < players[j].Id })
+	return players
+}
+
+func matchesFreeAgentFilter(player domain.Player, filter domain.FreeAgentFilter) bool {
+	if filter.Country != "" && !strings.EqualFold(player.Country, filter.Country) {
+		return false
+	}
+
+	for _, name := range strings.Fields(filter.PlayerName) {
+		if !containsFold(player.FirstName, name) && !containsFold(player.LastName, name) {
+			return false
+		}
+	}
+
+	if filter.Position != "" && player.Position != filter.Position {
+		return false
+	}
+
+	return inRange(int(player.Age), filter.MinAge, filter.MaxAge) && inRange(player.MarketValue, filter.MinMarketValue, filter.MaxMarketValue)
+}
+
+func copyPlayer(player domain.Player) domain.Player {
+	player.TeamId = copyInt(player.TeamId)
+	player.Contract = nil
+	return player
+}
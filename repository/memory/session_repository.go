@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+type SessionRepository struct {
+	store *Store
+}
+
+func NewSessionRepository(store *Store) *SessionRepository {
+	return &SessionRepository{
+		store: store,
+	}
+}
+
+func (sr *SessionRepository) CreateRefreshToken(refreshToken *domain.RefreshToken) error {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	for _, stored := range sr.store.refreshTokens {
+		if stored.Token == refreshToken.Token {
+			return errors.New("duplicate refresh token")
+		}
+	}
+
+	refreshToken.Id = sr.store.nextId("refresh_token")
+	sr.store.refreshTokens[refreshToken.Id] = copyRefreshToken(*refreshToken)
+	return nil
+}
+
+func (sr *SessionRepository) UseRefreshToken(token string, now time.Time) (domain.RefreshToken, bool, error) {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	for id, stored := range sr.store.refreshTokens {
+		if stored.Token != token {
+			continue
+		}
+
+		if stored.UsedAt == nil && stored.RevokedAt == nil && stored.ExpiresAt.After(now) {
+			usedAt := now
+			stored.UsedAt = &usedAt
+			sr.store.refreshTokens[id] = stored
+			return copyRefreshToken(stored), true, nil
+		}
+
+		return copyRefreshToken(stored), false, nil
+	}
+
+	return domain.RefreshToken{}, false, sql.ErrNoRows
+}
+
+func (sr *SessionRepository) RevokeFamily(family string, now time.Time) error {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	sr.store.revokeFamily(family, now)
+	return nil
+}
+
+func (sr *SessionRepository) RevokeAccountSessions(accountId int, now time.Time) error {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	for _, stored := range sr.store.refreshTokens {
+		if stored.AccountId == accountId && stored.RevokedAt == nil {
+			sr.store.revokeFamily(stored.Family, now)
+		}
+	}
+
+	return nil
+}
+
+func (sr *SessionRepository) RevokeSession(accessTokenId string, accountId int, accessExpiresAt, now time.Time) error {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	sr.store.revokeAccessToken(accessTokenId, accountId, accessExpiresAt, now)
+
+	for _, stored := range sr.store.refreshTokens {
+		if stored.AccessTokenId == accessTokenId {
+			sr.store.revokeFamily(stored.Family, now)
+		}
+	}
+
+	for tokenId, revoked := range sr.store.revokedTokens {
+		if revoked.expiresAt.Before(now) {
+			delete(sr.store.revokedTokens, tokenId)
+		}
+	}
+
+	return nil
+}
+
+func (sr *SessionRepository) IsAccessTokenRevoked(accessTokenId string) (bool, error) {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	_, ok := sr.store.revokedTokens[accessTokenId]
+	return ok, nil
+}
+
+func (s *Store) revokeFamily(family string, now time.Time) {
+	for id, stored := range s.refreshTokens {
+		if stored.Family != family {
+			continue
+		}
+
+		if stored.AccessExpiresAt.After(now) {
+			s.revokeAccessToken(stored.AccessTokenId, stored.AccountId, stored.AccessExpiresAt, now)
+		}
+
+		if stored.RevokedAt == nil {
+			revokedAt := now
+			stored.RevokedAt = &revokedAt
+			s.refreshTokens[id] = stored
+		}
+	}
+}
+
+func (s *Store) revokeAccessToken(accessTokenId string, accountId int, expiresAt, now time.Time) {
+	if _, ok := s.revokedTokens[accessTokenId]; !ok {
+		s.revokedTokens[accessTokenId] = revokedToken{
+			accountId: accountId,
+			expiresAt: expiresAt,
+			revokedAt: now,
+		}
+	}
+}
+
+func copyRefreshToken(refreshToken domain.RefreshToken) domain.RefreshToken {
+	refreshToken.UsedAt = copyTime(refreshToken.UsedAt)
+	refreshToken.RevokedAt = copyTime(refreshToken.RevokedAt)
+	return refreshToken
+}
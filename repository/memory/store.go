@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"sync"
+	"time"
+)
+
+var (
+	_ repository.PlayerRepository         = (*PlayerRepository)(nil)
+	_ repository.TeamRepository           = (*TeamRepository)(nil)
+	_ repository.AccountRepository        = (*AccountRepository)(nil)
+	_ repository.TransferRepository       = (*TransferRepository)(nil)
+	_ repository.OfferRepository          = (*OfferRepository)(nil)
+	_ repository.LoanRepository           = (*LoanRepository)(nil)
+	_ repository.SwapRepository           = (*SwapRepository)(nil)
+	_ repository.TransferWindowRepository = (*TransferWindowRepository)(nil)
+	_ repository.LedgerRepository         = (*LedgerRepository)(nil)
+	_ repository.ContractRepository       = (*ContractRepository)(nil)
+	_ repository.AccountTokenRepository   = (*AccountTokenRepository)(nil)
+	_ repository.SessionRepository        = (*SessionRepository)(nil)
+	_ repository.MatchRepository          = (*MatchRepository)(nil)
+	_ repository.LeagueRepository         = (*LeagueRepository)(nil)
+)
+
+type Store struct {
+	mu            sync.Mutex
+	sequences     map[string]int
+	accounts      map[int]domain.Account
+	teams         map[int]domain.Team
+	players       map[int]domain.Player
+	transfers     map[int]transferRecord
+	bids          map[int]domain.Bid
+	lockEvents    []domain.AccountLockEvent
+	accountTokens map[int]domain.AccountToken
+	refreshTokens map[int]domain.RefreshToken
+	revokedTokens map[string]revokedToken
+	matches       map[int]domain.Match
+	leagues       map[int]domain.League
+	leagueTeams   []leagueTeam
+	fixtures      map[int]domain.Fixture
+	offers        map[int]domain.Offer
+	loans         map[int]domain.Loan
+	swaps         map[int]domain.Swap
+	windows       map[int]domain.TransferWindow
+	ledger        []domain.LedgerTransaction
+	contracts     map[int]domain.Contract
+}
+
+type transferRecord struct {
+	id              int
+	playerId        int
+	askedPrice      int
+	marketValue     int
+	transferredFrom *int
+	transferredTo   *int
+	status          string
+	withdrawnAt     *time.Time
+	transferredAt   *time.Time
+	valueAfter      *int
+	mode            string
+	deadline        time.Time
+	sealed          bool
+	highestBid      *int
+	bidCount        int
+}
+
+type revokedToken struct {
+	accountId int
+	expiresAt time.Time
+	revokedAt time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		sequences:     make(map[string]int),
+		accounts:      make(map[int]domain.Account),
+		teams:         make(map[int]domain.Team),
+		players:       make(map[int]domain.Player),
+		transfers:     make(map[int]transferRecord),
+		bids:          make(map[int]domain.Bid),
+		accountTokens: make(map[int]domain.AccountToken),
+		refreshTokens: make(map[int]domain.RefreshToken),
+		revokedTokens: make(map[string]revokedToken),
+		matches:       make(map[int]domain.Match),
+		leagues:       make(map[int]domain.League),
+		fixtures:      make(map[int]domain.Fixture),
+		offers:        make(map[int]domain.Offer),
+		loans:         make(map[int]domain.Loan),
+		swaps:         make(map[int]domain.Swap),
+		windows:       make(map[int]domain.TransferWindow),
+		contracts:     make(map[int]domain.Contract),
+	}
+}
+
+func NewRepositories() repository.Repositories {
+	store := NewStore()
+	playerRepository := NewPlayerRepository(store)
+	teamRepository := NewTeamRepository(store)
+
+	return repository.Repositories{
+		Players:       playerRepository,
+		Teams:         teamRepository,
+		Accounts:      NewAccountRepository(store, teamRepository, playerRepository),
+		Transfers:     NewTransferRepository(store),
+		AccountTokens: NewAccountTokenRepository(store),
+		Sessions:      NewSessionRepository(store),
+		Matches:       NewMatchRepository(store),
+		Leagues:       NewLeagueRepository(store),
+		Offers:        NewOfferRepository(store),
+		Loans:         NewLoanRepository(store),
+		Swaps:         NewSwapRepository(store),
+		Windows:       NewTransferWindowRepository(store),
+		Ledger:        NewLedgerRepository(store),
+		Contracts:     NewContractRepository(store),
+	}
+}
+
+func (s *Store) nextId(table string) int {
+	s.sequences[table]++
+	return s.sequences[table]
+}
+
+func copyInt(v *int) *int {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	return &c
+}
+
+func copyTime(v *time.Time) *time.Time {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	return &c
+}
+
+func sameInt(a *int, b int) bool {
+	return a != nil && *a == b
+}
@@ -0,0 +1,272 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+type SwapRepository struct {
+	store *Store
+}
+
+func NewSwapRepository(store *Store) *SwapRepository {
+	return &SwapRepository{
+		store: store,
+	}
+}
+
+func (sr *SwapRepository) CreateSwap(swap *domain.Swap) error {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	_, proposerFound := sr.store.teams[swap.ProposerId]
+	_, receiverFound := sr.store.teams[swap.ReceiverId]
+
+	if !proposerFound || !receiverFound {
+		return sql.ErrNoRows
+	}
+
+	for _, player := range swap.Players {
+		if player.FromTeamId != swap.ProposerId && player.FromTeamId != swap.ReceiverId {
+			return fmt.Errorf("player %d is not part of either team", player.PlayerId)
+		}
+
+		if !sameInt(sr.store.players[player.PlayerId].TeamId, player.FromTeamId) || sr.store.onLoan(player.PlayerId) {
+			return fmt.Errorf("player %d cannot be swapped by team %d", player.PlayerId, player.FromTeamId)
+		}
+	}
+
+	swap.Id = sr.store.nextId("swap_deal")
+	swap.Status = domain.SwapProposed
+	swap.RespondedAt = nil
+
+	stored := *swap
+	stored.Players = make([]domain.SwapPlayer, 0, len(swap.Players))
+
+	for _, player := range swap.Players {
+		stored.Players = append(stored.Players, domain.SwapPlayer{PlayerId: player.PlayerId, FromTeamId: player.FromTeamId})
+	}
+
+	sr.store.swaps[swap.Id] = stored
+	return nil
+}
+
+func (sr *SwapRepository) GetSwap(id int) (domain.Swap, error) {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	swap, ok := sr.store.swaps[id]
+
+	if !ok {
+		return domain.Swap{}, sql.ErrNoRows
+	}
+
+	return sr.store.describeSwap(swap), nil
+}
+
+func (sr *SwapRepository) FindSwaps(teamId int, status string) (swaps []domain.Swap, err error) {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	for _, swap := range sr.store.swaps {
+		if (swap.ProposerId == teamId || swap.ReceiverId == teamId) && (status == "" || swap.Status == status) {
+			swaps = append(swaps, sr.store.describeSwap(swap))
+		}
+	}
+
+	sort.Slice(swaps, func(i, j int) bool {
+		if !swaps[i].ProposedAt.Equal(swaps[j].ProposedAt) {
+			return swaps[i].ProposedAt.After(swaps[j].ProposedAt)
+		}
+
+		return swaps[i].Id > swaps[j].Id
+	})
+
+	return swaps, nil
+}
+
+var swapCloseStatuses = map[string]bool{
+	domain.SwapRejected:  true,
+	domain.SwapWithdrawn: true,
+}
+
+func (sr *SwapRepository) CloseSwap(swapId, teamId int, status string, now time.Time) error {
+	if !swapCloseStatuses[status] {
+		return errors.New("invalid swap status")
+	}
+
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	swap, ok := sr.store.swaps[swapId]
+
+	if !ok || swap.Status != domain.SwapProposed || (swap.ProposerId != teamId && swap.ReceiverId != teamId) {
+		return errors.New("swap is no longer open")
+	}
+
+	swap.Status = status
+	swap.RespondedAt = &now
+	sr.store.swaps[swapId] = swap
+	return nil
+}
+
+func (sr *SwapRepository) CompleteSwap(swapId, receiverId int, newMarketValues map[int]int, contracts map[int]*domain.Contract, now time.Time) error {
+	sr.store.mu.Lock()
+	defer sr.store.mu.Unlock()
+
+	swap, ok := sr.store.swaps[swapId]
+
+	if !ok || swap.Status != domain.SwapProposed || swap.ReceiverId != receiverId {
+		return errors.New("swap is no longer open")
+	}
+
+	sides := map[int]bool{}
+
+	for _, player := range swap.Players {
+		if !sameInt(sr.store.players[player.PlayerId].TeamId, player.FromTeamId) || sr.store.onLoan(player.PlayerId) {
+			return fmt.Errorf("player %d no longer belongs to team %d", player.PlayerId, player.FromTeamId)
+		}
+
+		if _, ok = newMarketValues[player.PlayerId]; !ok {
+			return fmt.Errorf("missing market value for player %d", player.PlayerId)
+		}
+
+		if _, ok = contracts[player.PlayerId]; !ok {
+			return fmt.Errorf("missing contract for player %d", player.PlayerId)
+		}
+
+		for _, record := range sr.store.transfers {
+			if record.playerId == player.PlayerId && record.status == domain.TransferListed && record.mode == domain.TransferAuction {
+				return errors.New("player is being auctioned")
+			}
+		}
+
+		sides[player.FromTeamId] = true
+	}
+
+	if !sides[swap.ProposerId] || !sides[swap.ReceiverId] {
+		return errors.New("swap must exchange players from both teams")
+	}
+
+	payerId, payeeId, amount := swap.ProposerId, swap.ReceiverId, swap.CashAdjustment
+
+	if amount < 0 {
+		payerId, payeeId, amount = swap.ReceiverId, swap.ProposerId, -amount
+	}
+
+	payer, payee := sr.store.teams[payerId], sr.store.teams[payeeId]
+
+	if payer.AvailableCash < amount {
+		return errors.New("insufficient funds for the cash adjustment")
+	}
+
+	for i, player := range swap.Players {
+		_ = sr.store.withdrawListings(player.PlayerId, now)
+		sr.store.cancelOffers(player.PlayerId, now)
+
+		buyerId := swap.ProposerId
+
+		if player.FromTeamId == swap.ProposerId {
+			buyerId = swap.ReceiverId
+		}
+
+		marketValue := sr.store.players[player.PlayerId].MarketValue
+		transferId := sr.store.nextId("transfer_list")
+		sr.store.transfers[transferId] = transferRecord{
+			id:          transferId,
+			playerId:    player.PlayerId,
+			askedPrice:  marketValue,
+			marketValue: marketValue,
+			status:      domain.TransferListed,
+			mode:        domain.TransferSwap,
+		}
+
+		sr.store.recordTransfer(transferId, player.FromTeamId, buyerId, marketValue, newMarketValues[player.PlayerId], now)
+		swap.Players[i].TransferId = &transferId
+
+		contract := contracts[player.PlayerId]
+		contract.PlayerId = player.PlayerId
+		sr.store.replaceContract(contract, now)
+	}
+
+	payer.AvailableCash -= amount
+	sr.store.teams[payerId] = payer
+	payee.AvailableCash += amount
+	sr.store.teams[payeeId] = payee
+
+	if amount > 0 {
+		sr.store.post(domain.LedgerSwapAdjustment, domain.ReferenceSwap, swapId, teamAccount(payerId), teamAccount(payeeId), amount, now)
+	}
+
+	swap.Status = domain.SwapCompleted
+	swap.RespondedAt = &now
+	sr.store.swaps[swapId] = swap
+
+	for id, other := range sr.store.swaps {
+		if other.Status == domain.SwapProposed && sharesPlayer(other, swap) {
+			other.Status = domain.SwapCancelled
+			other.RespondedAt = &now
+			sr.store.swaps[id] = other
+		}
+	}
+
+	return nil
+}
+
+func sharesPlayer(a, b domain.Swap) bool {
+	for _, pa := range a.Players {
+		for _, pb := range b.Players {
+			if pa.PlayerId == pb.PlayerId {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (s *Store) describeSwap(swap domain.Swap) domain.Swap {
+	swap.ProposerName = s.teams[swap.ProposerId].Name
+	swap.ReceiverName = s.teams[swap.ReceiverId].Name
+	swap.RespondedAt = copyTime(swap.RespondedAt)
+	players := make([]domain.SwapPlayer, 0, len(swap.Players))
+
+	for _, player := range swap.Players {
+		stored := s.players[player.PlayerId]
+		player.PlayerName = strings.TrimSpace(stored.FirstName + " " + stored.LastName)
+		player.MarketValue = stored.MarketValue
+		player.TransferId = copyInt(player.TransferId)
+		players = append(players, player)
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].FromTeamId != players[j].FromTeamId {
+			return players[i].FromTeamId < players[j].FromTeamId
+		}
+
+		return players[i].PlayerId < players[j].PlayerId
+	})
+
+	swap.Players = players
+	return swap
+}
+
+func (s *Store) deleteSwapPlayers(playerId int) {
+	for id, swap := range s.swaps {
+		players := swap.Players[:0:0]
+
+		for _, player := range swap.Players {
+			if player.PlayerId != playerId {
+				players = append(players, player)
+			}
+		}
+
+		swap.Players = players
+		s.swaps[id] = swap
+	}
+}
@@ -0,0 +1,265 @@
+package memory
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"math"
+	"sort"
+	"time"
+)
+
+type TeamRepository struct {
+	store *Store
+}
+
+func NewTeamRepository(store *Store) *TeamRepository {
+	return &TeamRepository{
+		store: store,
+	}
+}
+
+func (tr *TeamRepository) GetTeamById(id int) (*domain.Team, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	team, ok := tr.store.teams[id]
+
+	if !ok {
+		return &domain.Team{}, sql.ErrNoRows
+	}
+
+	return &team, nil
+}
+
+func (tr *TeamRepository) GetTeamByAccountId(id int) (*domain.Team, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	team, ok := tr.store.teamByAccountId(id)
+
+	if !ok {
+		return &domain.Team{}, sql.ErrNoRows
+	}
+
+	return &team, nil
+}
+
+func (tr *TeamRepository) FindTeams() (teams []domain.Team, err error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	for _, team := range tr.store.teams {
+		teams = append(teams, team)
+	}
+
+	sort.Slice(teams, func(i, j int) bool { return teams[i].Id < teams[j].Id })
+	return teams, nil
+}
+
+func (tr *TeamRepository) GetSquadSummary(teamId int) (domain.SquadSummary, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	totalAge := 0
+	summary := domain.SquadSummary{
+		Positions: map[domain.PlayerPosition]int{domain.GoalKeeper: 0, domain.Defender: 0, domain.Midfielder: 0, domain.Forward: 0},
+	}
+
+	for _, player := range tr.store.players {
+		if !sameInt(player.TeamId, teamId) {
+			continue
+		}
+
+		summary.Players++
+		summary.Value += player.MarketValue
+		summary.Positions[player.Position]++
+		totalAge += int(player.Age)
+	}
+
+	for _, transfer := range tr.store.transfers {
+		if player, ok := tr.store.players[transfer.playerId]; ok && transfer.status == domain.TransferListed && sameInt(player.TeamId, teamId) {
+			summary.ListedPlayers++
+			summary.ListedAskingPrice += transfer.askedPrice
+		}
+	}
+
+	if summary.Players > 0 {
+		summary.AverageAge = math.Round(float64(totalAge)/float64(summary.Players)*10) / 10
+	}
+
+	return summary, nil
+}
+
+func (tr *TeamRepository) NewTeam(team *domain.Team, now time.Time) error {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	tr.store.createTeam(team, now)
+	return nil
+}
+
+func (tr *TeamRepository) UpdateTeam(accountId int, team *domain.Team) error {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	stored, ok := tr.store.teams[team.Id]
+
+	if !ok || stored.AccountId != accountId {
+		return nil
+	}
+
+	stored.Name = team.Name
+	stored.Country = team.Country
+	tr.store.teams[team.Id] = stored
+	return nil
+}
+
+func (tr *TeamRepository) UpdateTeamById(team *domain.Team, now time.Time) error {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	stored, ok := tr.store.teams[team.Id]
+
+	if !ok {
+		return nil
+	}
+
+	stored.Name = team.Name
+	stored.Country = team.Country
+
+	switch {
+	case team.AvailableCash > stored.AvailableCash:
+		tr.store.post(domain.LedgerAdminAdjustment, domain.ReferenceTeam, team.Id, externalAccount, teamAccount(team.Id), team.AvailableCash-stored.AvailableCash, now)
+	case team.AvailableCash < stored.AvailableCash:
+		tr.store.post(domain.LedgerAdminAdjustment, domain.ReferenceTeam, team.Id, teamAccount(team.Id), externalAccount, stored.AvailableCash-team.AvailableCash, now)
+	}
+
+	stored.AvailableCash = team.AvailableCash
+	stored.AccountId = team.AccountId
+	tr.store.teams[team.Id] = stored
+	return nil
+}
+
+func (tr *TeamRepository) DeleteTeam(id int, now time.Time) error {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	return tr.store.deleteTeam(id, now)
+}
+
+func (s *Store) createTeam(team *domain.Team, now time.Time) {
+	team.Id = s.nextId("team")
+	stored := *team
+	stored.Players = nil
+	s.teams[team.Id] = stored
+
+	if team.AvailableCash > 0 {
+		s.post(domain.LedgerOpeningBalance, domain.ReferenceTeam, team.Id, externalAccount, teamAccount(team.Id), team.AvailableCash, now)
+	} else if team.AvailableCash < 0 {
+		s.post(domain.LedgerOpeningBalance, domain.ReferenceTeam, team.Id, teamAccount(team.Id), externalAccount, -team.AvailableCash, now)
+	}
+}
+
+func (s *Store) teamByAccountId(accountId int) (domain.Team, bool) {
+	for _, team := range s.teams {
+		if team.AccountId == accountId {
+			return team, true
+		}
+	}
+
+	return domain.Team{}, false
+}
+
+func (s *Store) deleteTeam(id int, now time.Time) error {
+	if _, ok := s.teams[id]; !ok {
+		return sql.ErrNoRows
+	}
+
+	for _, lt := range s.leagueTeams {
+		if lt.teamId == id && s.leagues[lt.leagueId].Status == domain.LeagueRunning {
+			return repository.ErrTeamInRunningLeague
+		}
+	}
+
+	s.withdrawTeamBids(id, now)
+	s.deleteOffers(func(offer domain.Offer) bool { return offer.BuyerId == id || offer.SellerId == id })
+
+	for _, loan := range s.loans {
+		if loan.BorrowerId == id && loan.Status == domain.LoanActive && sameInt(s.players[loan.PlayerId].TeamId, id) {
+			lenderId := loan.LenderId
+			player := s.players[loan.PlayerId]
+			player.TeamId = &lenderId
+			s.players[player.Id] = player
+		}
+	}
+
+	s.deleteLoans(func(loan domain.Loan) bool { return loan.LenderId == id || loan.BorrowerId == id })
+
+	for swapId, swap := range s.swaps {
+		if swap.ProposerId == id || swap.ReceiverId == id {
+			delete(s.swaps, swapId)
+		}
+	}
+
+	for playerId, player := range s.players {
+		if sameInt(player.TeamId, id) {
+			s.releaseListings(playerId, now)
+		}
+	}
+
+	for transferId, transfer := range s.transfers {
+		if sameInt(transfer.transferredFrom, id) {
+			transfer.transferredFrom = nil
+		}
+
+		if sameInt(transfer.transferredTo, id) {
+			transfer.transferredTo = nil
+		}
+
+		s.transfers[transferId] = transfer
+	}
+
+	for playerId, player := range s.players {
+		if sameInt(player.TeamId, id) {
+			if contract, ok := s.activeContract(playerId); ok {
+				s.endContract(contract, domain.ContractTerminated, now)
+			}
+
+			player.TeamId = nil
+			s.players[playerId] = player
+		}
+	}
+
+	for matchId, match := range s.matches {
+		if sameInt(match.HomeTeamId, id) {
+			match.HomeTeamId = nil
+		}
+
+		if sameInt(match.AwayTeamId, id) {
+			match.AwayTeamId = nil
+		}
+
+		s.matches[matchId] = match
+	}
+
+	for fixtureId, fixture := range s.fixtures {
+		if fixture.HomeTeamId == id || fixture.AwayTeamId == id {
+			delete(s.fixtures, fixtureId)
+		}
+	}
+
+	leagueTeams := s.leagueTeams[:0]
+
+	for _, lt := range s.leagueTeams {
+		if lt.teamId != id {
+			leagueTeams = append(leagueTeams, lt)
+		}
+	}
+
+	s.leagueTeams = leagueTeams
+	s.detachLedger(id)
+
+	delete(s.teams, id)
+	return nil
+}
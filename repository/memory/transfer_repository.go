@@ -0,0 +1,700 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+type TransferRepository struct {
+	store *Store
+}
+
+func NewTransferRepository(store *Store) *TransferRepository {
+	return &TransferRepository{
+		store: store,
+	}
+}
+
+func (tr *TransferRepository) NewTransfer(playerId, askedPrice, marketValue int, expiresAt *time.Time) (int, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	if _, ok := tr.store.players[playerId]; !ok {
+		return 0, errors.New("player not found")
+	}
+
+	id := tr.store.nextId("transfer_list")
+	record := transferRecord{
+		id:          id,
+		playerId:    playerId,
+		askedPrice:  askedPrice,
+		marketValue: marketValue,
+		status:      domain.TransferListed,
+		mode:        domain.TransferFixedPrice,
+	}
+
+	if expiresAt != nil {
+		record.deadline = *expiresAt
+	}
+
+	tr.store.transfers[id] = record
+	return id, nil
+}
+
+var transferSortValues = map[string]func(domain.Transfer) int{
+	"id":          func(t domain.Transfer) int { return t.Id },
+	"askedPrice":  func(t domain.Transfer) int { return t.AskedPrice },
+	"marketValue": func(t domain.Transfer) int { return t.MarketValue },
+	"age":         func(t domain.Transfer) int { return int(t.Player.Age) },
+}
+
+func (tr *TransferRepository) FindTransfers(filter domain.TransferFilter) ([]domain.Transfer, int, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	value, ok := transferSortValues[filter.SortBy]
+
+	if !ok {
+		value = transferSortValues["id"]
+	}
+
+	before := func(a, b domain.Transfer) bool {
+		if value(a) != value(b) {
+			return (value(a) < value(b)) != filter.Descending
+		}
+
+		return a.Id != b.Id && (a.Id < b.Id) != filter.Descending
+	}
+
+	var matches []domain.Transfer
+
+	for _, record := range tr.store.transfers {
+		transfer, ok := tr.store.activeTransfer(record)
+
+		if ok && matchesTransferFilter(transfer, filter) {
+			matches = append(matches, transfer)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return before(matches[i], matches[j]) })
+
+	var page []domain.Transfer
+
+	for _, transfer := range matches {
+		if filter.After != nil && !before(transferAt(filter.SortBy, filter.After.Value, filter.After.Id), transfer) {
+			continue
+		}
+
+		if len(page) == filter.Limit {
+			break
+		}
+
+		page = append(page, transfer)
+	}
+
+	return page, len(matches), nil
+}
+
+func (tr *TransferRepository) GetTransfer(id int) (domain.Transfer, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	record, ok := tr.store.transfers[id]
+
+	if !ok {
+		return domain.Transfer{}, sql.ErrNoRows
+	}
+
+	transfer, ok := tr.store.activeTransfer(record)
+
+	if !ok {
+		return domain.Transfer{}, sql.ErrNoRows
+	}
+
+	return transfer, nil
+}
+
+func (tr *TransferRepository) ConfirmTransfer(transferId int, buyerId int, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	record, ok := tr.store.transfers[transferId]
+
+	if !ok || record.mode != domain.TransferFixedPrice || (!record.deadline.IsZero() && !record.deadline.After(now)) {
+		return errors.New("transfer not executed")
+	}
+
+	if err := tr.store.completeTransfer(transferId, buyerId, record.askedPrice, newMarketValue, true, now); err != nil {
+		return err
+	}
+
+	contract.PlayerId = record.playerId
+	tr.store.replaceContract(contract, now)
+	return nil
+}
+
+func (s *Store) completeTransfer(transferId, buyerId, price, newMarketValue int, chargeBuyer bool, now time.Time) error {
+	record, ok := s.transfers[transferId]
+	player := s.players[record.playerId]
+	buyer, buyerFound := s.teams[buyerId]
+
+	if !ok || record.status != domain.TransferListed || player.TeamId == nil || !buyerFound || *player.TeamId == buyerId ||
+		(chargeBuyer && buyer.AvailableCash < price) {
+		return errors.New("transfer not executed")
+	}
+
+	seller := s.teams[*player.TeamId]
+	seller.AvailableCash += price
+	s.teams[seller.Id] = seller
+
+	if chargeBuyer {
+		buyer.AvailableCash -= price
+		s.teams[buyer.Id] = buyer
+	} else if price > 0 {
+		s.post(domain.LedgerBidReleased, domain.ReferenceTransfer, transferId, escrowAccount(buyer.Id), teamAccount(buyer.Id), price, now)
+	}
+
+	if price > 0 {
+		s.post(domain.LedgerTransferFee, domain.ReferenceTransfer, transferId, teamAccount(buyer.Id), teamAccount(seller.Id), price, now)
+	}
+
+	s.recordTransfer(transferId, seller.Id, buyer.Id, price, newMarketValue, now)
+	return nil
+}
+
+func (s *Store) recordTransfer(transferId, sellerId, buyerId, price, newMarketValue int, now time.Time) {
+	record := s.transfers[transferId]
+	record.askedPrice = price
+	record.transferredFrom = &sellerId
+	record.transferredTo = &buyerId
+	record.status = domain.TransferTransferred
+	record.transferredAt = &now
+	record.valueAfter = &newMarketValue
+	s.transfers[transferId] = record
+
+	player := s.players[record.playerId]
+	player.TeamId = &buyerId
+	player.MarketValue = newMarketValue
+	s.players[player.Id] = player
+}
+
+func (tr *TransferRepository) FindCompletedTransfers(filter domain.TransferHistoryFilter) (transfers []domain.CompletedTransfer, err error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	for _, record := range tr.store.transfers {
+		if !matchesHistoryFilter(record, filter) {
+			continue
+		}
+
+		player := tr.store.players[record.playerId]
+		transfer := domain.CompletedTransfer{
+			Id:            record.id,
+			PlayerId:      record.playerId,
+			PlayerName:    strings.TrimSpace(player.FirstName + " " + player.LastName),
+			FromTeamId:    copyInt(record.transferredFrom),
+			ToTeamId:      copyInt(record.transferredTo),
+			Price:         record.askedPrice,
+			ValueBefore:   record.marketValue,
+			ValueAfter:    copyInt(record.valueAfter),
+			TransferredAt: copyTime(record.transferredAt),
+			Status:        record.status,
+		}
+
+		if record.status == domain.TransferExpired && !record.deadline.IsZero() {
+			expiredAt := record.deadline
+			transfer.ExpiredAt = &expiredAt
+		}
+
+		if record.status == domain.TransferWithdrawn {
+			transfer.WithdrawnAt = copyTime(record.withdrawnAt)
+		}
+
+		if transfer.FromTeamId != nil {
+			transfer.FromTeamName = tr.store.teams[*transfer.FromTeamId].Name
+		}
+
+		if transfer.ToTeamId != nil {
+			transfer.ToTeamName = tr.store.teams[*transfer.ToTeamId].Name
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		a, b := historyTime(transfers[i]), historyTime(transfers[j])
+
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.After(*b)
+		}
+
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+
+		return transfers[i].Id > transfers[j].Id
+	})
+
+	if filter.Limit > 0 && len(transfers) > filter.Limit {
+		transfers = transfers[:filter.Limit]
+	}
+
+	return transfers, nil
+}
+
+func historyTime(transfer domain.CompletedTransfer) *time.Time {
+	if transfer.TransferredAt != nil {
+		return transfer.TransferredAt
+	}
+
+	if transfer.WithdrawnAt != nil {
+		return transfer.WithdrawnAt
+	}
+
+	return transfer.ExpiredAt
+}
+
+func matchesHistoryFilter(record transferRecord, filter domain.TransferHistoryFilter) bool {
+	if filter.Status != "" && record.status != filter.Status {
+		return false
+	}
+
+	if record.status != domain.TransferTransferred && record.status != domain.TransferExpired && record.status != domain.TransferWithdrawn {
+		return false
+	}
+
+	if filter.TeamId > 0 && !sameInt(record.transferredFrom, filter.TeamId) && !sameInt(record.transferredTo, filter.TeamId) {
+		return false
+	}
+
+	if filter.PlayerId > 0 && record.playerId != filter.PlayerId {
+		return false
+	}
+
+	at := record.transferredAt
+
+	if at == nil {
+		at = record.withdrawnAt
+	}
+
+	if at == nil && !record.deadline.IsZero() {
+		at = &record.deadline
+	}
+
+	if filter.From != nil && (at == nil || at.Before(*filter.From)) {
+		return false
+	}
+
+	return filter.To == nil || (at != nil && at.Before(*filter.To))
+}
+
+func (tr *TransferRepository) UpdateTransfer(accountId int, transfer *domain.Transfer) error {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	record, ok := tr.store.transfers[transfer.Id]
+
+	if !ok || record.status != domain.TransferListed || record.mode != domain.TransferFixedPrice {
+		return nil
+	}
+
+	player := tr.store.players[record.playerId]
+
+	if player.TeamId == nil || tr.store.teams[*player.TeamId].AccountId != accountId {
+		return nil
+	}
+
+	record.askedPrice = transfer.AskedPrice
+	tr.store.transfers[transfer.Id] = record
+	return nil
+}
+
+func (tr *TransferRepository) WithdrawTransfer(transferId, sellerId int, now time.Time) error {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	record, ok := tr.store.transfers[transferId]
+
+	if !ok || record.status != domain.TransferListed || record.bidCount > 0 || !sameInt(tr.store.players[record.playerId].TeamId, sellerId) {
+		return errors.New("transfer is no longer available")
+	}
+
+	record.status = domain.TransferWithdrawn
+	record.withdrawnAt = &now
+	record.transferredFrom = &sellerId
+	tr.store.transfers[transferId] = record
+	return nil
+}
+
+func (tr *TransferRepository) NewAuction(playerId, reservePrice, marketValue int, auction domain.Auction) (int, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	if _, ok := tr.store.players[playerId]; !ok {
+		return 0, errors.New("player not found")
+	}
+
+	id := tr.store.nextId("transfer_list")
+	tr.store.transfers[id] = transferRecord{
+		id:          id,
+		playerId:    playerId,
+		askedPrice:  reservePrice,
+		marketValue: marketValue,
+		status:      domain.TransferListed,
+		mode:        domain.TransferAuction,
+		deadline:    auction.Deadline,
+		sealed:      auction.Sealed,
+	}
+
+	return id, nil
+}
+
+func (tr *TransferRepository) PlaceBid(transferId, teamId, amount int, terms domain.ContractTerms, now time.Time) (domain.Bid, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	record, ok := tr.store.transfers[transferId]
+
+	if !ok || record.status != domain.TransferListed || record.mode != domain.TransferAuction || !now.Before(record.deadline) ||
+		amount < record.askedPrice || sameInt(tr.store.players[record.playerId].TeamId, teamId) ||
+		(!record.sealed && record.highestBid != nil && amount <= *record.highestBid) {
+		return domain.Bid{}, errors.New("bid not accepted")
+	}
+
+	bid, previous := tr.store.activeBid(transferId, teamId)
+
+	if amount <= previous {
+		return domain.Bid{}, errors.New("bid must raise your previous bid")
+	}
+
+	team, ok := tr.store.teams[teamId]
+
+	if !ok || team.AvailableCash < amount-previous {
+		return domain.Bid{}, errors.New("insufficient funds")
+	}
+
+	team.AvailableCash -= amount - previous
+	tr.store.teams[teamId] = team
+	tr.store.post(domain.LedgerBidReserved, domain.ReferenceTransfer, transferId, teamAccount(teamId), escrowAccount(teamId), amount-previous, now)
+
+	if bid.Id == 0 {
+		bid = domain.Bid{Id: tr.store.nextId("transfer_bid"), TransferId: transferId, TeamId: teamId, Status: domain.BidActive}
+	}
+
+	bid.Amount = amount
+	bid.PlacedAt = now
+	bid.Contract = copyTerms(&terms)
+	tr.store.bids[bid.Id] = bid
+
+	record.bidCount++
+
+	if record.highestBid == nil || *record.highestBid < amount {
+		record.highestBid = &amount
+	}
+
+	tr.store.transfers[transferId] = record
+	bid.Contract = copyTerms(bid.Contract)
+	return bid, nil
+}
+
+func (tr *TransferRepository) FindBids(transferId int) (bids []domain.Bid, err error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	for _, bid := range tr.store.bids {
+		if bid.TransferId == transferId {
+			bid.TeamName = tr.store.teams[bid.TeamId].Name
+			bid.Contract = copyTerms(bid.Contract)
+			bids = append(bids, bid)
+		}
+	}
+
+	sort.Slice(bids, func(i, j int) bool {
+		if bids[i].Amount != bids[j].Amount {
+			return bids[i].Amount > bids[j].Amount
+		}
+
+		if !bids[i].PlacedAt.Equal(bids[j].PlacedAt) {
+			return bids[i].PlacedAt.Before(bids[j].PlacedAt)
+		}
+
+		return bids[i].Id < bids[j].Id
+	})
+
+	return bids, nil
+}
+
+func (tr *TransferRepository) FindDueAuctionIds(now time.Time) (ids []int, err error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	var due []transferRecord
+
+	for _, record := range tr.store.transfers {
+		if record.mode == domain.TransferAuction && record.status == domain.TransferListed && !record.deadline.After(now) {
+			due = append(due, record)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].deadline.Equal(due[j].deadline) {
+			return due[i].deadline.Before(due[j].deadline)
+		}
+
+		return due[i].id < due[j].id
+	})
+
+	for _, record := range due {
+		ids = append(ids, record.id)
+	}
+
+	return ids, nil
+}
+
+func (tr *TransferRepository) SettleAuction(transferId, bidId, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	record, ok := tr.store.transfers[transferId]
+
+	if !ok || record.status != domain.TransferListed || record.mode != domain.TransferAuction || record.deadline.After(now) {
+		return errors.New("auction not settled")
+	}
+
+	if bidId == 0 {
+		record.status = domain.TransferExpired
+		record.transferredFrom = copyInt(tr.store.players[record.playerId].TeamId)
+		tr.store.transfers[transferId] = record
+	} else {
+		bid, ok := tr.store.bids[bidId]
+
+		if !ok || bid.TransferId != transferId || bid.Status != domain.BidActive {
+			return errors.New("auction not settled")
+		}
+
+		if err := tr.store.completeTransfer(transferId, bid.TeamId, bid.Amount, newMarketValue, false, now); err != nil {
+			return err
+		}
+
+		contract.PlayerId = record.playerId
+		tr.store.replaceContract(contract, now)
+
+		bid.Status = domain.BidWon
+		tr.store.bids[bidId] = bid
+	}
+
+	tr.store.refundBids(transferId, now)
+	return nil
+}
+
+func (s *Store) activeBid(transferId, teamId int) (domain.Bid, int) {
+	for _, bid := range s.bids {
+		if bid.TransferId == transferId && bid.TeamId == teamId && bid.Status == domain.BidActive {
+			return bid, bid.Amount
+		}
+	}
+
+	return domain.Bid{}, 0
+}
+
+func (s *Store) refundBids(transferId int, now time.Time) {
+	s.releaseBids(func(bid domain.Bid) bool { return bid.TransferId == transferId }, now)
+}
+
+func (s *Store) releaseBids(match func(domain.Bid) bool, now time.Time) {
+	bidIds := make([]int, 0)
+
+	for bidId, bid := range s.bids {
+		if match(bid) && bid.Status == domain.BidActive {
+			bidIds = append(bidIds, bidId)
+		}
+	}
+
+	sort.Ints(bidIds)
+
+	for _, bidId := range bidIds {
+		bid := s.bids[bidId]
+
+		if team, ok := s.teams[bid.TeamId]; ok {
+			team.AvailableCash += bid.Amount
+			s.teams[team.Id] = team
+			s.post(domain.LedgerBidReleased, domain.ReferenceTransfer, bid.TransferId, escrowAccount(team.Id), teamAccount(team.Id), bid.Amount, now)
+		}
+
+		bid.Status = domain.BidRefunded
+		s.bids[bidId] = bid
+	}
+}
+
+func (s *Store) withdrawTeamBids(teamId int, now time.Time) {
+	s.releaseBids(func(bid domain.Bid) bool { return bid.TeamId == teamId }, now)
+
+	for bidId, bid := range s.bids {
+		if bid.TeamId != teamId {
+			continue
+		}
+
+		delete(s.bids, bidId)
+		record := s.transfers[bid.TransferId]
+		record.highestBid = nil
+
+		for _, other := range s.bids {
+			if other.TransferId == record.id && (record.highestBid == nil || other.Amount > *record.highestBid) {
+				amount := other.Amount
+				record.highestBid = &amount
+			}
+		}
+
+		s.transfers[record.id] = record
+	}
+}
+
+func (tr *TransferRepository) IsPlayerListed(playerId int) (bool, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	return tr.store.isListed(playerId), nil
+}
+
+func (s *Store) isListed(playerId int) bool {
+	for _, transfer := range s.transfers {
+		if transfer.playerId == playerId && transfer.status == domain.TransferListed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Store) activeTransfer(record transferRecord) (domain.Transfer, bool) {
+	player, ok := s.players[record.playerId]
+
+	if record.status != domain.TransferListed || !ok || player.TeamId == nil {
+		return domain.Transfer{}, false
+	}
+
+	team, ok := s.teams[*player.TeamId]
+
+	if !ok {
+		return domain.Transfer{}, false
+	}
+
+	transfer := domain.Transfer{
+		Id:          record.id,
+		Player:      copyPlayer(player),
+		TeamName:    team.Name,
+		MarketValue: record.marketValue,
+		AskedPrice:  record.askedPrice,
+		Status:      record.status,
+		Mode:        record.mode,
+	}
+
+	if record.mode == domain.TransferAuction {
+		transfer.Auction = &domain.Auction{Deadline: record.deadline, Sealed: record.sealed, Bids: record.bidCount}
+
+		if !record.sealed {
+			transfer.Auction.HighestBid = copyInt(record.highestBid)
+		}
+	} else if !record.deadline.IsZero() {
+		expiresAt := record.deadline
+		transfer.ExpiresAt = &expiresAt
+	}
+
+	return transfer, true
+}
+
+func matchesTransferFilter(transfer domain.Transfer, filter domain.TransferFilter) bool {
+	player := transfer.Player
+
+	if filter.Country != "" && !strings.EqualFold(player.Country, filter.Country) {
+		return false
+	}
+
+	if filter.TeamName != "" && !containsFold(transfer.TeamName, filter.TeamName) {
+		return false
+	}
+
+	for _, name := range strings.Fields(filter.PlayerName) {
+		if !containsFold(player.FirstName, name) && !containsFold(player.LastName, name) {
+			return false
+		}
+	}
+
+	if filter.Position != "" && player.Position != filter.Position {
+		return false
+	}
+
+	if filter.Mode != "" && transfer.Mode != filter.Mode {
+		return false
+	}
+
+	return inRange(int(player.Age), filter.MinAge, filter.MaxAge) &&
+		inRange(transfer.AskedPrice, filter.MinAskedPrice, filter.MaxAskedPrice) &&
+		inRange(transfer.MarketValue, filter.MinMarketValue, filter.MaxMarketValue)
+}
+
+func transferAt(sortBy string, value, id int) domain.Transfer {
+	transfer := domain.Transfer{Id: id}
+
+	switch sortBy {
+	case "askedPrice":
+		transfer.AskedPrice = value
+	case "marketValue":
+		transfer.MarketValue = value
+	case "age":
+		transfer.Player.Age = uint8(value)
+	}
+
+	return transfer
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func inRange(value, min, max int) bool {
+	return (min <= 0 || value >= min) && (max <= 0 || value <= max)
+}
+
+func (tr *TransferRepository) ExpireListings(now time.Time) (int, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	return tr.store.expireListings(now, func(record transferRecord) bool { return true }), nil
+}
+
+func (tr *TransferRepository) ExpireDueListings(now time.Time) (int, error) {
+	tr.store.mu.Lock()
+	defer tr.store.mu.Unlock()
+
+	return tr.store.expireListings(now, func(record transferRecord) bool {
+		return record.mode == domain.TransferFixedPrice && !record.deadline.IsZero() && !record.deadline.After(now)
+	}), nil
+}
+
+func (s *Store) expireListings(now time.Time, due func(record transferRecord) bool) int {
+	expired := 0
+
+	for transferId, record := range s.transfers {
+		if record.status != domain.TransferListed || !due(record) {
+			continue
+		}
+
+		record.status = domain.TransferExpired
+		record.transferredFrom = copyInt(s.players[record.playerId].TeamId)
+
+		if record.deadline.IsZero() || record.deadline.After(now) {
+			record.deadline = now
+		}
+
+		s.transfers[transferId] = record
+		s.refundBids(transferId, now)
+		expired++
+	}
+
+	return expired
+}
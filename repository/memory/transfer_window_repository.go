@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"sort"
+	"time"
+)
+
+type TransferWindowRepository struct {
+	store *Store
+}
+
+func NewTransferWindowRepository(store *Store) *TransferWindowRepository {
+	return &TransferWindowRepository{
+		store: store,
+	}
+}
+
+func (wr *TransferWindowRepository) CreateWindow(window *domain.TransferWindow) error {
+	wr.store.mu.Lock()
+	defer wr.store.mu.Unlock()
+
+	if window.LeagueId != nil {
+		if _, ok := wr.store.leagues[*window.LeagueId]; !ok {
+			return errors.New("league not found")
+		}
+	}
+
+	window.Id = wr.store.nextId("transfer_window")
+	window.ManualState = nil
+	window.ClosedAt = nil
+	wr.store.windows[window.Id] = domain.TransferWindow{
+		Id:               window.Id,
+		Name:             window.Name,
+		OpensAt:          copyTime(window.OpensAt),
+		ClosesAt:         copyTime(window.ClosesAt),
+		LeagueId:         copyInt(window.LeagueId),
+		OpensAfterRound:  copyInt(window.OpensAfterRound),
+		ClosesAfterRound: copyInt(window.ClosesAfterRound),
+		ListingPolicy:    window.ListingPolicy,
+		CreatedAt:        window.CreatedAt,
+	}
+
+	return nil
+}
+
+func (wr *TransferWindowRepository) GetWindow(id int) (domain.TransferWindow, error) {
+	wr.store.mu.Lock()
+	defer wr.store.mu.Unlock()
+
+	window, ok := wr.store.windows[id]
+
+	if !ok {
+		return domain.TransferWindow{}, sql.ErrNoRows
+	}
+
+	return wr.store.describeWindow(window), nil
+}
+
+func (wr *TransferWindowRepository) FindWindows() (windows []domain.TransferWindow, err error) {
+	wr.store.mu.Lock()
+	defer wr.store.mu.Unlock()
+
+	for _, window := range wr.store.windows {
+		windows = append(windows, wr.store.describeWindow(window))
+	}
+
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Id < windows[j].Id })
+	return windows, nil
+}
+
+func (wr *TransferWindowRepository) UpdateManualState(windowId int, state *string) error {
+	wr.store.mu.Lock()
+	defer wr.store.mu.Unlock()
+
+	window, ok := wr.store.windows[windowId]
+
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	window.ManualState = nil
+
+	if state != nil {
+		manualState := *state
+		window.ManualState = &manualState
+
+		if manualState == domain.WindowOpen {
+			window.ClosedAt = nil
+		}
+	}
+
+	wr.store.windows[windowId] = window
+	return nil
+}
+
+func (wr *TransferWindowRepository) MarkWindowClosed(windowId int, now time.Time) error {
+	wr.store.mu.Lock()
+	defer wr.store.mu.Unlock()
+
+	window, ok := wr.store.windows[windowId]
+
+	if !ok || window.ClosedAt != nil {
+		return errors.New("transfer window is already closed")
+	}
+
+	window.ClosedAt = &now
+	wr.store.windows[windowId] = window
+	return nil
+}
+
+func (wr *TransferWindowRepository) DeleteWindow(windowId int) error {
+	wr.store.mu.Lock()
+	defer wr.store.mu.Unlock()
+
+	if _, ok := wr.store.windows[windowId]; !ok {
+		return sql.ErrNoRows
+	}
+
+	delete(wr.store.windows, windowId)
+	return nil
+}
+
+func (s *Store) describeWindow(window domain.TransferWindow) domain.TransferWindow {
+	window.OpensAt = copyTime(window.OpensAt)
+	window.ClosesAt = copyTime(window.ClosesAt)
+	window.LeagueId = copyInt(window.LeagueId)
+	window.OpensAfterRound = copyInt(window.OpensAfterRound)
+	window.ClosesAfterRound = copyInt(window.ClosesAfterRound)
+	window.ClosedAt = copyTime(window.ClosedAt)
+
+	if window.ManualState != nil {
+		manualState := *window.ManualState
+		window.ManualState = &manualState
+	}
+
+	if window.LeagueId != nil {
+		l := s.leagues[*window.LeagueId]
+		window.LeagueRound = l.CurrentRound
+		window.LeagueStatus = l.Status
+	}
+
+	return window
+}
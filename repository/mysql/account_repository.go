@@ -0,0 +1,404 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+type AccountRepository struct {
+	db               *sql.DB
+	teamRepository   *TeamRepository
+	playerRepository *PlayerRepository
+}
+
+func NewAccountRepository(
+	db *sql.DB,
+	tr *TeamRepository,
+	pr *PlayerRepository) *AccountRepository {
+	return &AccountRepository{
+		db:               db,
+		teamRepository:   tr,
+		playerRepository: pr,
+	}
+}
+
+const lockedByFailedLogins = "locked = 1 AND (SELECT e.source FROM account_lock_event e " +
+	"WHERE e.account_id = account.id AND e.action = ? ORDER BY e.id DESC LIMIT 1) = ?"
+
+func (ar *AccountRepository) CreateAccount(account *domain.Account, now time.Time) error {
+	tx, err := ar.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(
+		"INSERT INTO account(username, password, first_name, last_name, confirmed, locked, login_attempts, verification_token, profile) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		account.Username, account.Password, account.FirstName, account.LastName, account.Confirmed, account.Locked, account.LoginAttempts, account.VerificationToken, account.Profile)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	id, err := res.LastInsertId()
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	account.Id = int(id)
+	account.Team.AccountId = account.Id
+
+	err = ar.teamRepository.CreateTeam(account.Team, now, tx)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for i := 0; i < len(account.Team.Players); i++ {
+		account.Team.Players[i].TeamId = &account.Team.Id
+		err = ar.playerRepository.CreatePlayer(&account.Team.Players[i], tx)
+
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (ar *AccountRepository) UpdateAccount(account *domain.Account) error {
+	_, err :=
+		ar.db.Exec("UPDATE account SET username = ?, first_name = ?, last_name = ?, profile = ?, confirmed = ? WHERE id = ?",
+			account.Username, account.FirstName, account.LastName, account.Profile, account.Confirmed, account.Id)
+
+	return err
+}
+
+func (ar *AccountRepository) DeleteAccount(id int, now time.Time) error {
+	tx, err := ar.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	team, err := ar.teamRepository.getTeamByAccountId(id, tx)
+
+	if err != nil && err != sql.ErrNoRows {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err == nil {
+		if err = ar.teamRepository.deleteTeam(team.Id, now, tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	res, err := tx.Exec("DELETE FROM account WHERE id = ?", id)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func (ar *AccountRepository) GetAccountById(id int) (account domain.Account, err error) {
+	return ar.getAccount(
+		"SELECT id, username, password, first_name, last_name, confirmed, locked, login_attempts, verification_token, COALESCE(profile, 'USER'), token_version FROM account WHERE id = ?", id)
+}
+
+func (ar *AccountRepository) GetAccountByUsername(username string) (account domain.Account, err error) {
+	return ar.getAccount(
+		"SELECT id, username, password, first_name, last_name, confirmed, locked, login_attempts, verification_token, COALESCE(profile, 'USER'), token_version FROM account WHERE username = ?", username)
+}
+
+func (ar *AccountRepository) getAccount(query string, args ...interface{}) (account domain.Account, err error) {
+	return account, ar.db.QueryRow(query, args...).Scan(
+		&account.Id,
+		&account.Username,
+		&account.Password,
+		&account.FirstName,
+		&account.LastName,
+		&account.Confirmed,
+		&account.Locked,
+		&account.LoginAttempts,
+		&account.VerificationToken,
+		&account.Profile,
+		&account.TokenVersion)
+}
+
+func (ar *AccountRepository) FindAccounts() (accounts []domain.Account, err error) {
+	rows, err := ar.db.Query(
+		"SELECT a.id, a.username, a.first_name, a.last_name, a.confirmed, a.locked, a.login_attempts, COALESCE(a.profile, 'USER'), t.id " +
+			"FROM account a " +
+			"LEFT JOIN team t ON t.account_id = a.id " +
+			"ORDER BY a.id")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var account domain.Account
+		var teamId sql.NullInt64
+
+		if err := rows.Scan(
+			&account.Id,
+			&account.Username,
+			&account.FirstName,
+			&account.LastName,
+			&account.Confirmed,
+			&account.Locked,
+			&account.LoginAttempts,
+			&account.Profile,
+			&teamId); err != nil {
+			return nil, err
+		}
+
+		if teamId.Valid {
+			account.Team = &domain.Team{Id: int(teamId.Int64), AccountId: account.Id}
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+func (ar *AccountRepository) VerifyAccount(token string) (bool, error) {
+	res, err := ar.db.Exec("UPDATE account SET confirmed = ? WHERE verification_token = ?", true, token)
+
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+
+	if rowsAffected != 1 {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (ar *AccountRepository) RegisterFailedLoginAttempt(username string, event *domain.AccountLockEvent) error {
+	tx, err := ar.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("UPDATE account SET login_attempts = login_attempts + 1 WHERE username = ? AND locked = 0", username)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return nil
+	}
+
+	res, err = tx.Exec("UPDATE account SET locked = 1 WHERE username = ? AND locked = 0 AND login_attempts > 2", username)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return tx.Commit()
+	}
+
+	if err = tx.QueryRow("SELECT id FROM account WHERE username = ?", username).Scan(&event.AccountId); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = ar.createLockEvent(event, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (ar *AccountRepository) ResetLoginAttempts(username string) error {
+	_, err := ar.db.Exec("UPDATE account SET login_attempts = 0 WHERE username = ?", username)
+	return err
+}
+
+func (ar *AccountRepository) ResetPassword(token, password string, event *domain.AccountLockEvent, now time.Time) (int, error) {
+	tx, err := ar.db.Begin()
+
+	if err != nil {
+		return 0, err
+	}
+
+	accountId, err := useToken(domain.PasswordResetPurpose, token, now, tx)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	res, err := tx.Exec("UPDATE account SET password = ?, token_version = token_version + 1 WHERE id = ?", password, accountId)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return 0, sql.ErrNoRows
+	}
+
+	if _, err = tx.Exec("UPDATE account SET login_attempts = 0 WHERE id = ? AND locked = 0", accountId); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	res, err = tx.Exec("UPDATE account SET locked = 0, login_attempts = 0 WHERE id = ? AND "+lockedByFailedLogins,
+		accountId, domain.LockAction, domain.FailedLoginsLockSource)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected == 1 {
+		event.AccountId, event.ActorAccountId = accountId, &accountId
+
+		if err = ar.createLockEvent(event, tx); err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err = revokeTokens(accountId, domain.PasswordResetPurpose, now, tx); err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	return accountId, tx.Commit()
+}
+
+func (ar *AccountRepository) SetLocked(accountId int, locked bool, event *domain.AccountLockEvent) error {
+	tx, err := ar.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("UPDATE account SET locked = ?, login_attempts = 0 WHERE id = ?", locked, accountId)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	if err = ar.createLockEvent(event, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (ar *AccountRepository) UnlockFailedLogins(accountId int, event *domain.AccountLockEvent) error {
+	tx, err := ar.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("UPDATE account SET locked = 0, login_attempts = 0 WHERE id = ? AND "+lockedByFailedLogins,
+		accountId, domain.LockAction, domain.FailedLoginsLockSource)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	if err = ar.createLockEvent(event, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (ar *AccountRepository) createLockEvent(event *domain.AccountLockEvent, tx *sql.Tx) error {
+	res, err := tx.Exec(
+		"INSERT INTO account_lock_event(account_id, actor_account_id, action, reason, source, created_at) VALUES(?, ?, ?, ?, ?, ?)",
+		event.AccountId, event.ActorAccountId, event.Action, event.Reason, event.Source, event.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	event.Id = int(id)
+	return nil
+}
+
+func (ar *AccountRepository) FindLockEvents(accountId int) (events []domain.AccountLockEvent, err error) {
+	rows, err := ar.db.Query(
+		"SELECT id, account_id, actor_account_id, action, reason, source, created_at FROM account_lock_event WHERE account_id = ? ORDER BY id", accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.AccountLockEvent
+		var reason sql.NullString
+
+		if err := rows.Scan(
+			&event.Id,
+			&event.AccountId,
+			&event.ActorAccountId,
+			&event.Action,
+			&reason,
+			&event.Source,
+			&event.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		event.Reason = reason.String
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
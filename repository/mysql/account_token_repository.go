@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+type AccountTokenRepository struct {
+	db *sql.DB
+}
+
+func NewAccountTokenRepository(db *sql.DB) *AccountTokenRepository {
+	return &AccountTokenRepository{
+		db: db,
+	}
+}
+
+func (atr *AccountTokenRepository) CreateToken(token *domain.AccountToken) error {
+	res, err := atr.db.Exec(
+		"INSERT INTO account_token(account_id, purpose, token, expires_at) VALUES(?, ?, ?, ?)",
+		token.AccountId, token.Purpose, token.Token, token.ExpiresAt)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	token.Id = int(id)
+	return nil
+}
+
+func (atr *AccountTokenRepository) UseToken(purpose, token string, now time.Time) (int, error) {
+	tx, err := atr.db.Begin()
+
+	if err != nil {
+		return 0, err
+	}
+
+	accountId, err := useToken(purpose, token, now, tx)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	return accountId, tx.Commit()
+}
+
+func (atr *AccountTokenRepository) RevokeTokens(accountId int, purpose string, now time.Time) error {
+	tx, err := atr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = revokeTokens(accountId, purpose, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func useToken(purpose, token string, now time.Time, tx *sql.Tx) (accountId int, err error) {
+	res, err := tx.Exec(
+		"UPDATE account_token SET used_at = ? WHERE token = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?",
+		now, token, purpose, now)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return 0, sql.ErrNoRows
+	}
+
+	err = tx.QueryRow("SELECT account_id FROM account_token WHERE token = ? AND purpose = ?", token, purpose).Scan(&accountId)
+	return accountId, err
+}
+
+func revokeTokens(accountId int, purpose string, now time.Time, tx *sql.Tx) error {
+	_, err := tx.Exec(
+		"UPDATE account_token SET used_at = ? WHERE account_id = ? AND purpose = ? AND used_at IS NULL",
+		now, accountId, purpose)
+
+	return err
+}
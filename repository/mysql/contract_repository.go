@@ -0,0 +1,379 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+type ContractRepository struct {
+	db *sql.DB
+}
+
+func NewContractRepository(db *sql.DB) *ContractRepository {
+	return &ContractRepository{
+		db: db,
+	}
+}
+
+const contractQuery = "SELECT c.id, c.player_id, c.wage, c.release_clause, c.starts_at, c.ends_at, c.next_wage_at, c.status, c.ended_at " +
+	"FROM contract c "
+
+func (cr *ContractRepository) GetContract(playerId int) (domain.Contract, error) {
+	contracts, err := cr.getContracts(contractQuery+"WHERE c.player_id = ? AND c.status = ?", playerId, domain.ContractActive)
+
+	if err != nil {
+		return domain.Contract{}, err
+	}
+
+	if len(contracts) == 0 {
+		return domain.Contract{}, sql.ErrNoRows
+	}
+
+	return contracts[0], nil
+}
+
+func (cr *ContractRepository) FindTeamContracts(teamId int) ([]domain.Contract, error) {
+	return cr.getContracts(contractQuery+"JOIN player p ON p.id = c.player_id WHERE p.team_id = ? AND c.status = ? ORDER BY c.player_id",
+		teamId, domain.ContractActive)
+}
+
+func (cr *ContractRepository) FindDueWages(now time.Time) ([]domain.Contract, error) {
+	return cr.getContracts(contractQuery+"WHERE c.status = ? AND c.next_wage_at <= ? AND c.next_wage_at < c.ends_at ORDER BY c.next_wage_at, c.id",
+		domain.ContractActive, now)
+}
+
+func (cr *ContractRepository) getContracts(query string, args ...interface{}) (contracts []domain.Contract, err error) {
+	rows, err := cr.db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.Contract
+
+		if err = rows.Scan(&c.Id, &c.PlayerId, &c.Wage, &c.ReleaseClause, &c.StartsAt, &c.EndsAt, &c.NextWageAt, &c.Status, &c.EndedAt); err != nil {
+			return nil, err
+		}
+
+		contracts = append(contracts, c)
+	}
+
+	return contracts, rows.Err()
+}
+
+func (cr *ContractRepository) RenewContract(teamId int, contract *domain.Contract, now time.Time) error {
+	tx, err := cr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	err = execAll([]statement{{"UPDATE contract SET status = ?, ended_at = ? WHERE player_id = ? AND status = ? " +
+		"AND player_id IN (SELECT id FROM player WHERE team_id = ?)",
+		[]interface{}{domain.ContractRenewed, now, contract.PlayerId, domain.ContractActive, teamId}}}, errors.New("contract not renewed"), tx)
+
+	if err == nil {
+		err = signContract(contract, tx)
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (cr *ContractRepository) PayWage(contract domain.Contract, nextWageAt, now time.Time) error {
+	tx, err := cr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = payWage(contract, nextWageAt, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func payWage(contract domain.Contract, nextWageAt, now time.Time, tx *sql.Tx) error {
+	err := execAll([]statement{{"UPDATE contract SET next_wage_at = ? WHERE id = ? AND status = ? AND next_wage_at < ?",
+		[]interface{}{nextWageAt, contract.Id, domain.ContractActive, nextWageAt}}}, errors.New("wage already paid"), tx)
+
+	if err != nil {
+		return err
+	}
+
+	var teamId sql.NullInt64
+
+	if err = tx.QueryRow("SELECT team_id FROM player WHERE id = ?", contract.PlayerId).Scan(&teamId); err != nil {
+		return err
+	}
+
+	if !teamId.Valid || contract.Wage <= 0 {
+		return nil
+	}
+
+	return execAll([]statement{
+		{"UPDATE team SET available_cash = available_cash - ? WHERE id = ?", []interface{}{contract.Wage, teamId.Int64}},
+		ledgerStatement(domain.LedgerWages, domain.ReferenceContract, contract.Id, teamAccount(int(teamId.Int64)), externalAccount, contract.Wage, now),
+	}, errors.New("wage not paid"), tx)
+}
+
+func (cr *ContractRepository) FindExpiredContractIds(now time.Time) (ids []int, err error) {
+	rows, err := cr.db.Query("SELECT c.id FROM contract c WHERE c.status = ? AND c.ends_at <= ? "+
+		"AND NOT EXISTS (SELECT 1 FROM loan l WHERE l.player_id = c.player_id AND l.status = ?) "+
+		"ORDER BY c.ends_at, c.id", domain.ContractActive, now, domain.LoanActive)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (cr *ContractRepository) ExpireContract(contractId int, now time.Time) error {
+	tx, err := cr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = expireContract(contractId, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func expireContract(contractId int, now time.Time, tx *sql.Tx) error {
+	var playerId int
+	err := tx.QueryRow("SELECT player_id FROM contract WHERE id = ? AND status = ? AND ends_at <= ?",
+		contractId, domain.ContractActive, now).Scan(&playerId)
+
+	if err != nil {
+		return errors.New("contract has not expired")
+	}
+
+	_, err = expireListings(now, tx, "SELECT id FROM transfer_list WHERE player_id = ? AND status = ? ORDER BY id", playerId, domain.TransferListed)
+
+	if err != nil {
+		return err
+	}
+
+	if err = cancelOffers(playerId, now, tx); err != nil {
+		return err
+	}
+
+	err = execAll([]statement{{"UPDATE contract SET status = ?, ended_at = ? WHERE id = ? AND status = ?",
+		[]interface{}{domain.ContractExpired, now, contractId, domain.ContractActive}}}, errors.New("contract not expired"), tx)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE player SET team_id = NULL WHERE id = ?", playerId)
+	return err
+}
+
+func (cr *ContractRepository) SignFreeAgent(teamId int, contract *domain.Contract, fee int, now time.Time) error {
+	tx, err := cr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = signFreeAgent(teamId, contract, fee, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func signFreeAgent(teamId int, contract *domain.Contract, fee int, now time.Time, tx *sql.Tx) error {
+	statements := []statement{{"UPDATE player SET team_id = ? WHERE id = ? AND team_id IS NULL", []interface{}{teamId, contract.PlayerId}}}
+
+	if fee > 0 {
+		statements = append(statements, statement{"UPDATE team SET available_cash = available_cash - ? WHERE id = ? AND available_cash >= ?",
+			[]interface{}{fee, teamId, fee}})
+	}
+
+	if err := execAll(statements, errors.New("free agent not signed"), tx); err != nil {
+		return err
+	}
+
+	if err := replaceContract(contract, now, tx); err != nil {
+		return err
+	}
+
+	if fee == 0 {
+		return nil
+	}
+
+	return execAll([]statement{ledgerStatement(domain.LedgerSigningFee, domain.ReferenceContract, contract.Id, teamAccount(teamId), externalAccount, fee, now)},
+		errors.New("free agent not signed"), tx)
+}
+
+func (cr *ContractRepository) PayReleaseClause(buyerId, releaseClause, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	tx, err := cr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = payReleaseClause(buyerId, releaseClause, newMarketValue, contract, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func payReleaseClause(buyerId, releaseClause, newMarketValue int, contract *domain.Contract, now time.Time, tx *sql.Tx) error {
+	c := completion{playerId: contract.PlayerId, buyerId: buyerId, price: releaseClause, newMarketValue: newMarketValue, chargeBuyer: true, now: now}
+	var marketValue int
+	err := tx.QueryRow("SELECT p.team_id, p.market_value "+
+		"FROM player p "+
+		"JOIN contract c ON c.player_id = p.id AND c.status = ? "+
+		"WHERE p.id = ? AND p.team_id != ? AND c.release_clause = ? "+
+		"AND NOT EXISTS (SELECT 1 FROM loan l WHERE l.player_id = p.id AND l.status = ?)",
+		domain.ContractActive, c.playerId, buyerId, releaseClause, domain.LoanActive).Scan(&c.sellerId, &marketValue)
+
+	if err != nil {
+		return errors.New("release clause not paid")
+	}
+
+	if err = withdrawListings(c.playerId, now, tx); err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("INSERT INTO transfer_list(player_id, asked_price, market_value, transferred, status, mode) VALUES(?, ?, ?, ?, ?, ?)",
+		c.playerId, c.price, marketValue, false, domain.TransferListed, domain.TransferReleaseClause)
+
+	if err != nil {
+		return err
+	}
+
+	transferId, _ := res.LastInsertId()
+	c.transferId = int(transferId)
+
+	if err = completeTransfer(c, tx); err != nil {
+		return err
+	}
+
+	if err = replaceContract(contract, now, tx); err != nil {
+		return err
+	}
+
+	return cancelOffers(c.playerId, now, tx)
+}
+
+func (cr *ContractRepository) ReleasePlayer(teamId, playerId int, now time.Time) error {
+	tx, err := cr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = releasePlayer(teamId, playerId, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func releasePlayer(teamId, playerId int, now time.Time, tx *sql.Tx) error {
+	var onLoan bool
+	err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM loan l WHERE l.player_id = p.id AND l.status = ?) FROM player p WHERE p.id = ? AND p.team_id = ?",
+		domain.LoanActive, playerId, teamId).Scan(&onLoan)
+
+	if err != nil {
+		return errors.New("player not in your team")
+	}
+
+	if onLoan {
+		return errors.New("loaned players cannot be released")
+	}
+
+	if err = withdrawListings(playerId, now, tx); err != nil {
+		return err
+	}
+
+	if err = cancelOffers(playerId, now, tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE swap_deal SET status = ?, responded_at = ? "+
+		"WHERE status = ? AND id IN (SELECT swap_id FROM swap_player WHERE player_id = ?)",
+		domain.SwapCancelled, now, domain.SwapProposed, playerId)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE contract SET status = ?, ended_at = ? WHERE player_id = ? AND status = ?",
+		domain.ContractTerminated, now, playerId, domain.ContractActive)
+
+	if err != nil {
+		return err
+	}
+
+	return execAll([]statement{{"UPDATE player SET team_id = NULL WHERE id = ? AND team_id = ?", []interface{}{playerId, teamId}}},
+		errors.New("player not released"), tx)
+}
+
+func signContract(contract *domain.Contract, tx *sql.Tx) error {
+	res, err := tx.Exec("INSERT INTO contract(player_id, wage, release_clause, starts_at, ends_at, next_wage_at, status) VALUES(?, ?, ?, ?, ?, ?, ?)",
+		contract.PlayerId, contract.Wage, contract.ReleaseClause, contract.StartsAt, contract.EndsAt, contract.NextWageAt, domain.ContractActive)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	contract.Id = int(id)
+	contract.Status = domain.ContractActive
+	return nil
+}
+
+func replaceContract(contract *domain.Contract, now time.Time, tx *sql.Tx) error {
+	_, err := tx.Exec("UPDATE contract SET status = ?, ended_at = ? WHERE player_id = ? AND status = ?",
+		domain.ContractReplaced, now, contract.PlayerId, domain.ContractActive)
+
+	if err != nil {
+		return err
+	}
+
+	return signContract(contract, tx)
+}
+
+func contractTerms(wage, years sql.NullInt64, releaseClause *int) *domain.ContractTerms {
+	if !wage.Valid || !years.Valid {
+		return nil
+	}
+
+	return &domain.ContractTerms{Wage: int(wage.Int64), Years: int(years.Int64), ReleaseClause: releaseClause}
+}
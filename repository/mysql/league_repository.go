@@ -0,0 +1,308 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/league"
+	"time"
+)
+
+type LeagueRepository struct {
+	db              *sql.DB
+	matchRepository *MatchRepository
+}
+
+func NewLeagueRepository(db *sql.DB, mr *MatchRepository) *LeagueRepository {
+	return &LeagueRepository{
+		db:              db,
+		matchRepository: mr,
+	}
+}
+
+func (lr *LeagueRepository) CreateLeague(l *domain.League) error {
+	res, err := lr.db.Exec(
+		"INSERT INTO league(name, country, status, current_round, rounds, seed, created_at) VALUES(?, ?, ?, ?, ?, ?, ?)",
+		l.Name, l.Country, l.Status, l.CurrentRound, l.Rounds, l.Seed, l.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	l.Id = int(id)
+	return nil
+}
+
+func (lr *LeagueRepository) GetLeague(id int) (domain.League, error) {
+	leagues, err := lr.getLeagues("WHERE id = ?", id)
+
+	if err != nil {
+		return domain.League{}, err
+	}
+
+	if len(leagues) == 0 {
+		return domain.League{}, sql.ErrNoRows
+	}
+
+	return leagues[0], nil
+}
+
+func (lr *LeagueRepository) FindLeagues() ([]domain.League, error) {
+	return lr.getLeagues("")
+}
+
+func (lr *LeagueRepository) getLeagues(where string, args ...interface{}) (leagues []domain.League, err error) {
+	rows, err := lr.db.Query("SELECT id, name, COALESCE(country, ''), status, current_round, rounds, seed, created_at FROM league "+where+" ORDER BY id", args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var l domain.League
+
+		if err = rows.Scan(&l.Id, &l.Name, &l.Country, &l.Status, &l.CurrentRound, &l.Rounds, &l.Seed, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		leagues = append(leagues, l)
+	}
+
+	return leagues, rows.Err()
+}
+
+func (lr *LeagueRepository) EnrolTeam(leagueId, teamId int, now time.Time) error {
+	res, err := lr.db.Exec(
+		"INSERT INTO league_team(league_id, team_id, enrolled_at) SELECT id, ?, ? FROM league WHERE id = ? AND status = ?",
+		teamId, now, leagueId, domain.LeagueOpen)
+
+	if err != nil {
+		return errors.New("team already enrolled")
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return errors.New("league is not open for enrolment")
+	}
+
+	return nil
+}
+
+func (lr *LeagueRepository) FindLeagueTeamIds(leagueId int) (teamIds []int, err error) {
+	rows, err := lr.db.Query("SELECT team_id FROM league_team WHERE league_id = ? ORDER BY enrolled_at, team_id", leagueId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var teamId int
+
+		if err = rows.Scan(&teamId); err != nil {
+			return nil, err
+		}
+
+		teamIds = append(teamIds, teamId)
+	}
+
+	return teamIds, rows.Err()
+}
+
+func (lr *LeagueRepository) StartLeague(leagueId, rounds int, fixtures []domain.Fixture) error {
+	tx, err := lr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("UPDATE league SET status = ?, rounds = ? WHERE id = ? AND status = ?",
+		domain.LeagueRunning, rounds, leagueId, domain.LeagueOpen)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return errors.New("league is not open")
+	}
+
+	for _, fixture := range fixtures {
+		_, err = tx.Exec("INSERT INTO fixture(league_id, round, home_team_id, away_team_id) VALUES(?, ?, ?, ?)",
+			leagueId, fixture.Round, fixture.HomeTeamId, fixture.AwayTeamId)
+
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (lr *LeagueRepository) FindFixtures(leagueId, round int) (fixtures []domain.Fixture, err error) {
+	query := "SELECT f.id, f.league_id, f.round, f.home_team_id, th.name, f.away_team_id, ta.name, m.id, m.home_goals, m.away_goals, m.played_at " +
+		"FROM fixture f " +
+		"JOIN team th ON th.id = f.home_team_id " +
+		"JOIN team ta ON ta.id = f.away_team_id " +
+		"LEFT JOIN match_result m ON m.id = f.match_id " +
+		"WHERE f.league_id = ?"
+	args := []interface{}{leagueId}
+
+	if round > 0 {
+		query += " AND f.round = ?"
+		args = append(args, round)
+	}
+
+	rows, err := lr.db.Query(query+" ORDER BY f.round, f.id", args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var f domain.Fixture
+		var matchId, homeGoals, awayGoals sql.NullInt64
+		var playedAt sql.NullTime
+
+		err = rows.Scan(&f.Id, &f.LeagueId, &f.Round, &f.HomeTeamId, &f.HomeTeamName, &f.AwayTeamId, &f.AwayTeamName,
+			&matchId, &homeGoals, &awayGoals, &playedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if matchId.Valid {
+			id, home, away, at := int(matchId.Int64), int(homeGoals.Int64), int(awayGoals.Int64), playedAt.Time
+			f.MatchId, f.HomeGoals, f.AwayGoals, f.PlayedAt = &id, &home, &away, &at
+		}
+
+		fixtures = append(fixtures, f)
+	}
+
+	return fixtures, rows.Err()
+}
+
+func (lr *LeagueRepository) RecordRound(leagueId, round int, fixtures []domain.Fixture, finished bool) error {
+	tx, err := lr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	status := domain.LeagueRunning
+
+	if finished {
+		status = domain.LeagueFinished
+	}
+
+	res, err := tx.Exec("UPDATE league SET current_round = ?, status = ? WHERE id = ? AND current_round = ? AND status = ?",
+		round, status, leagueId, round-1, domain.LeagueRunning)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return errors.New("round already played")
+	}
+
+	for _, fixture := range fixtures {
+		if err = lr.recordFixture(leagueId, fixture, tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (lr *LeagueRepository) recordFixture(leagueId int, fixture domain.Fixture, tx *sql.Tx) error {
+	if err := lr.matchRepository.createMatch(fixture.Match, tx); err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("UPDATE fixture SET match_id = ? WHERE id = ? AND match_id IS NULL", fixture.Match.Id, fixture.Id)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return errors.New("fixture already played")
+	}
+
+	home, away := fixture.Match.HomeGoals, fixture.Match.AwayGoals
+
+	if err = lr.updateStanding(leagueId, fixture.HomeTeamId, home, away, tx); err != nil {
+		return err
+	}
+
+	return lr.updateStanding(leagueId, fixture.AwayTeamId, away, home, tx)
+}
+
+func (lr *LeagueRepository) updateStanding(leagueId, teamId, goalsFor, goalsAgainst int, tx *sql.Tx) error {
+	var form string
+	err := tx.QueryRow("SELECT form FROM league_team WHERE league_id = ? AND team_id = ?", leagueId, teamId).Scan(&form)
+
+	if err != nil {
+		return err
+	}
+
+	result, points := league.Result(goalsFor, goalsAgainst)
+	won, drawn, lost := 0, 0, 0
+
+	switch result {
+	case "W":
+		won = 1
+	case "D":
+		drawn = 1
+	default:
+		lost = 1
+	}
+
+	_, err = tx.Exec("UPDATE league_team SET played = played + 1, won = won + ?, drawn = drawn + ?, lost = lost + ?, "+
+		"goals_for = goals_for + ?, goals_against = goals_against + ?, points = points + ?, form = ? "+
+		"WHERE league_id = ? AND team_id = ?",
+		won, drawn, lost, goalsFor, goalsAgainst, points, league.AppendForm(form, result), leagueId, teamId)
+
+	return err
+}
+
+func (lr *LeagueRepository) FindStandings(leagueId int) (standings []domain.Standing, err error) {
+	rows, err := lr.db.Query("SELECT lt.team_id, t.name, lt.played, lt.won, lt.drawn, lt.lost, lt.goals_for, lt.goals_against, lt.points, lt.form "+
+		"FROM league_team lt "+
+		"JOIN team t ON t.id = lt.team_id "+
+		"WHERE lt.league_id = ? "+
+		"ORDER BY lt.points DESC, lt.goals_for - lt.goals_against DESC, lt.goals_for DESC, t.name, t.id", leagueId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var s domain.Standing
+		err = rows.Scan(&s.TeamId, &s.TeamName, &s.Played, &s.Won, &s.Drawn, &s.Lost, &s.GoalsFor, &s.GoalsAgainst, &s.Points, &s.Form)
+
+		if err != nil {
+			return nil, err
+		}
+
+		s.Position = len(standings) + 1
+		s.GoalDifference = s.GoalsFor - s.GoalsAgainst
+		standings = append(standings, s)
+	}
+
+	return standings, rows.Err()
+}
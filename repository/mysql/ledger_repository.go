@@ -0,0 +1,94 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+type LedgerRepository struct {
+	db *sql.DB
+}
+
+func NewLedgerRepository(db *sql.DB) *LedgerRepository {
+	return &LedgerRepository{
+		db: db,
+	}
+}
+
+func (lr *LedgerRepository) FindTeamTransactions(teamId int) (transactions []domain.LedgerTransaction, err error) {
+	rows, err := lr.db.Query("SELECT id, category, reference_type, reference_id, from_account, from_team_id, to_account, to_team_id, amount, created_at "+
+		"FROM ledger_transaction WHERE from_team_id = ? OR to_team_id = ? ORDER BY id", teamId, teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var t domain.LedgerTransaction
+
+		err = rows.Scan(&t.Id, &t.Category, &t.ReferenceType, &t.ReferenceId, &t.FromAccount, &t.FromTeamId, &t.ToAccount, &t.ToTeamId,
+			&t.Amount, &t.CreatedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, t)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (lr *LedgerRepository) FindBalances() (balances []domain.LedgerBalance, err error) {
+	rows, err := lr.db.Query("SELECT t.id, t.name, t.available_cash, "+
+		"(SELECT COALESCE(SUM(l.amount), 0) FROM ledger_transaction l WHERE l.to_account = ? AND l.to_team_id = t.id) - "+
+		"(SELECT COALESCE(SUM(l.amount), 0) FROM ledger_transaction l WHERE l.from_account = ? AND l.from_team_id = t.id), "+
+		"(SELECT COALESCE(SUM(b.amount), 0) FROM transfer_bid b WHERE b.team_id = t.id AND b.status = ?), "+
+		"(SELECT COALESCE(SUM(l.amount), 0) FROM ledger_transaction l WHERE l.to_account = ? AND l.to_team_id = t.id) - "+
+		"(SELECT COALESCE(SUM(l.amount), 0) FROM ledger_transaction l WHERE l.from_account = ? AND l.from_team_id = t.id) "+
+		"FROM team t ORDER BY t.id",
+		domain.LedgerTeam, domain.LedgerTeam, domain.BidActive, domain.LedgerEscrow, domain.LedgerEscrow)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var b domain.LedgerBalance
+
+		if err = rows.Scan(&b.TeamId, &b.TeamName, &b.AvailableCash, &b.LedgerCash, &b.ActiveBids, &b.LedgerEscrow); err != nil {
+			return nil, err
+		}
+
+		balances = append(balances, b)
+	}
+
+	return balances, rows.Err()
+}
+
+type ledgerAccount struct {
+	kind   string
+	teamId interface{}
+}
+
+func teamAccount(teamId int) ledgerAccount {
+	return ledgerAccount{kind: domain.LedgerTeam, teamId: teamId}
+}
+
+func escrowAccount(teamId int) ledgerAccount {
+	return ledgerAccount{kind: domain.LedgerEscrow, teamId: teamId}
+}
+
+var externalAccount = ledgerAccount{kind: domain.LedgerExternal}
+
+const ledgerInsert = "INSERT INTO ledger_transaction(category, reference_type, reference_id, from_account, from_team_id, to_account, to_team_id, amount, created_at) "
+
+func ledgerStatement(category, referenceType string, referenceId int, from, to ledgerAccount, amount int, now time.Time) statement {
+	return statement{ledgerInsert + "VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		[]interface{}{category, referenceType, referenceId, from.kind, from.teamId, to.kind, to.teamId, amount, now}}
+}
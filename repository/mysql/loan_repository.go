@@ -0,0 +1,307 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"strings"
+	"time"
+)
+
+type LoanRepository struct {
+	db *sql.DB
+}
+
+func NewLoanRepository(db *sql.DB) *LoanRepository {
+	return &LoanRepository{
+		db: db,
+	}
+}
+
+const roundsPlayed = "(SELECT COUNT(*) FROM match_result m " +
+	"WHERE l.started_at IS NOT NULL AND (m.home_team_id = l.borrower_id OR m.away_team_id = l.borrower_id) " +
+	"AND m.played_at >= l.started_at AND (l.ended_at IS NULL OR m.played_at < l.ended_at))"
+
+const loanQuery = "SELECT l.id, l.player_id, p.first_name, p.last_name, l.lender_id, tl.name, l.borrower_id, tb.name, l.fee, " +
+	"l.duration_days, l.duration_rounds, " + roundsPlayed + ", l.buy_option_price, l.status, " +
+	"l.proposed_at, l.started_at, l.ends_at, l.ended_at, l.transfer_id " +
+	"FROM loan l " +
+	"JOIN player p ON p.id = l.player_id " +
+	"JOIN team tl ON tl.id = l.lender_id " +
+	"JOIN team tb ON tb.id = l.borrower_id "
+
+func (lr *LoanRepository) CreateLoan(loan *domain.Loan) error {
+	res, err := lr.db.Exec("INSERT INTO loan(player_id, lender_id, borrower_id, fee, duration_days, duration_rounds, buy_option_price, status, proposed_at) "+
+		"SELECT id, team_id, ?, ?, ?, ?, ?, ?, ? FROM player WHERE id = ? AND team_id = ? AND team_id != ? "+
+		"AND NOT EXISTS (SELECT 1 FROM loan l WHERE l.player_id = player.id AND l.status = ?)",
+		loan.BorrowerId, loan.Fee, loan.DurationDays, loan.DurationRounds, loan.BuyOptionPrice, domain.LoanProposed, loan.ProposedAt,
+		loan.PlayerId, loan.LenderId, loan.BorrowerId, domain.LoanActive)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return errors.New("player cannot be loaned by the lending team")
+	}
+
+	id, _ := res.LastInsertId()
+	loan.Id = int(id)
+	return nil
+}
+
+func (lr *LoanRepository) GetLoan(id int) (domain.Loan, error) {
+	loans, err := lr.getLoans(loanQuery+"WHERE l.id = ?", id)
+
+	if err != nil {
+		return domain.Loan{}, err
+	}
+
+	if len(loans) == 0 {
+		return domain.Loan{}, sql.ErrNoRows
+	}
+
+	return loans[0], nil
+}
+
+func (lr *LoanRepository) FindLoans(teamId int, status string) ([]domain.Loan, error) {
+	where := []string{"(l.lender_id = ? OR l.borrower_id = ?)"}
+	args := []interface{}{teamId, teamId}
+
+	if status != "" {
+		where = append(where, "l.status = ?")
+		args = append(args, status)
+	}
+
+	return lr.getLoans(loanQuery+"WHERE "+strings.Join(where, " AND ")+" ORDER BY l.proposed_at DESC, l.id DESC", args...)
+}
+
+func (lr *LoanRepository) getLoans(query string, args ...interface{}) (loans []domain.Loan, err error) {
+	rows, err := lr.db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var loan domain.Loan
+		var firstName, lastName string
+
+		err = rows.Scan(&loan.Id, &loan.PlayerId, &firstName, &lastName, &loan.LenderId, &loan.LenderName, &loan.BorrowerId, &loan.BorrowerName,
+			&loan.Fee, &loan.DurationDays, &loan.DurationRounds, &loan.RoundsPlayed, &loan.BuyOptionPrice, &loan.Status,
+			&loan.ProposedAt, &loan.StartedAt, &loan.EndsAt, &loan.EndedAt, &loan.TransferId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		loan.PlayerName = strings.TrimSpace(firstName + " " + lastName)
+		loans = append(loans, loan)
+	}
+
+	return loans, rows.Err()
+}
+
+func (lr *LoanRepository) StartLoan(loanId, lenderId int, now time.Time) error {
+	tx, err := lr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = startLoan(loanId, lenderId, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func startLoan(loanId, lenderId int, now time.Time, tx *sql.Tx) error {
+	var playerId, borrowerId, fee int
+	var durationDays sql.NullInt64
+	err := tx.QueryRow("SELECT l.player_id, l.borrower_id, l.fee, l.duration_days "+
+		"FROM loan l "+
+		"JOIN player p ON p.id = l.player_id AND p.team_id = l.lender_id "+
+		"WHERE l.id = ? AND l.status = ? AND l.lender_id = ?",
+		loanId, domain.LoanProposed, lenderId).Scan(&playerId, &borrowerId, &fee, &durationDays)
+
+	if err != nil {
+		return errors.New("loan is no longer open")
+	}
+
+	if err = withdrawListings(playerId, now, tx); err != nil {
+		return err
+	}
+
+	if err = cancelOffers(playerId, now, tx); err != nil {
+		return err
+	}
+
+	var endsAt *time.Time
+
+	if durationDays.Valid {
+		end := now.AddDate(0, 0, int(durationDays.Int64))
+		endsAt = &end
+	}
+
+	statements := []statement{
+		{"UPDATE loan SET status = ?, started_at = ?, ends_at = ? WHERE id = ? AND status = ?",
+			[]interface{}{domain.LoanActive, now, endsAt, loanId, domain.LoanProposed}},
+		{"UPDATE player SET team_id = ? WHERE id = ? AND team_id = ?",
+			[]interface{}{borrowerId, playerId, lenderId}},
+	}
+
+	if fee > 0 {
+		statements = append(statements,
+			statement{"UPDATE team SET available_cash = available_cash - ? WHERE id = ? AND available_cash >= ?",
+				[]interface{}{fee, borrowerId, fee}},
+			statement{"UPDATE team SET available_cash = available_cash + ? WHERE id = ?",
+				[]interface{}{fee, lenderId}},
+			ledgerStatement(domain.LedgerLoanFee, domain.ReferenceLoan, loanId, teamAccount(borrowerId), teamAccount(lenderId), fee, now))
+	}
+
+	if err = execAll(statements, errors.New("loan not started"), tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE loan SET status = ?, ended_at = ? WHERE player_id = ? AND status = ?",
+		domain.LoanCancelled, now, playerId, domain.LoanProposed)
+
+	return err
+}
+
+var loanCloseStatuses = map[string]bool{
+	domain.LoanRejected:  true,
+	domain.LoanWithdrawn: true,
+}
+
+func (lr *LoanRepository) CloseLoan(loanId, teamId int, status string, now time.Time) error {
+	if !loanCloseStatuses[status] {
+		return errors.New("invalid loan status")
+	}
+
+	res, err := lr.db.Exec("UPDATE loan SET status = ?, ended_at = ? WHERE id = ? AND status = ? AND (lender_id = ? OR borrower_id = ?)",
+		status, now, loanId, domain.LoanProposed, teamId, teamId)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return errors.New("loan is no longer open")
+	}
+
+	return nil
+}
+
+func (lr *LoanRepository) ReturnLoan(loanId int, now time.Time) error {
+	tx, err := lr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	var playerId, lenderId, borrowerId int
+	err = tx.QueryRow("SELECT player_id, lender_id, borrower_id FROM loan WHERE id = ? AND status = ?", loanId, domain.LoanActive).
+		Scan(&playerId, &lenderId, &borrowerId)
+
+	if err == nil {
+		err = execAll([]statement{{"UPDATE loan SET status = ?, ended_at = ? WHERE id = ? AND status = ?",
+			[]interface{}{domain.LoanReturned, now, loanId, domain.LoanActive}}}, errors.New("loan is not active"), tx)
+	}
+
+	if err == nil {
+		_, err = tx.Exec("UPDATE player SET team_id = ? WHERE id = ? AND team_id = ?", lenderId, playerId, borrowerId)
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (lr *LoanRepository) BuyLoanedPlayer(loanId, borrowerId, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	tx, err := lr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = buyLoanedPlayer(loanId, borrowerId, newMarketValue, contract, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func buyLoanedPlayer(loanId, borrowerId, newMarketValue int, contract *domain.Contract, now time.Time, tx *sql.Tx) error {
+	c := completion{buyerId: borrowerId, newMarketValue: newMarketValue, chargeBuyer: true, now: now}
+	var marketValue int
+	err := tx.QueryRow("SELECT l.player_id, l.lender_id, l.buy_option_price, p.market_value "+
+		"FROM loan l "+
+		"JOIN player p ON p.id = l.player_id AND p.team_id = l.borrower_id "+
+		"WHERE l.id = ? AND l.status = ? AND l.borrower_id = ? AND l.buy_option_price IS NOT NULL",
+		loanId, domain.LoanActive, borrowerId).Scan(&c.playerId, &c.sellerId, &c.price, &marketValue)
+
+	if err != nil {
+		return errors.New("loan has no buy option to exercise")
+	}
+
+	res, err := tx.Exec("INSERT INTO transfer_list(player_id, asked_price, market_value, transferred, status, mode) VALUES(?, ?, ?, ?, ?, ?)",
+		c.playerId, c.price, marketValue, false, domain.TransferListed, domain.TransferLoanOption)
+
+	if err != nil {
+		return err
+	}
+
+	transferId, _ := res.LastInsertId()
+	c.transferId = int(transferId)
+
+	statements := []statement{
+		{"UPDATE loan SET status = ?, ended_at = ?, transfer_id = ? WHERE id = ? AND status = ?",
+			[]interface{}{domain.LoanBought, now, c.transferId, loanId, domain.LoanActive}},
+		{"UPDATE player SET team_id = ? WHERE id = ? AND team_id = ?",
+			[]interface{}{c.sellerId, c.playerId, borrowerId}},
+	}
+
+	if err = execAll(statements, errors.New("loan has no buy option to exercise"), tx); err != nil {
+		return err
+	}
+
+	if err = completeTransfer(c, tx); err != nil {
+		return err
+	}
+
+	contract.PlayerId = c.playerId
+	return replaceContract(contract, now, tx)
+}
+
+func (lr *LoanRepository) FindDueLoanIds(now time.Time) (ids []int, err error) {
+	rows, err := lr.db.Query("SELECT l.id FROM loan l WHERE l.status = ? "+
+		"AND ((l.ends_at IS NOT NULL AND l.ends_at <= ?) OR (l.duration_rounds IS NOT NULL AND "+roundsPlayed+" >= l.duration_rounds)) "+
+		"ORDER BY l.id", domain.LoanActive, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,176 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+)
+
+type MatchRepository struct {
+	db *sql.DB
+}
+
+func NewMatchRepository(db *sql.DB) *MatchRepository {
+	return &MatchRepository{
+		db: db,
+	}
+}
+
+func (mr *MatchRepository) CreateMatch(match *domain.Match) error {
+	tx, err := mr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = mr.createMatch(match, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (mr *MatchRepository) createMatch(match *domain.Match, tx *sql.Tx) error {
+	res, err := tx.Exec(
+		"INSERT INTO match_result(home_team_id, away_team_id, home_goals, away_goals, seed, played_at) VALUES(?, ?, ?, ?, ?, ?)",
+		match.HomeTeamId, match.AwayTeamId, match.HomeGoals, match.AwayGoals, match.Seed, match.PlayedAt)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	match.Id = int(id)
+
+	for _, event := range match.Events {
+		_, err = tx.Exec(
+			"INSERT INTO match_event(match_id, minute, type, team_id, player_id, related_player_id) VALUES(?, ?, ?, ?, ?, ?)",
+			match.Id, event.Minute, event.Type, event.TeamId, event.PlayerId, event.RelatedPlayerId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rating := range match.Ratings {
+		_, err = tx.Exec(
+			"INSERT INTO match_rating(match_id, player_id, team_id, player_name, position, started, minutes_played, rating) VALUES(?, ?, ?, ?, ?, ?, ?, ?)",
+			match.Id, rating.PlayerId, rating.TeamId, rating.PlayerName, rating.Position, rating.Started, rating.MinutesPlayed, rating.Rating)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mr *MatchRepository) GetMatch(id int) (domain.Match, error) {
+	matches, err := mr.getMatches("WHERE m.id = ?", id)
+
+	if err != nil {
+		return domain.Match{}, err
+	}
+
+	if len(matches) == 0 {
+		return domain.Match{}, sql.ErrNoRows
+	}
+
+	match := matches[0]
+
+	if match.Events, err = mr.getEvents(id); err != nil {
+		return domain.Match{}, err
+	}
+
+	if match.Ratings, err = mr.getRatings(id); err != nil {
+		return domain.Match{}, err
+	}
+
+	return match, nil
+}
+
+func (mr *MatchRepository) FindMatchesByTeamId(teamId int) ([]domain.Match, error) {
+	return mr.getMatches("WHERE m.home_team_id = ? OR m.away_team_id = ?", teamId, teamId)
+}
+
+func (mr *MatchRepository) getMatches(where string, args ...interface{}) (matches []domain.Match, err error) {
+	rows, err := mr.db.Query("SELECT m.id, m.home_team_id, COALESCE(th.name, ''), m.away_team_id, COALESCE(ta.name, ''), m.home_goals, m.away_goals, m.seed, m.played_at "+
+		"FROM match_result m "+
+		"LEFT JOIN team th ON th.id = m.home_team_id "+
+		"LEFT JOIN team ta ON ta.id = m.away_team_id "+
+		where+" "+
+		"ORDER BY m.played_at DESC, m.id DESC", args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var m domain.Match
+		err = rows.Scan(&m.Id, &m.HomeTeamId, &m.HomeTeamName, &m.AwayTeamId, &m.AwayTeamName, &m.HomeGoals, &m.AwayGoals, &m.Seed, &m.PlayedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, m)
+	}
+
+	return matches, rows.Err()
+}
+
+func (mr *MatchRepository) getEvents(matchId int) (events []domain.MatchEvent, err error) {
+	rows, err := mr.db.Query("SELECT minute, type, team_id, player_id, related_player_id FROM match_event WHERE match_id = ? ORDER BY id", matchId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var e domain.MatchEvent
+		var relatedPlayerId sql.NullInt64
+
+		if err = rows.Scan(&e.Minute, &e.Type, &e.TeamId, &e.PlayerId, &relatedPlayerId); err != nil {
+			return nil, err
+		}
+
+		if relatedPlayerId.Valid {
+			id := int(relatedPlayerId.Int64)
+			e.RelatedPlayerId = &id
+		}
+
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+func (mr *MatchRepository) getRatings(matchId int) (ratings []domain.PlayerRating, err error) {
+	rows, err := mr.db.Query("SELECT r.player_id, r.team_id, r.player_name, r.position, r.started, r.minutes_played, r.rating "+
+		"FROM match_rating r "+
+		"JOIN match_result m ON m.id = r.match_id "+
+		"WHERE r.match_id = ? "+
+		"ORDER BY CASE WHEN r.team_id = m.home_team_id THEN 0 ELSE 1 END, r.started DESC, r.rating DESC, r.player_id", matchId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var r domain.PlayerRating
+
+		if err = rows.Scan(&r.PlayerId, &r.TeamId, &r.PlayerName, &r.Position, &r.Started, &r.MinutesPlayed, &r.Rating); err != nil {
+			return nil, err
+		}
+
+		ratings = append(ratings, r)
+	}
+
+	return ratings, rows.Err()
+}
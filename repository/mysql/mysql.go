@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/go-sql-driver/mysql"
+)
+
+var (
+	_ repository.PlayerRepository         = (*PlayerRepository)(nil)
+	_ repository.TeamRepository           = (*TeamRepository)(nil)
+	_ repository.AccountRepository        = (*AccountRepository)(nil)
+	_ repository.TransferRepository       = (*TransferRepository)(nil)
+	_ repository.AccountTokenRepository   = (*AccountTokenRepository)(nil)
+	_ repository.SessionRepository        = (*SessionRepository)(nil)
+	_ repository.MatchRepository          = (*MatchRepository)(nil)
+	_ repository.LeagueRepository         = (*LeagueRepository)(nil)
+	_ repository.OfferRepository          = (*OfferRepository)(nil)
+	_ repository.LoanRepository           = (*LoanRepository)(nil)
+	_ repository.SwapRepository           = (*SwapRepository)(nil)
+	_ repository.TransferWindowRepository = (*TransferWindowRepository)(nil)
+	_ repository.LedgerRepository         = (*LedgerRepository)(nil)
+	_ repository.ContractRepository       = (*ContractRepository)(nil)
+)
+
+func DSN(dsn string) (string, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+
+	if err != nil {
+		return "", err
+	}
+
+	cfg.ParseTime = true
+	cfg.ClientFoundRows = true
+	return cfg.FormatDSN(), nil
+}
+
+func NewRepositories(db *sql.DB) repository.Repositories {
+	playerRepository := NewPlayerRepository(db)
+	teamRepository := NewTeamRepository(db)
+	matchRepository := NewMatchRepository(db)
+
+	return repository.Repositories{
+		Players:       playerRepository,
+		Teams:         teamRepository,
+		Accounts:      NewAccountRepository(db, teamRepository, playerRepository),
+		Transfers:     NewTransferRepository(db),
+		AccountTokens: NewAccountTokenRepository(db),
+		Sessions:      NewSessionRepository(db),
+		Matches:       matchRepository,
+		Leagues:       NewLeagueRepository(db, matchRepository),
+		Offers:        NewOfferRepository(db),
+		Loans:         NewLoanRepository(db),
+		Swaps:         NewSwapRepository(db),
+		Windows:       NewTransferWindowRepository(db),
+		Ledger:        NewLedgerRepository(db),
+		Contracts:     NewContractRepository(db),
+	}
+}
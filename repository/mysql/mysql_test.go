@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/migration"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/repository/repositorytest"
+	"github.com/go-sql-driver/mysql"
+	"os"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	db := openTestDatabase(t)
+
+	migrator, err := migration.NewMigrator(db, "mysql")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repositorytest.Run(t, func(t *testing.T) repository.Repositories {
+		dropTables(t, db)
+
+		if _, err := migrator.Up(0, false); err != nil {
+			t.Fatal(err)
+		}
+
+		return NewRepositories(db)
+	})
+}
+
+func TestUnchangedRowsCountAsAffected(t *testing.T) {
+	db := openTestDatabase(t)
+	dropTables(t, db)
+
+	if _, err := db.Exec("CREATE TABLE counter (id INTEGER NOT NULL, value INTEGER NOT NULL, PRIMARY KEY (id))"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec("INSERT INTO counter(id, value) VALUES(1, 5)"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := db.Exec("UPDATE counter SET value = value + 0 WHERE id = 1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		t.Fatalf("expected an unchanged but matched row to be reported, got %d", rowsAffected)
+	}
+}
+
+func openTestDatabase(t *testing.T) *sql.DB {
+	dsn := os.Getenv("SOCCER_MANAGER_TEST_MYSQL_DSN")
+
+	if dsn == "" && os.Getenv("CI") != "" {
+		t.Fatal("SOCCER_MANAGER_TEST_MYSQL_DSN must be set in CI")
+	}
+
+	if dsn == "" {
+		t.Skip("SOCCER_MANAGER_TEST_MYSQL_DSN not set")
+	}
+
+	dsn, err := DSN(dsn)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := mysql.ParseDSN(dsn)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.MultiStatements = true
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func dropTables(t *testing.T, db *sql.DB) {
+	rows, err := db.Query("SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE()")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tables []string
+
+	for rows.Next() {
+		var table string
+
+		if err = rows.Scan(&table); err != nil {
+			t.Fatal(err)
+		}
+
+		tables = append(tables, table)
+	}
+
+	rows.Close()
+	statements := "SET FOREIGN_KEY_CHECKS = 0;"
+
+	for _, table := range tables {
+		statements += "DROP TABLE `" + table + "`;"
+	}
+
+	statements += "SET FOREIGN_KEY_CHECKS = 1;"
+
+	if _, err = db.Exec(statements); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,337 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"strings"
+	"time"
+)
+
+type OfferRepository struct {
+	db *sql.DB
+}
+
+func NewOfferRepository(db *sql.DB) *OfferRepository {
+	return &OfferRepository{
+		db: db,
+	}
+}
+
+const offerQuery = "SELECT o.id, o.player_id, p.first_name, p.last_name, o.buyer_id, tb.name, o.seller_id, ts.name, " +
+	"o.amount, o.status, o.awaiting_id, o.expires_at, o.created_at, o.updated_at, o.transfer_id, " +
+	"o.contract_wage, o.contract_years, o.contract_release_clause " +
+	"FROM transfer_offer o " +
+	"JOIN player p ON p.id = o.player_id " +
+	"JOIN team tb ON tb.id = o.buyer_id " +
+	"JOIN team ts ON ts.id = o.seller_id "
+
+func (ofr *OfferRepository) CreateOffer(offer *domain.Offer) error {
+	tx, err := ofr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	var wage, years, releaseClause interface{}
+
+	if offer.Contract != nil {
+		wage, years, releaseClause = offer.Contract.Wage, offer.Contract.Years, offer.Contract.ReleaseClause
+	}
+
+	res, err := tx.Exec("INSERT INTO transfer_offer(player_id, buyer_id, seller_id, amount, status, awaiting_id, expires_at, created_at, updated_at, "+
+		"contract_wage, contract_years, contract_release_clause) "+
+		"SELECT id, ?, team_id, ?, ?, team_id, ?, ?, ?, ?, ?, ? FROM player WHERE id = ? AND team_id = ? AND team_id != ? "+
+		"AND NOT EXISTS (SELECT 1 FROM loan l WHERE l.player_id = player.id AND l.status = ?)",
+		offer.BuyerId, offer.Amount, domain.OfferOpen, offer.ExpiresAt, offer.CreatedAt, offer.CreatedAt, wage, years, releaseClause,
+		offer.PlayerId, offer.SellerId, offer.BuyerId, domain.LoanActive)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return errors.New("player does not belong to the selling team")
+	}
+
+	id, _ := res.LastInsertId()
+	offer.Id = int(id)
+
+	if err = insertOfferEvent(offer.Id, offer.BuyerId, domain.OfferActionOffer, offer.Amount, offer.CreatedAt, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (ofr *OfferRepository) GetOffer(id int) (domain.Offer, error) {
+	offers, err := ofr.getOffers(offerQuery+"WHERE o.id = ?", id)
+
+	if err != nil {
+		return domain.Offer{}, err
+	}
+
+	if len(offers) == 0 {
+		return domain.Offer{}, sql.ErrNoRows
+	}
+
+	offer := offers[0]
+	rows, err := ofr.db.Query("SELECT team_id, action, amount, created_at FROM transfer_offer_event WHERE offer_id = ? ORDER BY created_at, id", id)
+
+	if err != nil {
+		return domain.Offer{}, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.OfferEvent
+
+		if err = rows.Scan(&event.TeamId, &event.Action, &event.Amount, &event.CreatedAt); err != nil {
+			return domain.Offer{}, err
+		}
+
+		offer.Events = append(offer.Events, event)
+	}
+
+	return offer, rows.Err()
+}
+
+func (ofr *OfferRepository) FindOffers(teamId int, status string) ([]domain.Offer, error) {
+	where := []string{"(o.buyer_id = ? OR o.seller_id = ?)"}
+	args := []interface{}{teamId, teamId}
+
+	if status != "" {
+		where = append(where, "o.status = ?")
+		args = append(args, status)
+	}
+
+	return ofr.getOffers(offerQuery+"WHERE "+strings.Join(where, " AND ")+" ORDER BY o.updated_at DESC, o.id DESC", args...)
+}
+
+func (ofr *OfferRepository) getOffers(query string, args ...interface{}) (offers []domain.Offer, err error) {
+	rows, err := ofr.db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var offer domain.Offer
+		var firstName, lastName string
+		var transferId, wage, years sql.NullInt64
+		var releaseClause *int
+
+		err = rows.Scan(&offer.Id, &offer.PlayerId, &firstName, &lastName, &offer.BuyerId, &offer.BuyerName, &offer.SellerId, &offer.SellerName,
+			&offer.Amount, &offer.Status, &offer.AwaitingTeamId, &offer.ExpiresAt, &offer.CreatedAt, &offer.UpdatedAt, &transferId,
+			&wage, &years, &releaseClause)
+
+		if err != nil {
+			return nil, err
+		}
+
+		offer.PlayerName = strings.TrimSpace(firstName + " " + lastName)
+		offer.Contract = contractTerms(wage, years, releaseClause)
+
+		if transferId.Valid {
+			id := int(transferId.Int64)
+			offer.TransferId = &id
+		}
+
+		offers = append(offers, offer)
+	}
+
+	return offers, rows.Err()
+}
+
+func (ofr *OfferRepository) CounterOffer(offerId, teamId, amount int, expiresAt, now time.Time) error {
+	tx, err := ofr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	err = execAll([]statement{{"UPDATE transfer_offer SET amount = ?, expires_at = ?, updated_at = ?, " +
+		"awaiting_id = CASE WHEN awaiting_id = buyer_id THEN seller_id ELSE buyer_id END " +
+		"WHERE id = ? AND status = ? AND awaiting_id = ? AND expires_at > ?",
+		[]interface{}{amount, expiresAt, now, offerId, domain.OfferOpen, teamId, now}}}, errors.New("offer is no longer open"), tx)
+
+	if err == nil {
+		err = insertOfferEvent(offerId, teamId, domain.OfferActionCounter, amount, now, tx)
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+var offerCloseActions = map[string]string{
+	domain.OfferRejected:  domain.OfferActionReject,
+	domain.OfferWithdrawn: domain.OfferActionWithdraw,
+}
+
+func (ofr *OfferRepository) CloseOffer(offerId, teamId int, status string, now time.Time) error {
+	action, ok := offerCloseActions[status]
+
+	if !ok {
+		return errors.New("invalid offer status")
+	}
+
+	tx, err := ofr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	var amount int
+	err = tx.QueryRow("SELECT amount FROM transfer_offer WHERE id = ?", offerId).Scan(&amount)
+
+	if err == nil {
+		err = execAll([]statement{{"UPDATE transfer_offer SET status = ?, updated_at = ? " +
+			"WHERE id = ? AND status = ? AND (buyer_id = ? OR seller_id = ?) AND expires_at > ?",
+			[]interface{}{status, now, offerId, domain.OfferOpen, teamId, teamId, now}}}, errors.New("offer is no longer open"), tx)
+	}
+
+	if err == nil {
+		err = insertOfferEvent(offerId, teamId, action, amount, now, tx)
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (ofr *OfferRepository) AcceptOffer(offerId, teamId, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	tx, err := ofr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = acceptOffer(offerId, teamId, newMarketValue, contract, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func acceptOffer(offerId, teamId, newMarketValue int, contract *domain.Contract, now time.Time, tx *sql.Tx) error {
+	c := completion{newMarketValue: newMarketValue, chargeBuyer: true, now: now}
+	var marketValue int
+	err := tx.QueryRow("SELECT o.player_id, o.seller_id, o.buyer_id, o.amount, p.market_value "+
+		"FROM transfer_offer o "+
+		"JOIN player p ON p.id = o.player_id AND p.team_id = o.seller_id "+
+		"WHERE o.id = ? AND o.status = ? AND o.awaiting_id = ? AND o.expires_at > ?",
+		offerId, domain.OfferOpen, teamId, now).Scan(&c.playerId, &c.sellerId, &c.buyerId, &c.price, &marketValue)
+
+	if err != nil {
+		return errors.New("offer is no longer open")
+	}
+
+	if err = withdrawListings(c.playerId, now, tx); err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("INSERT INTO transfer_list(player_id, asked_price, market_value, transferred, status, mode) VALUES(?, ?, ?, ?, ?, ?)",
+		c.playerId, c.price, marketValue, false, domain.TransferListed, domain.TransferDirectOffer)
+
+	if err != nil {
+		return err
+	}
+
+	transferId, _ := res.LastInsertId()
+	c.transferId = int(transferId)
+
+	if err = completeTransfer(c, tx); err != nil {
+		return err
+	}
+
+	contract.PlayerId = c.playerId
+
+	if err = replaceContract(contract, now, tx); err != nil {
+		return err
+	}
+
+	statements := []statement{
+		{"UPDATE transfer_offer SET status = ?, transfer_id = ?, updated_at = ? WHERE id = ? AND status = ?",
+			[]interface{}{domain.OfferAccepted, c.transferId, now, offerId, domain.OfferOpen}},
+	}
+
+	if err = execAll(statements, errors.New("offer is no longer open"), tx); err != nil {
+		return err
+	}
+
+	if err = insertOfferEvent(offerId, teamId, domain.OfferActionAccept, c.price, now, tx); err != nil {
+		return err
+	}
+
+	return cancelOffers(c.playerId, now, tx)
+}
+
+func withdrawListings(playerId int, now time.Time, tx *sql.Tx) error {
+	var auctions int
+	err := tx.QueryRow("SELECT COUNT(*) FROM transfer_list WHERE player_id = ? AND status = ? AND mode = ?",
+		playerId, domain.TransferListed, domain.TransferAuction).Scan(&auctions)
+
+	if err != nil {
+		return err
+	}
+
+	if auctions > 0 {
+		return errors.New("player is being auctioned")
+	}
+
+	return markListingsWithdrawn(playerId, now, tx)
+}
+
+func releaseListings(playerId int, now time.Time, tx *sql.Tx) error {
+	auctions, err := queryIds(tx, "SELECT id FROM transfer_list WHERE player_id = ? AND status = ? AND mode = ?",
+		playerId, domain.TransferListed, domain.TransferAuction)
+
+	if err != nil {
+		return err
+	}
+
+	for _, transferId := range auctions {
+		if err = refundBids(transferId, now, tx); err != nil {
+			return err
+		}
+	}
+
+	return markListingsWithdrawn(playerId, now, tx)
+}
+
+func markListingsWithdrawn(playerId int, now time.Time, tx *sql.Tx) error {
+	_, err := tx.Exec("UPDATE transfer_list SET status = ?, withdrawn_at = ?, "+
+		"transferred_from = (SELECT team_id FROM player WHERE player.id = transfer_list.player_id) "+
+		"WHERE player_id = ? AND status = ?",
+		domain.TransferWithdrawn, now, playerId, domain.TransferListed)
+
+	return err
+}
+
+func cancelOffers(playerId int, now time.Time, tx *sql.Tx) error {
+	_, err := tx.Exec("UPDATE transfer_offer SET status = ?, updated_at = ? WHERE player_id = ? AND status = ?",
+		domain.OfferCancelled, now, playerId, domain.OfferOpen)
+
+	return err
+}
+
+func insertOfferEvent(offerId, teamId int, action string, amount int, now time.Time, tx *sql.Tx) error {
+	_, err := tx.Exec("INSERT INTO transfer_offer_event(offer_id, team_id, action, amount, created_at) VALUES(?, ?, ?, ?, ?)",
+		offerId, teamId, action, amount, now)
+
+	return err
+}
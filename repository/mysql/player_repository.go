@@ -0,0 +1,264 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"strings"
+	"time"
+)
+
+const playerQuery = "SELECT p.id, p.first_name, p.last_name, p.age, p.country, p.position, p.market_value, p.team_id, " +
+	"COALESCE(a.pace, 0), COALESCE(a.shooting, 0), COALESCE(a.passing, 0), COALESCE(a.defending, 0), " +
+	"COALESCE(a.goalkeeping, 0), COALESCE(a.stamina, 0), COALESCE(a.potential, 0) " +
+	"FROM player p " +
+	"LEFT JOIN player_attributes a ON a.player_id = p.id "
+
+type PlayerRepository struct {
+	db *sql.DB
+}
+
+func NewPlayerRepository(db *sql.DB) *PlayerRepository {
+	return &PlayerRepository{
+		db: db,
+	}
+}
+
+func (pr *PlayerRepository) GetPlayer(id int) (domain.Player, error) {
+	players, err := pr.getPlayers(playerQuery+"WHERE p.id = ?", id)
+
+	if err != nil {
+		return domain.Player{}, err
+	}
+
+	if len(players) == 0 {
+		return domain.Player{}, sql.ErrNoRows
+	}
+
+	return players[0], nil
+}
+
+func (pr *PlayerRepository) GetPlayerOutOfTransferList(accountId, playerId int) (domain.Player, error) {
+	players, err := pr.getPlayers(
+		playerQuery+
+			"JOIN team t ON t.id = p.team_id "+
+			"WHERE p.id = ? AND t.account_id = ? "+
+			"AND NOT EXISTS (SELECT 1 FROM transfer_list tl WHERE tl.player_id = p.id AND tl.status = ?) "+
+			"AND NOT EXISTS (SELECT 1 FROM loan l WHERE l.player_id = p.id AND l.status = ?)",
+		playerId, accountId, domain.TransferListed, domain.LoanActive)
+
+	if err != nil {
+		return domain.Player{}, err
+	}
+
+	if len(players) == 0 {
+		return domain.Player{}, errors.New("Invalid player id or it is already in the transfer list")
+	}
+
+	return players[0], nil
+}
+
+func (pr *PlayerRepository) FindPlayers() (players []domain.Player, err error) {
+	return pr.getPlayers(playerQuery + "ORDER BY p.id")
+}
+
+func (pr *PlayerRepository) GetPlayersByTeamId(teamId int) (players []domain.Player, err error) {
+	return pr.getPlayers(playerQuery+"WHERE p.team_id = ?", teamId)
+}
+
+func (pr *PlayerRepository) FindFreeAgents(filter domain.FreeAgentFilter) (players []domain.Player, err error) {
+	where, args := []string{"p.team_id IS NULL"}, []interface{}{}
+
+	if filter.Country != "" {
+		where = append(where, "p.country = ?")
+		args = append(args, filter.Country)
+	}
+
+	for _, name := range strings.Fields(filter.PlayerName) {
+		where = append(where, "(p.first_name LIKE ? ESCAPE '!' OR p.last_name LIKE ? ESCAPE '!')")
+		args = append(args, "%"+escapeLike(name)+"%", "%"+escapeLike(name)+"%")
+	}
+
+	if filter.Position != "" {
+		where = append(where, "p.position = ?")
+		args = append(args, filter.Position)
+	}
+
+	if filter.MinAge > 0 {
+		where = append(where, "p.age >= ?")
+		args = append(args, filter.MinAge)
+	}
+
+	if filter.MaxAge > 0 {
+		where = append(where, "p.age <= ?")
+		args = append(args, filter.MaxAge)
+	}
+
+	if filter.MinMarketValue > 0 {
+		where = append(where, "p.market_value >= ?")
+		args = append(args, filter.MinMarketValue)
+	}
+
+	if filter.MaxMarketValue > 0 {
+		where = append(where, "p.market_value <= ?")
+		args = append(args, filter.MaxMarketValue)
+	}
+
+	return pr.getPlayers(playerQuery+"WHERE "+strings.Join(where, " AND ")+" ORDER BY p.market_value DESC, p.id LIMIT ?",
+		append(args, filter.Limit)...)
+}
+
+func (pr *PlayerRepository) getPlayers(query string, args ...interface{}) (players []domain.Player, err error) {
+	rows, err := pr.db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var player domain.Player
+		if err := rows.Scan(
+			&player.Id,
+			&player.FirstName,
+			&player.LastName,
+			&player.Age,
+			&player.Country,
+			&player.Position,
+			&player.MarketValue,
+			&player.TeamId,
+			&player.Attributes.Pace,
+			&player.Attributes.Shooting,
+			&player.Attributes.Passing,
+			&player.Attributes.Defending,
+			&player.Attributes.Goalkeeping,
+			&player.Attributes.Stamina,
+			&player.Attributes.Potential); err != nil {
+			return nil, err
+		}
+		players = append(players, player)
+	}
+
+	return players, nil
+}
+
+func (pr *PlayerRepository) CreatePlayer(player *domain.Player, tx *sql.Tx) error {
+	res, err := tx.Exec(
+		"INSERT INTO player(first_name, last_name, age, country, position, market_value, team_id) VALUES(?, ?, ?, ?, ?, ?, ?)",
+		player.FirstName, player.LastName, player.Age, player.Country, player.Position, player.MarketValue, player.TeamId)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	player.Id = int(id)
+
+	if err = insertAttributes(player, tx); err != nil {
+		return err
+	}
+
+	if player.Contract == nil || player.TeamId == nil {
+		return nil
+	}
+
+	player.Contract.PlayerId = player.Id
+	return signContract(player.Contract, tx)
+}
+
+func insertAttributes(player *domain.Player, tx *sql.Tx) error {
+	a := player.Attributes
+	_, err := tx.Exec(
+		"INSERT INTO player_attributes(player_id, pace, shooting, passing, defending, goalkeeping, stamina, potential) VALUES(?, ?, ?, ?, ?, ?, ?, ?)",
+		player.Id, a.Pace, a.Shooting, a.Passing, a.Defending, a.Goalkeeping, a.Stamina, a.Potential)
+
+	return err
+}
+
+func (pr *PlayerRepository) UpdatePlayer(accountId int, player *domain.Player) error {
+	_, err :=
+		pr.db.Exec("UPDATE player p "+
+			"JOIN team t ON t.id = p.team_id "+
+			"SET p.first_name = ?, p.last_name = ?, p.country = ? "+
+			"WHERE p.id = ? AND t.account_id = ?",
+			player.FirstName, player.LastName, player.Country, player.Id, accountId)
+
+	return err
+}
+
+func (pr *PlayerRepository) NewPlayer(player *domain.Player) error {
+	tx, err := pr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = pr.CreatePlayer(player, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (pr *PlayerRepository) UpdatePlayerById(player *domain.Player) error {
+	tx, err := pr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	_, err =
+		tx.Exec("UPDATE player SET first_name = ?, last_name = ?, country = ?, age = ?, position = ?, market_value = ?, team_id = ? WHERE id = ?",
+			player.FirstName, player.LastName, player.Country, player.Age, player.Position, player.MarketValue, player.TeamId, player.Id)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec("DELETE FROM player_attributes WHERE player_id = ?", player.Id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = insertAttributes(player, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (pr *PlayerRepository) DeletePlayer(id int, now time.Time) error {
+	tx, err := pr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = releaseListings(id, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec("UPDATE transfer_list SET player_id = NULL WHERE player_id = ?", id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	res, err := tx.Exec("DELETE FROM player WHERE id = ?", id)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
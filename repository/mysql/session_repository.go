@@ -0,0 +1,207 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+func (sr *SessionRepository) CreateRefreshToken(refreshToken *domain.RefreshToken) error {
+	res, err := sr.db.Exec(
+		"INSERT INTO refresh_token(account_id, family, token, access_token_id, access_expires_at, expires_at, created_at) VALUES(?, ?, ?, ?, ?, ?, ?)",
+		refreshToken.AccountId, refreshToken.Family, refreshToken.Token, refreshToken.AccessTokenId,
+		refreshToken.AccessExpiresAt, refreshToken.ExpiresAt, refreshToken.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	refreshToken.Id = int(id)
+	return nil
+}
+
+func (sr *SessionRepository) UseRefreshToken(token string, now time.Time) (refreshToken domain.RefreshToken, consumed bool, err error) {
+	res, err := sr.db.Exec(
+		"UPDATE refresh_token SET used_at = ? WHERE token = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?",
+		now, token, now)
+
+	if err != nil {
+		return refreshToken, false, err
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+
+	err = sr.db.QueryRow(
+		"SELECT id, account_id, family, token, access_token_id, access_expires_at, expires_at, used_at, revoked_at, created_at "+
+			"FROM refresh_token WHERE token = ?", token).Scan(
+		&refreshToken.Id,
+		&refreshToken.AccountId,
+		&refreshToken.Family,
+		&refreshToken.Token,
+		&refreshToken.AccessTokenId,
+		&refreshToken.AccessExpiresAt,
+		&refreshToken.ExpiresAt,
+		&refreshToken.UsedAt,
+		&refreshToken.RevokedAt,
+		&refreshToken.CreatedAt)
+
+	return refreshToken, rowsAffected == 1, err
+}
+
+func (sr *SessionRepository) RevokeFamily(family string, now time.Time) error {
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = sr.revokeFamily(family, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (sr *SessionRepository) RevokeAccountSessions(accountId int, now time.Time) error {
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	families, err := sr.findFamilies(tx, "SELECT DISTINCT family FROM refresh_token WHERE account_id = ? AND revoked_at IS NULL", accountId)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, family := range families {
+		if err = sr.revokeFamily(family, now, tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (sr *SessionRepository) RevokeSession(accessTokenId string, accountId int, accessExpiresAt, now time.Time) error {
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = sr.revokeAccessToken(accessTokenId, accountId, accessExpiresAt, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	families, err := sr.findFamilies(tx, "SELECT DISTINCT family FROM refresh_token WHERE access_token_id = ?", accessTokenId)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	for _, family := range families {
+		if err = sr.revokeFamily(family, now, tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err = tx.Exec("DELETE FROM revoked_token WHERE expires_at < ?", now); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (sr *SessionRepository) IsAccessTokenRevoked(accessTokenId string) (bool, error) {
+	var count int
+	err := sr.db.QueryRow("SELECT COUNT(*) FROM revoked_token WHERE token_id = ?", accessTokenId).Scan(&count)
+	return count > 0, err
+}
+
+func (sr *SessionRepository) revokeFamily(family string, now time.Time, tx *sql.Tx) error {
+	rows, err := tx.Query(
+		"SELECT access_token_id, account_id, access_expires_at FROM refresh_token WHERE family = ? AND access_expires_at > ?", family, now)
+
+	if err != nil {
+		return err
+	}
+
+	var accessTokens []domain.RefreshToken
+
+	for rows.Next() {
+		var accessToken domain.RefreshToken
+		if err := rows.Scan(&accessToken.AccessTokenId, &accessToken.AccountId, &accessToken.AccessExpiresAt); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		accessTokens = append(accessTokens, accessToken)
+	}
+
+	if err = rows.Close(); err != nil {
+		return err
+	}
+
+	for _, accessToken := range accessTokens {
+		if err = sr.revokeAccessToken(accessToken.AccessTokenId, accessToken.AccountId, accessToken.AccessExpiresAt, now, tx); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE refresh_token SET revoked_at = ? WHERE family = ? AND revoked_at IS NULL", now, family)
+	return err
+}
+
+func (sr *SessionRepository) revokeAccessToken(accessTokenId string, accountId int, expiresAt, now time.Time, tx *sql.Tx) error {
+	var count int
+	err := tx.QueryRow("SELECT COUNT(*) FROM revoked_token WHERE token_id = ?", accessTokenId).Scan(&count)
+
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO revoked_token(token_id, account_id, expires_at, revoked_at) VALUES(?, ?, ?, ?)",
+		accessTokenId, accountId, expiresAt, now)
+
+	return err
+}
+
+func (sr *SessionRepository) findFamilies(tx *sql.Tx, query string, args ...interface{}) (families []string, err error) {
+	rows, err := tx.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var family string
+		if err := rows.Scan(&family); err != nil {
+			return nil, err
+		}
+		families = append(families, family)
+	}
+
+	return families, rows.Err()
+}
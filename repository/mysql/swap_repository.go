@@ -0,0 +1,358 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"strings"
+	"time"
+)
+
+type SwapRepository struct {
+	db *sql.DB
+}
+
+func NewSwapRepository(db *sql.DB) *SwapRepository {
+	return &SwapRepository{
+		db: db,
+	}
+}
+
+const swapQuery = "SELECT s.id, s.proposer_id, tp.name, s.receiver_id, tr.name, s.cash_adjustment, s.status, s.proposed_at, s.responded_at " +
+	"FROM swap_deal s " +
+	"JOIN team tp ON tp.id = s.proposer_id " +
+	"JOIN team tr ON tr.id = s.receiver_id "
+
+func (sr *SwapRepository) CreateSwap(swap *domain.Swap) error {
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = createSwap(swap, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func createSwap(swap *domain.Swap, tx *sql.Tx) error {
+	res, err := tx.Exec("INSERT INTO swap_deal(proposer_id, receiver_id, cash_adjustment, status, proposed_at) VALUES(?, ?, ?, ?, ?)",
+		swap.ProposerId, swap.ReceiverId, swap.CashAdjustment, domain.SwapProposed, swap.ProposedAt)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	swap.Id = int(id)
+
+	for _, player := range swap.Players {
+		if player.FromTeamId != swap.ProposerId && player.FromTeamId != swap.ReceiverId {
+			return fmt.Errorf("player %d is not part of either team", player.PlayerId)
+		}
+
+		res, err = tx.Exec("INSERT INTO swap_player(swap_id, player_id, from_team_id) "+
+			"SELECT ?, id, team_id FROM player WHERE id = ? AND team_id = ? "+
+			"AND NOT EXISTS (SELECT 1 FROM loan l WHERE l.player_id = player.id AND l.status = ?)",
+			swap.Id, player.PlayerId, player.FromTeamId, domain.LoanActive)
+
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+			return fmt.Errorf("player %d cannot be swapped by team %d", player.PlayerId, player.FromTeamId)
+		}
+	}
+
+	return nil
+}
+
+func (sr *SwapRepository) GetSwap(id int) (domain.Swap, error) {
+	swaps, err := sr.getSwaps(swapQuery+"WHERE s.id = ?", id)
+
+	if err != nil {
+		return domain.Swap{}, err
+	}
+
+	if len(swaps) == 0 {
+		return domain.Swap{}, sql.ErrNoRows
+	}
+
+	return swaps[0], nil
+}
+
+func (sr *SwapRepository) FindSwaps(teamId int, status string) ([]domain.Swap, error) {
+	where := []string{"(s.proposer_id = ? OR s.receiver_id = ?)"}
+	args := []interface{}{teamId, teamId}
+
+	if status != "" {
+		where = append(where, "s.status = ?")
+		args = append(args, status)
+	}
+
+	return sr.getSwaps(swapQuery+"WHERE "+strings.Join(where, " AND ")+" ORDER BY s.proposed_at DESC, s.id DESC", args...)
+}
+
+func (sr *SwapRepository) getSwaps(query string, args ...interface{}) ([]domain.Swap, error) {
+	rows, err := sr.db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var swaps []domain.Swap
+
+	for rows.Next() {
+		var swap domain.Swap
+
+		err = rows.Scan(&swap.Id, &swap.ProposerId, &swap.ProposerName, &swap.ReceiverId, &swap.ReceiverName,
+			&swap.CashAdjustment, &swap.Status, &swap.ProposedAt, &swap.RespondedAt)
+
+		if err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+
+		swaps = append(swaps, swap)
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	for i := range swaps {
+		if swaps[i].Players, err = sr.getSwapPlayers(swaps[i].Id); err != nil {
+			return nil, err
+		}
+	}
+
+	return swaps, nil
+}
+
+func (sr *SwapRepository) getSwapPlayers(swapId int) (players []domain.SwapPlayer, err error) {
+	rows, err := sr.db.Query("SELECT sp.player_id, p.first_name, p.last_name, sp.from_team_id, p.market_value, sp.transfer_id "+
+		"FROM swap_player sp "+
+		"JOIN player p ON p.id = sp.player_id "+
+		"WHERE sp.swap_id = ? ORDER BY sp.from_team_id, sp.player_id", swapId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var player domain.SwapPlayer
+		var firstName, lastName string
+
+		if err = rows.Scan(&player.PlayerId, &firstName, &lastName, &player.FromTeamId, &player.MarketValue, &player.TransferId); err != nil {
+			return nil, err
+		}
+
+		player.PlayerName = strings.TrimSpace(firstName + " " + lastName)
+		players = append(players, player)
+	}
+
+	return players, rows.Err()
+}
+
+var swapCloseStatuses = map[string]bool{
+	domain.SwapRejected:  true,
+	domain.SwapWithdrawn: true,
+}
+
+func (sr *SwapRepository) CloseSwap(swapId, teamId int, status string, now time.Time) error {
+	if !swapCloseStatuses[status] {
+		return errors.New("invalid swap status")
+	}
+
+	res, err := sr.db.Exec("UPDATE swap_deal SET status = ?, responded_at = ? WHERE id = ? AND status = ? AND (proposer_id = ? OR receiver_id = ?)",
+		status, now, swapId, domain.SwapProposed, teamId, teamId)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return errors.New("swap is no longer open")
+	}
+
+	return nil
+}
+
+func (sr *SwapRepository) CompleteSwap(swapId, receiverId int, newMarketValues map[int]int, contracts map[int]*domain.Contract, now time.Time) error {
+	tx, err := sr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = completeSwap(swapId, receiverId, newMarketValues, contracts, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type swapMove struct {
+	playerId    int
+	fromTeamId  int
+	teamId      *int
+	marketValue int
+	loaned      bool
+}
+
+func completeSwap(swapId, receiverId int, newMarketValues map[int]int, contracts map[int]*domain.Contract, now time.Time, tx *sql.Tx) error {
+	var proposerId, cashAdjustment int
+	err := tx.QueryRow("SELECT proposer_id, cash_adjustment FROM swap_deal WHERE id = ? AND status = ? AND receiver_id = ?",
+		swapId, domain.SwapProposed, receiverId).Scan(&proposerId, &cashAdjustment)
+
+	if err != nil {
+		return errors.New("swap is no longer open")
+	}
+
+	moves, err := swapMoves(swapId, tx)
+
+	if err != nil {
+		return err
+	}
+
+	sides := map[int]bool{}
+
+	for _, move := range moves {
+		if move.teamId == nil || *move.teamId != move.fromTeamId || move.loaned {
+			return fmt.Errorf("player %d no longer belongs to team %d", move.playerId, move.fromTeamId)
+		}
+
+		sides[move.fromTeamId] = true
+	}
+
+	if !sides[proposerId] || !sides[receiverId] {
+		return errors.New("swap must exchange players from both teams")
+	}
+
+	for _, move := range moves {
+		newMarketValue, ok := newMarketValues[move.playerId]
+
+		if !ok {
+			return fmt.Errorf("missing market value for player %d", move.playerId)
+		}
+
+		contract, ok := contracts[move.playerId]
+
+		if !ok {
+			return fmt.Errorf("missing contract for player %d", move.playerId)
+		}
+
+		if err = swapPlayer(swapId, move, proposerId, receiverId, newMarketValue, now, tx); err != nil {
+			return err
+		}
+
+		contract.PlayerId = move.playerId
+
+		if err = replaceContract(contract, now, tx); err != nil {
+			return err
+		}
+	}
+
+	if cashAdjustment != 0 {
+		payerId, payeeId, amount := proposerId, receiverId, cashAdjustment
+
+		if cashAdjustment < 0 {
+			payerId, payeeId, amount = receiverId, proposerId, -cashAdjustment
+		}
+
+		statements := []statement{
+			{"UPDATE team SET available_cash = available_cash - ? WHERE id = ? AND available_cash >= ?",
+				[]interface{}{amount, payerId, amount}},
+			{"UPDATE team SET available_cash = available_cash + ? WHERE id = ?",
+				[]interface{}{amount, payeeId}},
+			ledgerStatement(domain.LedgerSwapAdjustment, domain.ReferenceSwap, swapId, teamAccount(payerId), teamAccount(payeeId), amount, now),
+		}
+
+		if err = execAll(statements, errors.New("insufficient funds for the cash adjustment"), tx); err != nil {
+			return err
+		}
+	}
+
+	err = execAll([]statement{{"UPDATE swap_deal SET status = ?, responded_at = ? WHERE id = ? AND status = ?",
+		[]interface{}{domain.SwapCompleted, now, swapId, domain.SwapProposed}}}, errors.New("swap is no longer open"), tx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, move := range moves {
+		_, err = tx.Exec("UPDATE swap_deal SET status = ?, responded_at = ? "+
+			"WHERE status = ? AND id IN (SELECT swap_id FROM swap_player WHERE player_id = ?)",
+			domain.SwapCancelled, now, domain.SwapProposed, move.playerId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func swapMoves(swapId int, tx *sql.Tx) (moves []swapMove, err error) {
+	rows, err := tx.Query("SELECT sp.player_id, sp.from_team_id, p.team_id, p.market_value, "+
+		"EXISTS (SELECT 1 FROM loan l WHERE l.player_id = p.id AND l.status = ?) "+
+		"FROM swap_player sp "+
+		"JOIN player p ON p.id = sp.player_id "+
+		"WHERE sp.swap_id = ? ORDER BY sp.player_id", domain.LoanActive, swapId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var move swapMove
+
+		if err = rows.Scan(&move.playerId, &move.fromTeamId, &move.teamId, &move.marketValue, &move.loaned); err != nil {
+			return nil, err
+		}
+
+		moves = append(moves, move)
+	}
+
+	return moves, rows.Err()
+}
+
+func swapPlayer(swapId int, move swapMove, proposerId, receiverId, newMarketValue int, now time.Time, tx *sql.Tx) error {
+	if err := withdrawListings(move.playerId, now, tx); err != nil {
+		return err
+	}
+
+	if err := cancelOffers(move.playerId, now, tx); err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("INSERT INTO transfer_list(player_id, asked_price, market_value, transferred, status, mode) VALUES(?, ?, ?, ?, ?, ?)",
+		move.playerId, move.marketValue, move.marketValue, false, domain.TransferListed, domain.TransferSwap)
+
+	if err != nil {
+		return err
+	}
+
+	transferId, _ := res.LastInsertId()
+	c := completion{transferId: int(transferId), playerId: move.playerId, sellerId: move.fromTeamId, buyerId: proposerId,
+		price: move.marketValue, newMarketValue: newMarketValue, now: now}
+
+	if move.fromTeamId == proposerId {
+		c.buyerId = receiverId
+	}
+
+	statements := append(transferStatements(c), statement{"UPDATE swap_player SET transfer_id = ? WHERE swap_id = ? AND player_id = ?",
+		[]interface{}{c.transferId, swapId, move.playerId}})
+
+	return execAll(statements, errors.New("swap not executed"), tx)
+}
@@ -0,0 +1,321 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"math"
+	"time"
+)
+
+type TeamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{
+		db: db,
+	}
+}
+
+func (tr *TeamRepository) GetTeamById(id int) (*domain.Team, error) {
+	return tr.getTeam("SELECT id, name, country, available_cash, account_id FROM team WHERE id = ?", id)
+}
+
+func (tr *TeamRepository) GetTeamByAccountId(id int) (*domain.Team, error) {
+	return tr.getTeam("SELECT id, name, country, available_cash, account_id FROM team WHERE account_id = ?", id)
+}
+
+func (tr *TeamRepository) getTeamByAccountId(id int, tx *sql.Tx) (*domain.Team, error) {
+	team := &domain.Team{}
+	return team, tx.QueryRow("SELECT id, name, country, available_cash, account_id FROM team WHERE account_id = ?", id).Scan(
+		&team.Id,
+		&team.Name,
+		&team.Country,
+		&team.AvailableCash,
+		&team.AccountId)
+}
+
+func (tr *TeamRepository) getTeam(query string, args ...interface{}) (*domain.Team, error) {
+	team := &domain.Team{}
+	return team, tr.db.QueryRow(query, args...).Scan(
+		&team.Id,
+		&team.Name,
+		&team.Country,
+		&team.AvailableCash,
+		&team.AccountId)
+}
+
+func (tr *TeamRepository) FindTeams() (teams []domain.Team, err error) {
+	rows, err := tr.db.Query("SELECT id, name, country, available_cash, account_id FROM team ORDER BY id")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var team domain.Team
+		if err := rows.Scan(
+			&team.Id,
+			&team.Name,
+			&team.Country,
+			&team.AvailableCash,
+			&team.AccountId); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, rows.Err()
+}
+
+func (tr *TeamRepository) GetSquadSummary(teamId int) (domain.SquadSummary, error) {
+	var averageAge float64
+	var goalKeepers, defenders, midfielders, forwards int
+	summary := domain.SquadSummary{}
+
+	err := tr.db.QueryRow("SELECT COUNT(p.id), COALESCE(SUM(p.market_value), 0), COALESCE(AVG(p.age), 0), "+
+		"COALESCE(SUM(CASE WHEN p.position = ? THEN 1 ELSE 0 END), 0), "+
+		"COALESCE(SUM(CASE WHEN p.position = ? THEN 1 ELSE 0 END), 0), "+
+		"COALESCE(SUM(CASE WHEN p.position = ? THEN 1 ELSE 0 END), 0), "+
+		"COALESCE(SUM(CASE WHEN p.position = ? THEN 1 ELSE 0 END), 0), "+
+		"COUNT(tl.id), COALESCE(SUM(tl.asked_price), 0) "+
+		"FROM player p "+
+		"LEFT JOIN transfer_list tl ON tl.player_id = p.id AND tl.status = ? "+
+		"WHERE p.team_id = ?",
+		domain.GoalKeeper, domain.Defender, domain.Midfielder, domain.Forward, domain.TransferListed, teamId).Scan(
+		&summary.Players,
+		&summary.Value,
+		&averageAge,
+		&goalKeepers,
+		&defenders,
+		&midfielders,
+		&forwards,
+		&summary.ListedPlayers,
+		&summary.ListedAskingPrice)
+
+	if err != nil {
+		return domain.SquadSummary{}, err
+	}
+
+	summary.AverageAge = math.Round(averageAge*10) / 10
+	summary.Positions = map[domain.PlayerPosition]int{
+		domain.GoalKeeper: goalKeepers,
+		domain.Defender:   defenders,
+		domain.Midfielder: midfielders,
+		domain.Forward:    forwards,
+	}
+
+	return summary, nil
+}
+
+func (tr *TeamRepository) CreateTeam(team *domain.Team, now time.Time, tx *sql.Tx) error {
+	res, err := tx.Exec(
+		"INSERT INTO team(name, country, available_cash, account_id) VALUES(?, ?, ?, ?)",
+		team.Name, team.Country, team.AvailableCash, team.AccountId)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	team.Id = int(id)
+
+	if team.AvailableCash == 0 {
+		return nil
+	}
+
+	opening := ledgerStatement(domain.LedgerOpeningBalance, domain.ReferenceTeam, team.Id, externalAccount, teamAccount(team.Id), team.AvailableCash, now)
+
+	if team.AvailableCash < 0 {
+		opening = ledgerStatement(domain.LedgerOpeningBalance, domain.ReferenceTeam, team.Id, teamAccount(team.Id), externalAccount, -team.AvailableCash, now)
+	}
+
+	_, err = tx.Exec(opening.query, opening.args...)
+	return err
+}
+
+func (tr *TeamRepository) UpdateTeam(accountId int, team *domain.Team) error {
+	_, err :=
+		tr.db.Exec("UPDATE team SET name = ?, country = ? WHERE id = ? AND account_id = ?", team.Name, team.Country, team.Id, accountId)
+
+	return err
+}
+
+func (tr *TeamRepository) NewTeam(team *domain.Team, now time.Time) error {
+	tx, err := tr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = tr.CreateTeam(team, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (tr *TeamRepository) UpdateTeamById(team *domain.Team, now time.Time) error {
+	tx, err := tr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	statements := []statement{
+		{ledgerInsert + "SELECT ?, ?, id, ?, NULL, ?, id, ? - available_cash, ? FROM team WHERE id = ? AND available_cash < ?",
+			[]interface{}{domain.LedgerAdminAdjustment, domain.ReferenceTeam, domain.LedgerExternal, domain.LedgerTeam, team.AvailableCash, now, team.Id, team.AvailableCash}},
+		{ledgerInsert + "SELECT ?, ?, id, ?, id, ?, NULL, available_cash - ?, ? FROM team WHERE id = ? AND available_cash > ?",
+			[]interface{}{domain.LedgerAdminAdjustment, domain.ReferenceTeam, domain.LedgerTeam, domain.LedgerExternal, team.AvailableCash, now, team.Id, team.AvailableCash}},
+		{"UPDATE team SET name = ?, country = ?, available_cash = ?, account_id = ? WHERE id = ?",
+			[]interface{}{team.Name, team.Country, team.AvailableCash, team.AccountId, team.Id}},
+	}
+
+	for _, statement := range statements {
+		if _, err = tx.Exec(statement.query, statement.args...); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (tr *TeamRepository) DeleteTeam(id int, now time.Time) error {
+	tx, err := tr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = tr.deleteTeam(id, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (tr *TeamRepository) deleteTeam(id int, now time.Time, tx *sql.Tx) error {
+	var running int
+	err := tx.QueryRow("SELECT COUNT(*) FROM league_team lt JOIN league l ON l.id = lt.league_id WHERE lt.team_id = ? AND l.status = ?",
+		id, domain.LeagueRunning).Scan(&running)
+
+	if err != nil {
+		return err
+	}
+
+	if running > 0 {
+		return repository.ErrTeamInRunningLeague
+	}
+
+	if err = withdrawTeamBids(id, now, tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE player SET team_id = (SELECT l.lender_id FROM loan l WHERE l.player_id = player.id AND l.status = ?) "+
+		"WHERE team_id = ? AND id IN (SELECT player_id FROM loan WHERE borrower_id = ? AND status = ?)",
+		domain.LoanActive, id, id, domain.LoanActive)
+
+	if err != nil {
+		return err
+	}
+
+	playerIds, err := queryIds(tx, "SELECT id FROM player WHERE team_id = ?", id)
+
+	if err != nil {
+		return err
+	}
+
+	for _, playerId := range playerIds {
+		if err = releaseListings(playerId, now, tx); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE contract SET status = ?, ended_at = ? WHERE status = ? AND player_id IN (SELECT id FROM player WHERE team_id = ?)",
+		domain.ContractTerminated, now, domain.ContractActive, id)
+
+	if err != nil {
+		return err
+	}
+
+	statements := []string{
+		"UPDATE transfer_list SET transferred_from = NULL WHERE transferred_from = ?",
+		"UPDATE transfer_list SET transferred_to = NULL WHERE transferred_to = ?",
+		"UPDATE player SET team_id = NULL WHERE team_id = ?",
+		"DELETE FROM fixture WHERE ? IN (home_team_id, away_team_id)",
+		"DELETE FROM league_team WHERE team_id = ?",
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, id); err != nil {
+			return err
+		}
+	}
+
+	res, err := tx.Exec("DELETE FROM team WHERE id = ?", id)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func withdrawTeamBids(teamId int, now time.Time, tx *sql.Tx) error {
+	bidOn, err := queryIds(tx, "SELECT transfer_id FROM transfer_bid WHERE team_id = ? AND status = ?", teamId, domain.BidActive)
+
+	if err != nil {
+		return err
+	}
+
+	if err = releaseBids("team_id", teamId, now, tx); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec("DELETE FROM transfer_bid WHERE team_id = ?", teamId); err != nil {
+		return err
+	}
+
+	for _, transferId := range bidOn {
+		_, err = tx.Exec("UPDATE transfer_list SET highest_bid = (SELECT MAX(b.amount) FROM transfer_bid b WHERE b.transfer_id = transfer_list.id) "+
+			"WHERE id = ?", transferId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func queryIds(tx *sql.Tx, query string, args ...interface{}) (ids []int, err error) {
+	rows, err := tx.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,753 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"strings"
+	"time"
+)
+
+type TransferRepository struct {
+	db *sql.DB
+}
+
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{
+		db: db,
+	}
+}
+
+func (tr *TransferRepository) NewTransfer(playerId, askedPrice, marketValue int, expiresAt *time.Time) (transferId int, err error) {
+	res, err := tr.db.Exec(
+		"INSERT INTO transfer_list(player_id, asked_price, market_value, transferred, status, deadline) VALUES(?, ?, ?, ?, ?, ?)",
+		playerId, askedPrice, marketValue, false, domain.TransferListed, expiresAt)
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, _ := res.LastInsertId()
+	return int(id), nil
+}
+
+var transferSortColumns = map[string]string{
+	"id":          "tl.id",
+	"askedPrice":  "tl.asked_price",
+	"marketValue": "tl.market_value",
+	"age":         "p.age",
+}
+
+func (tr *TransferRepository) FindTransfers(filter domain.TransferFilter) (transfers []domain.Transfer, total int, err error) {
+	where, args := transferFilterConditions(filter)
+
+	err = tr.db.QueryRow("SELECT COUNT(*) "+
+		"FROM transfer_list tl "+
+		"JOIN player p ON p.id = tl.player_id "+
+		"JOIN team t ON t.id = p.team_id "+
+		"WHERE "+strings.Join(where, " AND "), args...).Scan(&total)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := transferSortColumns[filter.SortBy]
+
+	if !ok {
+		column = "tl.id"
+	}
+
+	direction, comparator := "ASC", ">"
+
+	if filter.Descending {
+		direction, comparator = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		if column == "tl.id" {
+			where = append(where, "tl.id "+comparator+" ?")
+			args = append(args, filter.After.Id)
+		} else {
+			where = append(where, "("+column+" "+comparator+" ? OR ("+column+" = ? AND tl.id "+comparator+" ?))")
+			args = append(args, filter.After.Value, filter.After.Value, filter.After.Id)
+		}
+	}
+
+	query := "SELECT tl.id, tl.asked_price, tl.market_value, tl.status, tl.mode, tl.deadline, tl.sealed, tl.highest_bid, tl.bid_count, " +
+		"p.id player_id, p.age, p.country player_country, p.first_name, p.last_name, p.position, p.team_id, t.name, " +
+		"COALESCE(a.pace, 0), COALESCE(a.shooting, 0), COALESCE(a.passing, 0), COALESCE(a.defending, 0), " +
+		"COALESCE(a.goalkeeping, 0), COALESCE(a.stamina, 0), COALESCE(a.potential, 0) " +
+		"FROM transfer_list tl " +
+		"JOIN player p ON p.id = tl.player_id " +
+		"JOIN team t ON t.id = p.team_id " +
+		"LEFT JOIN player_attributes a ON a.player_id = p.id " +
+		"WHERE " + strings.Join(where, " AND ") + " " +
+		"ORDER BY " + column + " " + direction + ", tl.id " + direction + " " +
+		"LIMIT ?"
+
+	transfers, err = tr.getTransfers(query, append(args, filter.Limit)...)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return transfers, total, nil
+}
+
+func transferFilterConditions(filter domain.TransferFilter) (where []string, args []interface{}) {
+	where = append(where, "tl.status = ?")
+	args = append(args, domain.TransferListed)
+
+	if filter.Country != "" {
+		where = append(where, "p.country = ?")
+		args = append(args, filter.Country)
+	}
+
+	if filter.TeamName != "" {
+		where = append(where, "t.name LIKE ? ESCAPE '!'")
+		args = append(args, "%"+escapeLike(filter.TeamName)+"%")
+	}
+
+	for _, name := range strings.Fields(filter.PlayerName) {
+		where = append(where, "(p.first_name LIKE ? ESCAPE '!' OR p.last_name LIKE ? ESCAPE '!')")
+		args = append(args, "%"+escapeLike(name)+"%", "%"+escapeLike(name)+"%")
+	}
+
+	if filter.Position != "" {
+		where = append(where, "p.position = ?")
+		args = append(args, filter.Position)
+	}
+
+	if filter.Mode != "" {
+		where = append(where, "tl.mode = ?")
+		args = append(args, filter.Mode)
+	}
+
+	if filter.MinAge > 0 {
+		where = append(where, "p.age >= ?")
+		args = append(args, filter.MinAge)
+	}
+
+	if filter.MaxAge > 0 {
+		where = append(where, "p.age <= ?")
+		args = append(args, filter.MaxAge)
+	}
+
+	if filter.MinAskedPrice > 0 {
+		where = append(where, "tl.asked_price >= ?")
+		args = append(args, filter.MinAskedPrice)
+	}
+
+	if filter.MaxAskedPrice > 0 {
+		where = append(where, "tl.asked_price <= ?")
+		args = append(args, filter.MaxAskedPrice)
+	}
+
+	if filter.MinMarketValue > 0 {
+		where = append(where, "tl.market_value >= ?")
+		args = append(args, filter.MinMarketValue)
+	}
+
+	if filter.MaxMarketValue > 0 {
+		where = append(where, "tl.market_value <= ?")
+		args = append(args, filter.MaxMarketValue)
+	}
+
+	return where, args
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+func (tr *TransferRepository) getTransfers(query string, args ...interface{}) (transfers []domain.Transfer, err error) {
+	rows, err := tr.db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var transfer domain.Transfer
+		var deadline sql.NullTime
+		var sealed bool
+		var highestBid sql.NullInt64
+		var bids int
+		if err := rows.Scan(
+			&transfer.Id,
+			&transfer.AskedPrice,
+			&transfer.MarketValue,
+			&transfer.Status,
+			&transfer.Mode,
+			&deadline,
+			&sealed,
+			&highestBid,
+			&bids,
+			&transfer.Player.Id,
+			&transfer.Player.Age,
+			&transfer.Player.Country,
+			&transfer.Player.FirstName,
+			&transfer.Player.LastName,
+			&transfer.Player.Position,
+			&transfer.Player.TeamId,
+			&transfer.TeamName,
+			&transfer.Player.Attributes.Pace,
+			&transfer.Player.Attributes.Shooting,
+			&transfer.Player.Attributes.Passing,
+			&transfer.Player.Attributes.Defending,
+			&transfer.Player.Attributes.Goalkeeping,
+			&transfer.Player.Attributes.Stamina,
+			&transfer.Player.Attributes.Potential); err != nil {
+			return nil, err
+		}
+
+		if transfer.Mode == domain.TransferAuction {
+			transfer.Auction = &domain.Auction{Deadline: deadline.Time, Sealed: sealed, Bids: bids}
+
+			if highestBid.Valid && !sealed {
+				highest := int(highestBid.Int64)
+				transfer.Auction.HighestBid = &highest
+			}
+		} else if deadline.Valid {
+			expiresAt := deadline.Time
+			transfer.ExpiresAt = &expiresAt
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, nil
+}
+
+func (tr *TransferRepository) GetTransfer(id int) (domain.Transfer, error) {
+	query := "SELECT tl.id, tl.asked_price, tl.market_value, tl.status, tl.mode, tl.deadline, tl.sealed, tl.highest_bid, tl.bid_count, " +
+		"p.id player_id, p.age, p.country player_country, p.first_name, p.last_name, p.position, p.team_id, t.name, " +
+		"COALESCE(a.pace, 0), COALESCE(a.shooting, 0), COALESCE(a.passing, 0), COALESCE(a.defending, 0), " +
+		"COALESCE(a.goalkeeping, 0), COALESCE(a.stamina, 0), COALESCE(a.potential, 0) " +
+		"FROM transfer_list tl " +
+		"JOIN player p ON p.id = tl.player_id " +
+		"JOIN team t ON t.id = p.team_id " +
+		"LEFT JOIN player_attributes a ON a.player_id = p.id " +
+		"WHERE tl.status = ? AND tl.id = ?"
+
+	transfers, err := tr.getTransfers(query, domain.TransferListed, id)
+
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+
+	if len(transfers) == 0 {
+		return domain.Transfer{}, sql.ErrNoRows
+	}
+
+	return transfers[0], nil
+}
+
+func (tr *TransferRepository) ConfirmTransfer(transferId int, buyerId int, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	tx, err := tr.db.Begin()
+
+	if err != nil {
+		return errors.New("internal error")
+	}
+
+	c := completion{transferId: transferId, buyerId: buyerId, newMarketValue: newMarketValue, chargeBuyer: true, now: now}
+	err = tx.QueryRow("SELECT tl.player_id, p.team_id, tl.asked_price "+
+		"FROM transfer_list tl "+
+		"JOIN player p ON p.id = tl.player_id "+
+		"WHERE tl.id = ? AND tl.status = ? AND tl.mode = ? AND p.team_id != ? AND (tl.deadline IS NULL OR tl.deadline > ?)",
+		transferId, domain.TransferListed, domain.TransferFixedPrice, buyerId, now).Scan(&c.playerId, &c.sellerId, &c.price)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return errors.New("transfer not executed")
+	}
+
+	if err = completeTransfer(c, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	contract.PlayerId = c.playerId
+
+	if err = replaceContract(contract, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type completion struct {
+	transferId     int
+	playerId       int
+	sellerId       int
+	buyerId        int
+	price          int
+	newMarketValue int
+	chargeBuyer    bool
+	now            time.Time
+}
+
+type statement struct {
+	query string
+	args  []interface{}
+}
+
+func transferStatements(c completion) []statement {
+	return []statement{
+		{"UPDATE transfer_list SET transferred = 1, status = ?, asked_price = ?, transferred_from = ?, transferred_to = ?, " +
+			"transferred_at = ?, value_after = ? WHERE id = ? AND status = ?",
+			[]interface{}{domain.TransferTransferred, c.price, c.sellerId, c.buyerId, c.now, c.newMarketValue, c.transferId, domain.TransferListed}},
+		{"UPDATE player SET team_id = ?, market_value = ? WHERE id = ? AND team_id = ?",
+			[]interface{}{c.buyerId, c.newMarketValue, c.playerId, c.sellerId}},
+	}
+}
+
+func completeTransfer(c completion, tx *sql.Tx) error {
+	statements := append(transferStatements(c), statement{"UPDATE team SET available_cash = available_cash + ? WHERE id = ?",
+		[]interface{}{c.price, c.sellerId}})
+
+	if c.chargeBuyer {
+		statements = append(statements, statement{"UPDATE team SET available_cash = available_cash - ? WHERE id = ? AND available_cash >= ?",
+			[]interface{}{c.price, c.buyerId, c.price}})
+	} else if c.price > 0 {
+		statements = append(statements, ledgerStatement(domain.LedgerBidReleased, domain.ReferenceTransfer, c.transferId,
+			escrowAccount(c.buyerId), teamAccount(c.buyerId), c.price, c.now))
+	}
+
+	if c.price > 0 {
+		statements = append(statements, ledgerStatement(domain.LedgerTransferFee, domain.ReferenceTransfer, c.transferId,
+			teamAccount(c.buyerId), teamAccount(c.sellerId), c.price, c.now))
+	}
+
+	return execAll(statements, errors.New("transfer not executed"), tx)
+}
+
+func execAll(statements []statement, failure error, tx *sql.Tx) error {
+	for _, statement := range statements {
+		res, err := tx.Exec(statement.query, statement.args...)
+
+		if err != nil {
+			return failure
+		}
+
+		if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+			return failure
+		}
+	}
+
+	return nil
+}
+
+const historyTime = "COALESCE(tl.transferred_at, tl.withdrawn_at, tl.deadline)"
+
+func (tr *TransferRepository) FindCompletedTransfers(filter domain.TransferHistoryFilter) (transfers []domain.CompletedTransfer, err error) {
+	where := []string{"tl.status IN (?, ?, ?)"}
+	args := []interface{}{domain.TransferTransferred, domain.TransferExpired, domain.TransferWithdrawn}
+
+	if filter.Status != "" {
+		where = []string{"tl.status = ?"}
+		args = []interface{}{filter.Status}
+	}
+
+	if filter.TeamId > 0 {
+		where = append(where, "(tl.transferred_from = ? OR tl.transferred_to = ?)")
+		args = append(args, filter.TeamId, filter.TeamId)
+	}
+
+	if filter.PlayerId > 0 {
+		where = append(where, "tl.player_id = ?")
+		args = append(args, filter.PlayerId)
+	}
+
+	if filter.From != nil {
+		where = append(where, historyTime+" >= ?")
+		args = append(args, *filter.From)
+	}
+
+	if filter.To != nil {
+		where = append(where, historyTime+" < ?")
+		args = append(args, *filter.To)
+	}
+
+	query := "SELECT tl.id, COALESCE(tl.player_id, 0), COALESCE(p.first_name, ''), COALESCE(p.last_name, ''), tl.transferred_from, COALESCE(tf.name, ''), " +
+		"tl.transferred_to, COALESCE(tt.name, ''), tl.asked_price, tl.market_value, tl.value_after, tl.transferred_at, tl.deadline, tl.withdrawn_at, tl.status " +
+		"FROM transfer_list tl " +
+		"LEFT JOIN player p ON p.id = tl.player_id " +
+		"LEFT JOIN team tf ON tf.id = tl.transferred_from " +
+		"LEFT JOIN team tt ON tt.id = tl.transferred_to " +
+		"WHERE " + strings.Join(where, " AND ") + " " +
+		"ORDER BY " + historyTime + " DESC, tl.id DESC"
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := tr.db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var transfer domain.CompletedTransfer
+		var firstName, lastName string
+		var valueAfter sql.NullInt64
+		var transferredAt, deadline, withdrawnAt sql.NullTime
+
+		err = rows.Scan(&transfer.Id, &transfer.PlayerId, &firstName, &lastName, &transfer.FromTeamId, &transfer.FromTeamName,
+			&transfer.ToTeamId, &transfer.ToTeamName, &transfer.Price, &transfer.ValueBefore, &valueAfter, &transferredAt, &deadline, &withdrawnAt, &transfer.Status)
+
+		if err != nil {
+			return nil, err
+		}
+
+		transfer.PlayerName = strings.TrimSpace(firstName + " " + lastName)
+
+		if valueAfter.Valid {
+			value := int(valueAfter.Int64)
+			transfer.ValueAfter = &value
+		}
+
+		if transferredAt.Valid {
+			transfer.TransferredAt = &transferredAt.Time
+		}
+
+		if transfer.Status == domain.TransferExpired && deadline.Valid {
+			transfer.ExpiredAt = &deadline.Time
+		}
+
+		if transfer.Status == domain.TransferWithdrawn && withdrawnAt.Valid {
+			transfer.WithdrawnAt = &withdrawnAt.Time
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers, rows.Err()
+}
+
+func (tr *TransferRepository) UpdateTransfer(accountId int, transfer *domain.Transfer) error {
+	_, err :=
+		tr.db.Exec("UPDATE transfer_list tl "+
+			"JOIN player p ON tl.player_id = p.id "+
+			"JOIN team t ON p.team_id = t.id "+
+			"SET tl.asked_price = ? WHERE tl.id = ? AND tl.status = ? AND tl.mode = ? AND t.account_id = ?",
+			transfer.AskedPrice, transfer.Id, domain.TransferListed, domain.TransferFixedPrice, accountId)
+
+	return err
+}
+
+func (tr *TransferRepository) WithdrawTransfer(transferId, sellerId int, now time.Time) error {
+	res, err := tr.db.Exec("UPDATE transfer_list SET status = ?, withdrawn_at = ?, transferred_from = ? "+
+		"WHERE id = ? AND status = ? AND bid_count = 0 AND player_id IN (SELECT id FROM player WHERE team_id = ?)",
+		domain.TransferWithdrawn, now, sellerId, transferId, domain.TransferListed, sellerId)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return errors.New("transfer is no longer available")
+	}
+
+	return nil
+}
+
+func (tr *TransferRepository) NewAuction(playerId, reservePrice, marketValue int, auction domain.Auction) (transferId int, err error) {
+	res, err := tr.db.Exec(
+		"INSERT INTO transfer_list(player_id, asked_price, market_value, transferred, status, mode, deadline, sealed) VALUES(?, ?, ?, ?, ?, ?, ?, ?)",
+		playerId, reservePrice, marketValue, false, domain.TransferListed, domain.TransferAuction, auction.Deadline, auction.Sealed)
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, _ := res.LastInsertId()
+	return int(id), nil
+}
+
+func (tr *TransferRepository) PlaceBid(transferId, teamId, amount int, terms domain.ContractTerms, now time.Time) (domain.Bid, error) {
+	tx, err := tr.db.Begin()
+
+	if err != nil {
+		return domain.Bid{}, err
+	}
+
+	res, err := tx.Exec("UPDATE transfer_list SET bid_count = bid_count + 1, "+
+		"highest_bid = CASE WHEN highest_bid IS NULL OR highest_bid < ? THEN ? ELSE highest_bid END "+
+		"WHERE id = ? AND status = ? AND mode = ? AND deadline > ? AND asked_price <= ? "+
+		"AND (sealed = 1 OR highest_bid IS NULL OR highest_bid < ?) "+
+		"AND player_id NOT IN (SELECT id FROM player WHERE team_id = ?)",
+		amount, amount, transferId, domain.TransferListed, domain.TransferAuction, now, amount, amount, teamId)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return domain.Bid{}, err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return domain.Bid{}, errors.New("bid not accepted")
+	}
+
+	bid := domain.Bid{TransferId: transferId, TeamId: teamId, Amount: amount, Status: domain.BidActive, PlacedAt: now, Contract: &terms}
+	var previous int
+	err = tx.QueryRow("SELECT id, amount FROM transfer_bid WHERE transfer_id = ? AND team_id = ? AND status = ?",
+		transferId, teamId, domain.BidActive).Scan(&bid.Id, &previous)
+
+	if err != nil && err != sql.ErrNoRows {
+		_ = tx.Rollback()
+		return domain.Bid{}, err
+	}
+
+	if amount <= previous {
+		_ = tx.Rollback()
+		return domain.Bid{}, errors.New("bid must raise your previous bid")
+	}
+
+	res, err = tx.Exec("UPDATE team SET available_cash = available_cash - ? WHERE id = ? AND available_cash >= ?",
+		amount-previous, teamId, amount-previous)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return domain.Bid{}, err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		_ = tx.Rollback()
+		return domain.Bid{}, errors.New("insufficient funds")
+	}
+
+	reserved := ledgerStatement(domain.LedgerBidReserved, domain.ReferenceTransfer, transferId, teamAccount(teamId), escrowAccount(teamId), amount-previous, now)
+
+	if _, err = tx.Exec(reserved.query, reserved.args...); err != nil {
+		_ = tx.Rollback()
+		return domain.Bid{}, err
+	}
+
+	if bid.Id == 0 {
+		res, err = tx.Exec("INSERT INTO transfer_bid(transfer_id, team_id, amount, status, placed_at, contract_wage, contract_years, contract_release_clause) "+
+			"VALUES(?, ?, ?, ?, ?, ?, ?, ?)", transferId, teamId, amount, domain.BidActive, now, terms.Wage, terms.Years, terms.ReleaseClause)
+
+		if err == nil {
+			id, _ := res.LastInsertId()
+			bid.Id = int(id)
+		}
+	} else {
+		_, err = tx.Exec("UPDATE transfer_bid SET amount = ?, placed_at = ?, contract_wage = ?, contract_years = ?, contract_release_clause = ? WHERE id = ?",
+			amount, now, terms.Wage, terms.Years, terms.ReleaseClause, bid.Id)
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return domain.Bid{}, err
+	}
+
+	return bid, tx.Commit()
+}
+
+func (tr *TransferRepository) FindBids(transferId int) (bids []domain.Bid, err error) {
+	rows, err := tr.db.Query("SELECT b.id, b.transfer_id, b.team_id, t.name, b.amount, b.status, b.placed_at, "+
+		"b.contract_wage, b.contract_years, b.contract_release_clause "+
+		"FROM transfer_bid b "+
+		"JOIN team t ON t.id = b.team_id "+
+		"WHERE b.transfer_id = ? "+
+		"ORDER BY b.amount DESC, b.placed_at, b.id", transferId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var bid domain.Bid
+		var wage, years sql.NullInt64
+		var releaseClause *int
+
+		err = rows.Scan(&bid.Id, &bid.TransferId, &bid.TeamId, &bid.TeamName, &bid.Amount, &bid.Status, &bid.PlacedAt, &wage, &years, &releaseClause)
+
+		if err != nil {
+			return nil, err
+		}
+
+		bid.Contract = contractTerms(wage, years, releaseClause)
+
+		bids = append(bids, bid)
+	}
+
+	return bids, rows.Err()
+}
+
+func (tr *TransferRepository) FindDueAuctionIds(now time.Time) (ids []int, err error) {
+	rows, err := tr.db.Query("SELECT id FROM transfer_list WHERE mode = ? AND status = ? AND deadline <= ? ORDER BY deadline, id",
+		domain.TransferAuction, domain.TransferListed, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (tr *TransferRepository) SettleAuction(transferId, bidId, newMarketValue int, contract *domain.Contract, now time.Time) error {
+	tx, err := tr.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = settleAuction(transferId, bidId, newMarketValue, contract, now, tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func settleAuction(transferId, bidId, newMarketValue int, contract *domain.Contract, now time.Time, tx *sql.Tx) error {
+	c := completion{transferId: transferId, newMarketValue: newMarketValue, now: now}
+	err := tx.QueryRow("SELECT tl.player_id, p.team_id "+
+		"FROM transfer_list tl "+
+		"JOIN player p ON p.id = tl.player_id "+
+		"WHERE tl.id = ? AND tl.status = ? AND tl.mode = ? AND tl.deadline <= ?",
+		transferId, domain.TransferListed, domain.TransferAuction, now).Scan(&c.playerId, &c.sellerId)
+
+	if err != nil {
+		return errors.New("auction not settled")
+	}
+
+	if bidId == 0 {
+		err = execAll([]statement{{"UPDATE transfer_list SET status = ?, transferred_from = ? WHERE id = ? AND status = ?",
+			[]interface{}{domain.TransferExpired, c.sellerId, transferId, domain.TransferListed}}}, errors.New("auction not settled"), tx)
+	} else {
+		err = tx.QueryRow("SELECT team_id, amount FROM transfer_bid WHERE id = ? AND transfer_id = ? AND status = ?",
+			bidId, transferId, domain.BidActive).Scan(&c.buyerId, &c.price)
+
+		if err != nil {
+			return errors.New("auction not settled")
+		}
+
+		if err = completeTransfer(c, tx); err != nil {
+			return err
+		}
+
+		contract.PlayerId = c.playerId
+
+		if err = replaceContract(contract, now, tx); err != nil {
+			return err
+		}
+
+		err = execAll([]statement{{"UPDATE transfer_bid SET status = ? WHERE id = ? AND status = ?",
+			[]interface{}{domain.BidWon, bidId, domain.BidActive}}}, errors.New("auction not settled"), tx)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return refundBids(transferId, now, tx)
+}
+
+func refundBids(transferId int, now time.Time, tx *sql.Tx) error {
+	return releaseBids("transfer_id", transferId, now, tx)
+}
+
+func releaseBids(column string, id int, now time.Time, tx *sql.Tx) error {
+	_, err := tx.Exec(ledgerInsert+"SELECT ?, ?, b.transfer_id, ?, b.team_id, ?, b.team_id, b.amount, ? "+
+		"FROM transfer_bid b WHERE b."+column+" = ? AND b.status = ? ORDER BY b.id",
+		domain.LedgerBidReleased, domain.ReferenceTransfer, domain.LedgerEscrow, domain.LedgerTeam, now, id, domain.BidActive)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE team SET available_cash = available_cash + "+
+		"(SELECT COALESCE(SUM(b.amount), 0) FROM transfer_bid b WHERE b."+column+" = ? AND b.team_id = team.id AND b.status = ?) "+
+		"WHERE id IN (SELECT team_id FROM transfer_bid WHERE "+column+" = ? AND status = ?)",
+		id, domain.BidActive, id, domain.BidActive)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE transfer_bid SET status = ? WHERE "+column+" = ? AND status = ?",
+		domain.BidRefunded, id, domain.BidActive)
+
+	return err
+}
+
+func (tr *TransferRepository) IsPlayerListed(playerId int) (bool, error) {
+	var count int
+	err := tr.db.QueryRow("SELECT COUNT(*) FROM transfer_list WHERE player_id = ? AND status = ?", playerId, domain.TransferListed).Scan(&count)
+	return count > 0, err
+}
+
+func (tr *TransferRepository) ExpireListings(now time.Time) (int, error) {
+	return tr.expireListings(now, "SELECT id FROM transfer_list WHERE status = ? ORDER BY id", domain.TransferListed)
+}
+
+func (tr *TransferRepository) ExpireDueListings(now time.Time) (int, error) {
+	return tr.expireListings(now, "SELECT id FROM transfer_list WHERE mode = ? AND status = ? AND deadline <= ? ORDER BY deadline, id",
+		domain.TransferFixedPrice, domain.TransferListed, now)
+}
+
+func (tr *TransferRepository) expireListings(now time.Time, query string, args ...interface{}) (int, error) {
+	tx, err := tr.db.Begin()
+
+	if err != nil {
+		return 0, err
+	}
+
+	expired, err := expireListings(now, tx, query, args...)
+
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	return expired, tx.Commit()
+}
+
+func expireListings(now time.Time, tx *sql.Tx, query string, args ...interface{}) (int, error) {
+	ids, err := queryIds(tx, query, args...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		_, err = tx.Exec("UPDATE transfer_list SET status = ?, "+
+			"transferred_from = (SELECT team_id FROM player WHERE player.id = transfer_list.player_id), "+
+			"deadline = CASE WHEN deadline IS NULL OR deadline > ? THEN ? ELSE deadline END "+
+			"WHERE id = ? AND status = ?", domain.TransferExpired, now, now, id, domain.TransferListed)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if err = refundBids(id, now, tx); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(ids), nil
+}
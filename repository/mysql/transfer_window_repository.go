@@ -0,0 +1,119 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+type TransferWindowRepository struct {
+	db *sql.DB
+}
+
+func NewTransferWindowRepository(db *sql.DB) *TransferWindowRepository {
+	return &TransferWindowRepository{
+		db: db,
+	}
+}
+
+const windowQuery = "SELECT w.id, w.name, w.opens_at, w.closes_at, w.league_id, COALESCE(l.current_round, 0), COALESCE(l.status, ''), " +
+	"w.opens_after_round, w.closes_after_round, w.listing_policy, w.manual_state, w.closed_at, w.created_at " +
+	"FROM transfer_window w " +
+	"LEFT JOIN league l ON l.id = w.league_id "
+
+func (wr *TransferWindowRepository) CreateWindow(window *domain.TransferWindow) error {
+	res, err := wr.db.Exec("INSERT INTO transfer_window(name, opens_at, closes_at, league_id, opens_after_round, closes_after_round, listing_policy, created_at) "+
+		"VALUES(?, ?, ?, ?, ?, ?, ?, ?)",
+		window.Name, window.OpensAt, window.ClosesAt, window.LeagueId, window.OpensAfterRound, window.ClosesAfterRound, window.ListingPolicy, window.CreatedAt)
+
+	if err != nil {
+		return err
+	}
+
+	id, _ := res.LastInsertId()
+	window.Id = int(id)
+	return nil
+}
+
+func (wr *TransferWindowRepository) GetWindow(id int) (domain.TransferWindow, error) {
+	windows, err := wr.getWindows(windowQuery+"WHERE w.id = ?", id)
+
+	if err != nil {
+		return domain.TransferWindow{}, err
+	}
+
+	if len(windows) == 0 {
+		return domain.TransferWindow{}, sql.ErrNoRows
+	}
+
+	return windows[0], nil
+}
+
+func (wr *TransferWindowRepository) FindWindows() ([]domain.TransferWindow, error) {
+	return wr.getWindows(windowQuery + "ORDER BY w.id")
+}
+
+func (wr *TransferWindowRepository) getWindows(query string, args ...interface{}) (windows []domain.TransferWindow, err error) {
+	rows, err := wr.db.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var w domain.TransferWindow
+
+		err = rows.Scan(&w.Id, &w.Name, &w.OpensAt, &w.ClosesAt, &w.LeagueId, &w.LeagueRound, &w.LeagueStatus,
+			&w.OpensAfterRound, &w.ClosesAfterRound, &w.ListingPolicy, &w.ManualState, &w.ClosedAt, &w.CreatedAt)
+
+		if err != nil {
+			return nil, err
+		}
+
+		windows = append(windows, w)
+	}
+
+	return windows, rows.Err()
+}
+
+func (wr *TransferWindowRepository) UpdateManualState(windowId int, state *string) error {
+	query := "UPDATE transfer_window SET manual_state = ? WHERE id = ?"
+
+	if state != nil && *state == domain.WindowOpen {
+		query = "UPDATE transfer_window SET manual_state = ?, closed_at = NULL WHERE id = ?"
+	}
+
+	_, err := wr.db.Exec(query, state, windowId)
+	return err
+}
+
+func (wr *TransferWindowRepository) MarkWindowClosed(windowId int, now time.Time) error {
+	res, err := wr.db.Exec("UPDATE transfer_window SET closed_at = ? WHERE id = ? AND closed_at IS NULL", now, windowId)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return errors.New("transfer window is already closed")
+	}
+
+	return nil
+}
+
+func (wr *TransferWindowRepository) DeleteWindow(windowId int) error {
+	res, err := wr.db.Exec("DELETE FROM transfer_window WHERE id = ?", windowId)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected, _ := res.RowsAffected(); rowsAffected != 1 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
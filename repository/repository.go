@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"time"
+)
+
+var ErrTeamInRunningLeague = errors.New("team plays in a running league")
+
+type Repositories struct {
+	Players       PlayerRepository
+	Teams         TeamRepository
+	Accounts      AccountRepository
+	Transfers     TransferRepository
+	AccountTokens AccountTokenRepository
+	Sessions      SessionRepository
+	Matches       MatchRepository
+	Leagues       LeagueRepository
+	Offers        OfferRepository
+	Loans         LoanRepository
+	Swaps         SwapRepository
+	Windows       TransferWindowRepository
+	Ledger        LedgerRepository
+	Contracts     ContractRepository
+}
+
+type PlayerRepository interface {
+	GetPlayer(id int) (domain.Player, error)
+	GetPlayerOutOfTransferList(accountId, playerId int) (domain.Player, error)
+	FindPlayers() ([]domain.Player, error)
+	GetPlayersByTeamId(teamId int) ([]domain.Player, error)
+	FindFreeAgents(filter domain.FreeAgentFilter) ([]domain.Player, error)
+	NewPlayer(player *domain.Player) error
+	UpdatePlayer(accountId int, player *domain.Player) error
+	UpdatePlayerById(player *domain.Player) error
+	DeletePlayer(id int, now time.Time) error
+}
+
+type TeamRepository interface {
+	GetTeamById(id int) (*domain.Team, error)
+	GetTeamByAccountId(id int) (*domain.Team, error)
+	FindTeams() ([]domain.Team, error)
+	GetSquadSummary(teamId int) (domain.SquadSummary, error)
+	NewTeam(team *domain.Team, now time.Time) error
+	UpdateTeam(accountId int, team *domain.Team) error
+	UpdateTeamById(team *domain.Team, now time.Time) error
+	DeleteTeam(id int, now time.Time) error
+}
+
+type AccountRepository interface {
+	CreateAccount(account *domain.Account, now time.Time) error
+	UpdateAccount(account *domain.Account) error
+	DeleteAccount(id int, now time.Time) error
+	GetAccountById(id int) (domain.Account, error)
+	GetAccountByUsername(username string) (domain.Account, error)
+	FindAccounts() ([]domain.Account, error)
+	VerifyAccount(token string) (bool, error)
+	RegisterFailedLoginAttempt(username string, event *domain.AccountLockEvent) error
+	ResetLoginAttempts(username string) error
+	ResetPassword(token, password string, event *domain.AccountLockEvent, now time.Time) (int, error)
+	SetLocked(accountId int, locked bool, event *domain.AccountLockEvent) error
+	UnlockFailedLogins(accountId int, event *domain.AccountLockEvent) error
+	FindLockEvents(accountId int) ([]domain.AccountLockEvent, error)
+}
+
+type TransferRepository interface {
+	NewTransfer(playerId, askedPrice, marketValue int, expiresAt *time.Time) (int, error)
+	FindTransfers(filter domain.TransferFilter) ([]domain.Transfer, int, error)
+	GetTransfer(id int) (domain.Transfer, error)
+	ConfirmTransfer(transferId int, buyerId int, newMarketValue int, contract *domain.Contract, now time.Time) error
+	FindCompletedTransfers(filter domain.TransferHistoryFilter) ([]domain.CompletedTransfer, error)
+	UpdateTransfer(accountId int, transfer *domain.Transfer) error
+	WithdrawTransfer(transferId, sellerId int, now time.Time) error
+	NewAuction(playerId, reservePrice, marketValue int, auction domain.Auction) (int, error)
+	PlaceBid(transferId, teamId, amount int, terms domain.ContractTerms, now time.Time) (domain.Bid, error)
+	FindBids(transferId int) ([]domain.Bid, error)
+	FindDueAuctionIds(now time.Time) ([]int, error)
+	SettleAuction(transferId, bidId, newMarketValue int, contract *domain.Contract, now time.Time) error
+	IsPlayerListed(playerId int) (bool, error)
+	ExpireListings(now time.Time) (int, error)
+	ExpireDueListings(now time.Time) (int, error)
+}
+
+type OfferRepository interface {
+	CreateOffer(offer *domain.Offer) error
+	GetOffer(id int) (domain.Offer, error)
+	FindOffers(teamId int, status string) ([]domain.Offer, error)
+	CounterOffer(offerId, teamId, amount int, expiresAt, now time.Time) error
+	CloseOffer(offerId, teamId int, status string, now time.Time) error
+	AcceptOffer(offerId, teamId, newMarketValue int, contract *domain.Contract, now time.Time) error
+}
+
+type LoanRepository interface {
+	CreateLoan(loan *domain.Loan) error
+	GetLoan(id int) (domain.Loan, error)
+	FindLoans(teamId int, status string) ([]domain.Loan, error)
+	StartLoan(loanId, lenderId int, now time.Time) error
+	CloseLoan(loanId, teamId int, status string, now time.Time) error
+	ReturnLoan(loanId int, now time.Time) error
+	BuyLoanedPlayer(loanId, borrowerId, newMarketValue int, contract *domain.Contract, now time.Time) error
+	FindDueLoanIds(now time.Time) ([]int, error)
+}
+
+type SwapRepository interface {
+	CreateSwap(swap *domain.Swap) error
+	GetSwap(id int) (domain.Swap, error)
+	FindSwaps(teamId int, status string) ([]domain.Swap, error)
+	CloseSwap(swapId, teamId int, status string, now time.Time) error
+	CompleteSwap(swapId, receiverId int, newMarketValues map[int]int, contracts map[int]*domain.Contract, now time.Time) error
+}
+
+type TransferWindowRepository interface {
+	CreateWindow(window *domain.TransferWindow) error
+	GetWindow(id int) (domain.TransferWindow, error)
+	FindWindows() ([]domain.TransferWindow, error)
+	UpdateManualState(windowId int, state *string) error
+	MarkWindowClosed(windowId int, now time.Time) error
+	DeleteWindow(windowId int) error
+}
+
+type ContractRepository interface {
+	GetContract(playerId int) (domain.Contract, error)
+	FindTeamContracts(teamId int) ([]domain.Contract, error)
+	RenewContract(teamId int, contract *domain.Contract, now time.Time) error
+	FindDueWages(now time.Time) ([]domain.Contract, error)
+	PayWage(contract domain.Contract, nextWageAt, now time.Time) error
+	FindExpiredContractIds(now time.Time) ([]int, error)
+	ExpireContract(contractId int, now time.Time) error
+	SignFreeAgent(teamId int, contract *domain.Contract, fee int, now time.Time) error
+	PayReleaseClause(buyerId, releaseClause, newMarketValue int, contract *domain.Contract, now time.Time) error
+	ReleasePlayer(teamId, playerId int, now time.Time) error
+}
+
+type LedgerRepository interface {
+	FindTeamTransactions(teamId int) ([]domain.LedgerTransaction, error)
+	FindBalances() ([]domain.LedgerBalance, error)
+}
+
+type AccountTokenRepository interface {
+	CreateToken(token *domain.AccountToken) error
+	UseToken(purpose, token string, now time.Time) (int, error)
+	RevokeTokens(accountId int, purpose string, now time.Time) error
+}
+
+type SessionRepository interface {
+	CreateRefreshToken(refreshToken *domain.RefreshToken) error
+	UseRefreshToken(token string, now time.Time) (domain.RefreshToken, bool, error)
+	RevokeFamily(family string, now time.Time) error
+	RevokeAccountSessions(accountId int, now time.Time) error
+	RevokeSession(accessTokenId string, accountId int, accessExpiresAt, now time.Time) error
+	IsAccessTokenRevoked(accessTokenId string) (bool, error)
+}
+
+type MatchRepository interface {
+	CreateMatch(match *domain.Match) error
+	GetMatch(id int) (domain.Match, error)
+	FindMatchesByTeamId(teamId int) ([]domain.Match, error)
+}
+
+type LeagueRepository interface {
+	CreateLeague(league *domain.League) error
+	GetLeague(id int) (domain.League, error)
+	FindLeagues() ([]domain.League, error)
+	EnrolTeam(leagueId, teamId int, now time.Time) error
+	FindLeagueTeamIds(leagueId int) ([]int, error)
+	StartLeague(leagueId, rounds int, fixtures []domain.Fixture) error
+	FindFixtures(leagueId, round int) ([]domain.Fixture, error)
+	RecordRound(leagueId, round int, fixtures []domain.Fixture, finished bool) error
+	FindStandings(leagueId int) ([]domain.Standing, error)
+}
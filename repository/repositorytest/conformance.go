@@ -0,0 +1,2432 @@
+package repositorytest
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/league"
+	"github.com/giancarlobastos/soccer-manager-api/match"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Factory func(t *testing.T) repository.Repositories
+
+func Run(t *testing.T, newRepositories Factory) {
+	tests := map[string]func(*testing.T, repository.Repositories){
+		"Accounts":            testAccounts,
+		"FailedLoginAttempts": testFailedLoginAttempts,
+		"UnlockFailedLogins":  testUnlockFailedLogins,
+		"ResetPassword":       testResetPassword,
+		"DeleteAccount":       testDeleteAccount,
+		"TransferListing":     testTransferListing,
+		"SquadSummary":        testSquadSummary,
+		"FindTransfers":       testFindTransfers,
+		"ConfirmTransfer":     testConfirmTransfer,
+		"WithdrawTransfer":    testWithdrawTransfer,
+		"TransferHistory":     testTransferHistory,
+		"Auctions":            testAuctions,
+		"SealedAuctions":      testSealedAuctions,
+		"Offers":              testOffers,
+		"OfferExpiry":         testOfferExpiry,
+		"Loans":               testLoans,
+		"LoanReturns":         testLoanReturns,
+		"Swaps":               testSwaps,
+		"TransferWindows":     testTransferWindows,
+		"ExpireListings":      testExpireListings,
+		"ListingExpiry":       testListingExpiry,
+		"Ledger":              testLedger,
+		"NegativeOpening":     testNegativeOpeningBalance,
+		"Contracts":           testContracts,
+		"ReleaseClause":       testReleaseClause,
+		"ReleasePlayer":       testReleasePlayer,
+		"FreeAgents":          testFreeAgents,
+		"AccountTokens":       testAccountTokens,
+		"Sessions":            testSessions,
+		"Matches":             testMatches,
+		"Leagues":             testLeagues,
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			test(t, newRepositories(t))
+		})
+	}
+}
+
+func newContract() *domain.Contract {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &domain.Contract{Wage: 100, StartsAt: now, EndsAt: now.AddDate(1, 0, 0), NextWageAt: now.AddDate(0, 0, 7)}
+}
+
+func newTerms() domain.ContractTerms {
+	return domain.ContractTerms{Wage: 100, Years: 1}
+}
+
+func swapContracts(values map[int]int) map[int]*domain.Contract {
+	contracts := make(map[int]*domain.Contract)
+
+	for playerId := range values {
+		contracts[playerId] = newContract()
+	}
+
+	return contracts
+}
+
+func createAccount(t *testing.T, repositories repository.Repositories, username string, cash int, positions ...domain.PlayerPosition) *domain.Account {
+	t.Helper()
+
+	team := &domain.Team{
+		Name:          username + " FC",
+		Country:       "Brazil",
+		AvailableCash: cash,
+	}
+
+	for i, position := range positions {
+		team.Players = append(team.Players, domain.Player{
+			FirstName:   fmt.Sprintf("%s%d", position, i),
+			LastName:    username,
+			Country:     "Brazil",
+			Age:         uint8(18 + i),
+			Position:    position,
+			MarketValue: 1000000 + i*100000,
+			Attributes:  domain.PlayerAttributes{Pace: 60 + i, Shooting: 55, Passing: 50, Defending: 45, Goalkeeping: 20, Stamina: 65, Potential: 70},
+		})
+	}
+
+	account := &domain.Account{
+		Username:          username + "@example.com",
+		Password:          "hash",
+		FirstName:         username,
+		LastName:          "Tester",
+		VerificationToken: username + "-token",
+		Profile:           domain.UserProfile,
+		Team:              team,
+	}
+
+	if err := repositories.Accounts.CreateAccount(account, time.Now()); err != nil {
+		t.Fatalf("create account %s: %v", username, err)
+	}
+
+	return account
+}
+
+func testAccounts(t *testing.T, repositories repository.Repositories) {
+	account := createAccount(t, repositories, "alice", 5000000, domain.GoalKeeper, domain.Defender)
+
+	if account.Id == 0 || account.Team.Id == 0 || account.Team.Players[1].Id == 0 {
+		t.Fatalf("expected ids to be assigned, got %+v", account)
+	}
+
+	byId, err := repositories.Accounts.GetAccountById(account.Id)
+
+	if err != nil || byId.Username != "alice@example.com" || byId.Profile != domain.UserProfile || byId.Confirmed {
+		t.Fatalf("unexpected account by id: %+v, %v", byId, err)
+	}
+
+	if _, err = repositories.Accounts.GetAccountByUsername("nobody@example.com"); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows for unknown username, got %v", err)
+	}
+
+	duplicate := &domain.Account{Username: "alice@example.com", Team: &domain.Team{Name: "Other"}}
+
+	if err = repositories.Accounts.CreateAccount(duplicate, time.Now()); err == nil {
+		t.Fatal("expected duplicate username to be rejected")
+	}
+
+	team, err := repositories.Teams.GetTeamByAccountId(account.Id)
+
+	if err != nil || team.Id != account.Team.Id || team.AvailableCash != 5000000 {
+		t.Fatalf("unexpected team: %+v, %v", team, err)
+	}
+
+	players, err := repositories.Players.GetPlayersByTeamId(team.Id)
+
+	if err != nil || len(players) != 2 {
+		t.Fatalf("expected 2 players, got %d: %v", len(players), err)
+	}
+
+	if players[1].Attributes != account.Team.Players[1].Attributes {
+		t.Fatalf("expected attributes %+v, got %+v", account.Team.Players[1].Attributes, players[1].Attributes)
+	}
+
+	players[1].Attributes.Shooting = 80
+
+	if err = repositories.Players.UpdatePlayerById(&players[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if updated, _ := repositories.Players.GetPlayer(players[1].Id); updated.Attributes != players[1].Attributes {
+		t.Fatalf("expected updated attributes %+v, got %+v", players[1].Attributes, updated.Attributes)
+	}
+
+	verified, err := repositories.Accounts.VerifyAccount("alice-token")
+
+	if err != nil || !verified {
+		t.Fatalf("expected account to be verified: %v", err)
+	}
+
+	byId, _ = repositories.Accounts.GetAccountById(account.Id)
+	byId.Profile = domain.AdminProfile
+	byId.FirstName = "Alicia"
+
+	if err = repositories.Accounts.UpdateAccount(&byId); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err := repositories.Accounts.FindAccounts()
+
+	if err != nil || len(accounts) != 1 {
+		t.Fatalf("expected 1 account, got %d: %v", len(accounts), err)
+	}
+
+	if found := accounts[0]; found.FirstName != "Alicia" || found.Profile != domain.AdminProfile || !found.Confirmed ||
+		found.Team == nil || found.Team.Id != team.Id {
+		t.Fatalf("unexpected account listing: %+v", found)
+	}
+}
+
+func testFailedLoginAttempts(t *testing.T, repositories repository.Repositories) {
+	account := createAccount(t, repositories, "bob", 0)
+
+	for i := 0; i < 2; i++ {
+		if err := repositories.Accounts.RegisterFailedLoginAttempt(account.Username, failedLoginEvent()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stored, _ := repositories.Accounts.GetAccountById(account.Id)
+
+	if stored.Locked || stored.LoginAttempts != 2 {
+		t.Fatalf("expected 2 attempts and unlocked account, got %+v", stored)
+	}
+
+	for i := 0; i < 2; i++ {
+		_ = repositories.Accounts.RegisterFailedLoginAttempt(account.Username, failedLoginEvent())
+	}
+
+	stored, _ = repositories.Accounts.GetAccountById(account.Id)
+
+	if !stored.Locked || stored.LoginAttempts != 3 {
+		t.Fatalf("expected account to be locked after the third failed attempt, got %+v", stored)
+	}
+
+	events, err := repositories.Accounts.FindLockEvents(account.Id)
+
+	if err != nil || len(events) != 1 || events[0].AccountId != account.Id || events[0].Source != domain.FailedLoginsLockSource {
+		t.Fatalf("expected a single failed logins lock event, got %+v: %v", events, err)
+	}
+
+	event := &domain.AccountLockEvent{
+		AccountId:      account.Id,
+		ActorAccountId: &account.Id,
+		Action:         domain.UnlockAction,
+		Reason:         "support ticket",
+		Source:         domain.AdminLockSource,
+		CreatedAt:      time.Now(),
+	}
+
+	if err := repositories.Accounts.SetLocked(account.Id, false, event); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ = repositories.Accounts.GetAccountById(account.Id)
+
+	if stored.Locked || stored.LoginAttempts != 0 {
+		t.Fatalf("expected account to be unlocked with attempts reset, got %+v", stored)
+	}
+
+	if err := repositories.Accounts.SetLocked(account.Id+100, true, event); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows for unknown account, got %v", err)
+	}
+
+	events, err = repositories.Accounts.FindLockEvents(account.Id)
+
+	if err != nil || len(events) != 2 || events[1].Reason != "support ticket" || *events[1].ActorAccountId != account.Id {
+		t.Fatalf("unexpected lock events: %+v, %v", events, err)
+	}
+}
+
+func testUnlockFailedLogins(t *testing.T, repositories repository.Repositories) {
+	account := createAccount(t, repositories, "erin", 0)
+	unlock := &domain.AccountLockEvent{
+		AccountId: account.Id,
+		Action:    domain.UnlockAction,
+		Source:    domain.SelfServiceLockSource,
+		CreatedAt: time.Now(),
+	}
+
+	if err := repositories.Accounts.UnlockFailedLogins(account.Id, unlock); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows for an unlocked account, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = repositories.Accounts.RegisterFailedLoginAttempt(account.Username, failedLoginEvent())
+	}
+
+	if err := repositories.Accounts.UnlockFailedLogins(account.Id, unlock); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, _ := repositories.Accounts.GetAccountById(account.Id); stored.Locked || stored.LoginAttempts != 0 {
+		t.Fatalf("expected account to be unlocked with attempts reset, got %+v", stored)
+	}
+
+	err := repositories.Accounts.SetLocked(account.Id, true, &domain.AccountLockEvent{
+		AccountId: account.Id,
+		Action:    domain.LockAction,
+		Source:    domain.AdminLockSource,
+		CreatedAt: time.Now(),
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Accounts.UnlockFailedLogins(account.Id, unlock); err != sql.ErrNoRows {
+		t.Fatalf("expected an admin lock to be kept, got %v", err)
+	}
+
+	if stored, _ := repositories.Accounts.GetAccountById(account.Id); !stored.Locked {
+		t.Fatal("expected account to stay locked")
+	}
+}
+
+func failedLoginEvent() *domain.AccountLockEvent {
+	return &domain.AccountLockEvent{
+		Action:    domain.LockAction,
+		Reason:    "too many failed login attempts",
+		Source:    domain.FailedLoginsLockSource,
+		CreatedAt: time.Now(),
+	}
+}
+
+func testResetPassword(t *testing.T, repositories repository.Repositories) {
+	carol := createAccount(t, repositories, "carol", 0)
+	frank := createAccount(t, repositories, "frank", 0)
+	now := time.Now()
+	tokens := []*domain.AccountToken{
+		{AccountId: carol.Id, Purpose: domain.PasswordResetPurpose, Token: "carol-1", ExpiresAt: now.Add(time.Hour)},
+		{AccountId: carol.Id, Purpose: domain.PasswordResetPurpose, Token: "carol-2", ExpiresAt: now.Add(time.Hour)},
+		{AccountId: frank.Id, Purpose: domain.PasswordResetPurpose, Token: "frank", ExpiresAt: now.Add(time.Hour)},
+	}
+
+	for _, token := range tokens {
+		if err := repositories.AccountTokens.CreateToken(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = repositories.Accounts.RegisterFailedLoginAttempt(carol.Username, failedLoginEvent())
+	}
+
+	unlock := func() *domain.AccountLockEvent {
+		return &domain.AccountLockEvent{Action: domain.UnlockAction, Source: domain.PasswordResetLockSource, CreatedAt: now}
+	}
+
+	if _, err := repositories.Accounts.ResetPassword("unknown", "new-hash", unlock(), now); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows for an unknown token, got %v", err)
+	}
+
+	if accountId, err := repositories.Accounts.ResetPassword("carol-1", "new-hash", unlock(), now); err != nil || accountId != carol.Id {
+		t.Fatalf("expected password of %d to be reset, got %d: %v", carol.Id, accountId, err)
+	}
+
+	stored, _ := repositories.Accounts.GetAccountById(carol.Id)
+
+	if stored.Password != "new-hash" || stored.Locked || stored.LoginAttempts != 0 || stored.TokenVersion != 1 {
+		t.Fatalf("unexpected account after password reset: %+v", stored)
+	}
+
+	if events, _ := repositories.Accounts.FindLockEvents(carol.Id); len(events) != 2 || events[1].Source != domain.PasswordResetLockSource {
+		t.Fatalf("expected the failed logins lock to be lifted by the reset, got %+v", events)
+	}
+
+	if _, err := repositories.Accounts.ResetPassword("carol-2", "other-hash", unlock(), now); err != sql.ErrNoRows {
+		t.Fatalf("expected the other reset tokens to be revoked, got %v", err)
+	}
+
+	err := repositories.Accounts.SetLocked(frank.Id, true, &domain.AccountLockEvent{
+		AccountId: frank.Id,
+		Action:    domain.LockAction,
+		Source:    domain.AdminLockSource,
+		CreatedAt: now,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repositories.Accounts.ResetPassword("frank", "new-hash", unlock(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, _ = repositories.Accounts.GetAccountById(frank.Id); stored.Password != "new-hash" || !stored.Locked {
+		t.Fatalf("expected an admin lock to survive the password reset, got %+v", stored)
+	}
+
+	if events, _ := repositories.Accounts.FindLockEvents(frank.Id); len(events) != 1 {
+		t.Fatalf("expected no unlock event for an admin lock, got %+v", events)
+	}
+}
+
+func testDeleteAccount(t *testing.T, repositories repository.Repositories) {
+	account := createAccount(t, repositories, "dave", 0, domain.Forward, domain.Forward)
+
+	if _, err := repositories.Transfers.NewTransfer(account.Team.Players[0].Id, 10, 10, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repositories.Accounts.DeleteAccount(account.Id, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repositories.Accounts.GetAccountById(account.Id); err != sql.ErrNoRows {
+		t.Fatalf("expected account to be deleted, got %v", err)
+	}
+
+	if _, err := repositories.Teams.GetTeamById(account.Team.Id); err != sql.ErrNoRows {
+		t.Fatalf("expected team to be deleted, got %v", err)
+	}
+
+	player, err := repositories.Players.GetPlayer(account.Team.Players[0].Id)
+
+	if err != nil || player.TeamId != nil {
+		t.Fatalf("expected player to be detached from the deleted team: %+v, %v", player, err)
+	}
+
+	if listed, _ := repositories.Transfers.IsPlayerListed(player.Id); listed {
+		t.Fatal("expected listing of the deleted team to be removed")
+	}
+
+	if err = repositories.Accounts.DeleteAccount(account.Id, time.Now()); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows when deleting twice, got %v", err)
+	}
+}
+
+func testSquadSummary(t *testing.T, repositories repository.Repositories) {
+	account := createAccount(t, repositories, "gina", 0, domain.GoalKeeper, domain.Defender, domain.Defender, domain.Forward)
+	players := account.Team.Players
+	createAccount(t, repositories, "hugo", 0, domain.Midfielder)
+
+	if _, err := repositories.Transfers.NewTransfer(players[3].Id, 750000, players[3].MarketValue, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := repositories.Teams.GetSquadSummary(account.Team.Id)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := domain.SquadSummary{
+		Value:             1000000 + 1100000 + 1200000 + 1300000,
+		Players:           4,
+		AverageAge:        19.5,
+		Positions:         map[domain.PlayerPosition]int{domain.GoalKeeper: 1, domain.Defender: 2, domain.Midfielder: 0, domain.Forward: 1},
+		ListedPlayers:     1,
+		ListedAskingPrice: 750000,
+	}
+
+	if !reflect.DeepEqual(summary, expected) {
+		t.Fatalf("expected summary %+v, got %+v", expected, summary)
+	}
+
+	empty, err := repositories.Teams.GetSquadSummary(createAccount(t, repositories, "iris", 0).Team.Id)
+
+	if err != nil || empty.Players != 0 || empty.Value != 0 || empty.AverageAge != 0 || empty.Positions[domain.Forward] != 0 {
+		t.Fatalf("unexpected summary for an empty squad: %+v: %v", empty, err)
+	}
+}
+
+func testTransferListing(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "erin", 0, domain.Midfielder)
+	other := createAccount(t, repositories, "frank", 0)
+	player := seller.Team.Players[0]
+
+	if _, err := repositories.Players.GetPlayerOutOfTransferList(other.Id, player.Id); err == nil {
+		t.Fatal("expected player owned by another account to be rejected")
+	}
+
+	if _, err := repositories.Players.GetPlayerOutOfTransferList(seller.Id, player.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	transferId, err := repositories.Transfers.NewTransfer(player.Id, 2000000, player.MarketValue, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repositories.Players.GetPlayerOutOfTransferList(seller.Id, player.Id); err == nil {
+		t.Fatal("expected listed player to be rejected")
+	}
+
+	if listed, err := repositories.Transfers.IsPlayerListed(player.Id); err != nil || !listed {
+		t.Fatalf("expected player to be listed: %v", err)
+	}
+
+	_ = repositories.Transfers.UpdateTransfer(other.Id, &domain.Transfer{Id: transferId, AskedPrice: 1})
+	_ = repositories.Transfers.UpdateTransfer(seller.Id, &domain.Transfer{Id: transferId, AskedPrice: 2500000})
+
+	transfer, err := repositories.Transfers.GetTransfer(transferId)
+
+	if err != nil || transfer.AskedPrice != 2500000 || transfer.Player.Id != player.Id || transfer.TeamName != "erin FC" ||
+		transfer.Player.Attributes != player.Attributes {
+		t.Fatalf("unexpected transfer: %+v, %v", transfer, err)
+	}
+}
+
+func testFindTransfers(t *testing.T, repositories repository.Repositories) {
+	first := createAccount(t, repositories, "gina", 0, domain.GoalKeeper, domain.Defender, domain.Forward)
+	second := createAccount(t, repositories, "hugo", 0, domain.Forward, domain.Midfielder)
+	prices := []int{500, 300, 300, 100, 400}
+	players := append(first.Team.Players, second.Team.Players...)
+
+	for i, player := range players {
+		if _, err := repositories.Transfers.NewTransfer(player.Id, prices[i], player.MarketValue, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transfers, total, err := repositories.Transfers.FindTransfers(domain.TransferFilter{Position: domain.Forward, Limit: 10})
+
+	if err != nil || total != 2 || len(transfers) != 2 {
+		t.Fatalf("expected 2 forwards, got %d/%d: %v", len(transfers), total, err)
+	}
+
+	transfers, total, _ = repositories.Transfers.FindTransfers(domain.TransferFilter{TeamName: "HUGO", PlayerName: "mf", Limit: 10})
+
+	if total != 1 || len(transfers) != 1 || transfers[0].Player.Position != domain.Midfielder {
+		t.Fatalf("expected the midfielder of hugo FC, got %+v", transfers)
+	}
+
+	transfers, total, _ = repositories.Transfers.FindTransfers(domain.TransferFilter{MinAskedPrice: 200, MaxAskedPrice: 400, Limit: 10})
+
+	if total != 3 || len(transfers) != 3 {
+		t.Fatalf("expected 3 transfers in price range, got %d/%d", len(transfers), total)
+	}
+
+	filter := domain.TransferFilter{SortBy: "askedPrice", Descending: true, Limit: 2}
+	var seen []int
+
+	for page := 0; page < 5; page++ {
+		transfers, total, err = repositories.Transfers.FindTransfers(filter)
+
+		if err != nil || total != 5 {
+			t.Fatalf("unexpected page: total %d, %v", total, err)
+		}
+
+		for _, transfer := range transfers {
+			seen = append(seen, transfer.AskedPrice)
+		}
+
+		if len(transfers) < filter.Limit {
+			break
+		}
+
+		last := transfers[len(transfers)-1]
+		filter.After = &domain.TransferCursor{Value: last.AskedPrice, Id: last.Id}
+	}
+
+	if fmt.Sprint(seen) != "[500 400 300 300 100]" {
+		t.Fatalf("unexpected keyset pagination order: %v", seen)
+	}
+}
+
+func testConfirmTransfer(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "ivan", 1000, domain.Forward)
+	buyer := createAccount(t, repositories, "judy", 3000, domain.Defender)
+	poor := createAccount(t, repositories, "kyle", 100)
+	player := seller.Team.Players[0]
+
+	transferId, err := repositories.Transfers.NewTransfer(player.Id, 2000, player.MarketValue, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(transferId, poor.Team.Id, 9999, newContract(), time.Now()); err == nil {
+		t.Fatal("expected transfer to be rejected for insufficient funds")
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(transferId, seller.Team.Id, 9999, newContract(), time.Now()); err == nil {
+		t.Fatal("expected transfer to the owning team to be rejected")
+	}
+
+	unchanged, _ := repositories.Teams.GetTeamById(poor.Team.Id)
+
+	if unchanged.AvailableCash != 100 {
+		t.Fatalf("expected rejected transfer to leave cash untouched, got %d", unchanged.AvailableCash)
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(transferId, buyer.Team.Id, 3500, newContract(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	sellerTeam, _ := repositories.Teams.GetTeamById(seller.Team.Id)
+	buyerTeam, _ := repositories.Teams.GetTeamById(buyer.Team.Id)
+	moved, _ := repositories.Players.GetPlayer(player.Id)
+
+	if sellerTeam.AvailableCash != 3000 || buyerTeam.AvailableCash != 1000 {
+		t.Fatalf("unexpected cash after transfer: seller %d, buyer %d", sellerTeam.AvailableCash, buyerTeam.AvailableCash)
+	}
+
+	if moved.TeamId == nil || *moved.TeamId != buyer.Team.Id || moved.MarketValue != 3500 {
+		t.Fatalf("unexpected player after transfer: %+v", moved)
+	}
+
+	if _, err = repositories.Transfers.GetTransfer(transferId); err != sql.ErrNoRows {
+		t.Fatalf("expected completed transfer to leave the market, got %v", err)
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(transferId, poor.Team.Id, 1, newContract(), time.Now()); err == nil {
+		t.Fatal("expected completed transfer to be rejected")
+	}
+
+	if listed, _ := repositories.Transfers.IsPlayerListed(player.Id); listed {
+		t.Fatal("expected transferred player to be out of the transfer list")
+	}
+}
+
+func testWithdrawTransfer(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "mona", 0, domain.Forward)
+	buyer := createAccount(t, repositories, "ned", 5000)
+	player := seller.Team.Players[0]
+	transferId, err := repositories.Transfers.NewTransfer(player.Id, 2000, player.MarketValue, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if transfer, _ := repositories.Transfers.GetTransfer(transferId); transfer.Status != domain.TransferListed {
+		t.Fatalf("expected a new listing to be listed, got %q", transfer.Status)
+	}
+
+	if err = repositories.Transfers.WithdrawTransfer(transferId, buyer.Team.Id, time.Now()); err == nil {
+		t.Fatal("expected only the selling team to withdraw the listing")
+	}
+
+	if err = repositories.Transfers.WithdrawTransfer(transferId, seller.Team.Id, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Transfers.WithdrawTransfer(transferId, seller.Team.Id, time.Now()); err == nil {
+		t.Fatal("expected a withdrawn listing not to be withdrawn again")
+	}
+
+	if _, err = repositories.Transfers.GetTransfer(transferId); err != sql.ErrNoRows {
+		t.Fatalf("expected withdrawn listing to leave the market, got %v", err)
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(transferId, buyer.Team.Id, 3000, newContract(), time.Now()); err == nil {
+		t.Fatal("expected a withdrawn listing not to be bought")
+	}
+
+	if listed, _ := repositories.Transfers.IsPlayerListed(player.Id); listed {
+		t.Fatal("expected withdrawn player to be out of the transfer list")
+	}
+
+	if _, err = repositories.Players.GetPlayerOutOfTransferList(seller.Id, player.Id); err != nil {
+		t.Fatalf("expected withdrawn player to be listable again: %v", err)
+	}
+
+	relisted, err := repositories.Transfers.NewTransfer(player.Id, 2500, player.MarketValue, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repositories.Players.GetPlayerOutOfTransferList(seller.Id, player.Id); err == nil {
+		t.Fatal("expected relisted player to be rejected despite the withdrawn listing")
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(relisted, buyer.Team.Id, 3000, newContract(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Transfers.WithdrawTransfer(relisted, seller.Team.Id, time.Now()); err == nil {
+		t.Fatal("expected a completed transfer not to be withdrawn")
+	}
+}
+
+func testTransferHistory(t *testing.T, repositories repository.Repositories) {
+	first := createAccount(t, repositories, "olga", 5000, domain.Forward, domain.Defender)
+	second := createAccount(t, repositories, "pete", 5000)
+	third := createAccount(t, repositories, "quin", 5000)
+	player, other := first.Team.Players[0], first.Team.Players[1]
+	start := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	moves := []struct {
+		playerId, buyerId, price, valueAfter int
+		at                                   time.Time
+	}{
+		{player.Id, second.Team.Id, 1500, 1400000, start},
+		{other.Id, third.Team.Id, 1200, 1300000, start.AddDate(0, 0, 1)},
+		{player.Id, third.Team.Id, 2000, 1600000, start.AddDate(0, 0, 2)},
+	}
+
+	for _, move := range moves {
+		moved, _ := repositories.Players.GetPlayer(move.playerId)
+		transferId, err := repositories.Transfers.NewTransfer(move.playerId, move.price, moved.MarketValue, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err = repositories.Transfers.ConfirmTransfer(transferId, move.buyerId, move.valueAfter, newContract(), move.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	withdrawn, _ := repositories.Transfers.NewTransfer(player.Id, 9000, 1600000, nil)
+	_ = repositories.Transfers.WithdrawTransfer(withdrawn, third.Team.Id, start.AddDate(0, 0, 3))
+
+	all, err := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(all) != 4 || all[1].PlayerId != player.Id || all[3].PlayerId != player.Id {
+		t.Fatalf("expected three completed transfers and a withdrawal, newest first, got %+v", all)
+	}
+
+	if all[0].Status != domain.TransferWithdrawn || all[0].WithdrawnAt == nil || !all[0].WithdrawnAt.Equal(start.AddDate(0, 0, 3)) ||
+		all[0].TransferredAt != nil {
+		t.Fatalf("unexpected withdrawn listing in history: %+v", all[0])
+	}
+
+	latest := all[1]
+
+	if latest.Price != 2000 || latest.ValueBefore != 1400000 || latest.ValueAfter == nil || *latest.ValueAfter != 1600000 {
+		t.Fatalf("unexpected values on latest transfer: %+v", latest)
+	}
+
+	if latest.FromTeamName != second.Team.Name || latest.ToTeamName != third.Team.Name || latest.PlayerName == "" {
+		t.Fatalf("unexpected names on latest transfer: %+v", latest)
+	}
+
+	if latest.TransferredAt == nil || !latest.TransferredAt.Equal(start.AddDate(0, 0, 2)) {
+		t.Fatalf("unexpected transfer date: %v", latest.TransferredAt)
+	}
+
+	from, to := start.AddDate(0, 0, 1), start.AddDate(0, 0, 2)
+
+	cases := []struct {
+		name   string
+		filter domain.TransferHistoryFilter
+		want   int
+	}{
+		{"team", domain.TransferHistoryFilter{TeamId: second.Team.Id}, 2},
+		{"player", domain.TransferHistoryFilter{PlayerId: other.Id}, 1},
+		{"date range", domain.TransferHistoryFilter{From: &from, To: &to}, 1},
+		{"team and player", domain.TransferHistoryFilter{TeamId: third.Team.Id, PlayerId: player.Id}, 2},
+		{"withdrawn", domain.TransferHistoryFilter{Status: domain.TransferWithdrawn}, 1},
+		{"limit", domain.TransferHistoryFilter{Limit: 2}, 2},
+	}
+
+	for _, c := range cases {
+		transfers, err := repositories.Transfers.FindCompletedTransfers(c.filter)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(transfers) != c.want {
+			t.Fatalf("%s: expected %d transfers, got %d", c.name, c.want, len(transfers))
+		}
+	}
+
+	if _, err = repositories.Transfers.NewTransfer(other.Id, 3000, 1300000, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Players.DeletePlayer(other.Id, start.AddDate(0, 0, 4)); err != nil {
+		t.Fatal(err)
+	}
+
+	kept, err := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{TeamId: third.Team.Id})
+
+	if err != nil || len(kept) != 4 {
+		t.Fatalf("expected the deleted player's history to be kept, got %+v: %v", kept, err)
+	}
+
+	statuses := map[string]int{}
+
+	for _, transfer := range kept {
+		if transfer.PlayerId == 0 {
+			statuses[transfer.Status]++
+		}
+	}
+
+	if statuses[domain.TransferTransferred] != 1 || statuses[domain.TransferWithdrawn] != 1 {
+		t.Fatalf("expected a completed transfer and a withdrawn listing without a player, got %+v", kept)
+	}
+}
+
+func testAuctions(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "rita", 0, domain.Forward)
+	first := createAccount(t, repositories, "saul", 5000)
+	second := createAccount(t, repositories, "tina", 5000)
+	player := seller.Team.Players[0]
+	now := time.Now().UTC().Truncate(time.Second)
+	deadline := now.Add(time.Hour)
+
+	transferId, err := repositories.Transfers.NewAuction(player.Id, 1000, player.MarketValue, domain.Auction{Deadline: deadline})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repositories.Transfers.PlaceBid(transferId, seller.Team.Id, 1500, newTerms(), now); err == nil {
+		t.Fatal("expected the selling team not to bid")
+	}
+
+	if _, err = repositories.Transfers.PlaceBid(transferId, first.Team.Id, 900, newTerms(), now); err == nil {
+		t.Fatal("expected a bid below the reserve price to be rejected")
+	}
+
+	bids := []struct {
+		teamId, amount int
+		accepted       bool
+	}{
+		{first.Team.Id, 1500, true},
+		{second.Team.Id, 1500, false},
+		{second.Team.Id, 2000, true},
+		{first.Team.Id, 2500, true},
+		{second.Team.Id, 2400, false},
+		{second.Team.Id, 9000, false},
+	}
+
+	for _, b := range bids {
+		if _, err = repositories.Transfers.PlaceBid(transferId, b.teamId, b.amount, newTerms(), now); (err == nil) != b.accepted {
+			t.Fatalf("bid of %d by team %d: expected accepted %t, got %v", b.amount, b.teamId, b.accepted, err)
+		}
+	}
+
+	firstTeam, _ := repositories.Teams.GetTeamById(first.Team.Id)
+	secondTeam, _ := repositories.Teams.GetTeamById(second.Team.Id)
+
+	if firstTeam.AvailableCash != 2500 || secondTeam.AvailableCash != 3000 {
+		t.Fatalf("expected live bids to reserve cash, got %d and %d", firstTeam.AvailableCash, secondTeam.AvailableCash)
+	}
+
+	listing, err := repositories.Transfers.GetTransfer(transferId)
+
+	if err != nil || listing.Mode != domain.TransferAuction || listing.Auction == nil {
+		t.Fatalf("expected an auction listing, got %+v: %v", listing, err)
+	}
+
+	if listing.Auction.HighestBid == nil || *listing.Auction.HighestBid != 2500 || listing.Auction.Bids != 3 || !listing.Auction.Deadline.Equal(deadline) {
+		t.Fatalf("unexpected auction state: %+v", listing.Auction)
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(transferId, second.Team.Id, 3000, newContract(), now); err == nil {
+		t.Fatal("expected an auction not to be bought at the asked price")
+	}
+
+	if err = repositories.Transfers.WithdrawTransfer(transferId, seller.Team.Id, now); err == nil {
+		t.Fatal("expected an auction with bids not to be withdrawn")
+	}
+
+	if _, err = repositories.Transfers.PlaceBid(transferId, second.Team.Id, 4000, newTerms(), deadline); err == nil {
+		t.Fatal("expected a bid at the deadline to be rejected")
+	}
+
+	if due, _ := repositories.Transfers.FindDueAuctionIds(now); len(due) != 0 {
+		t.Fatalf("expected no auction due before its deadline, got %v", due)
+	}
+
+	due, err := repositories.Transfers.FindDueAuctionIds(deadline)
+
+	if err != nil || len(due) != 1 || due[0] != transferId {
+		t.Fatalf("expected the auction to be due at its deadline, got %v: %v", due, err)
+	}
+
+	placed, _ := repositories.Transfers.FindBids(transferId)
+
+	if len(placed) != 2 || placed[0].TeamId != first.Team.Id || placed[0].Amount != 2500 || placed[0].TeamName != first.Team.Name {
+		t.Fatalf("expected the highest bid first, got %+v", placed)
+	}
+
+	if terms := placed[0].Contract; terms == nil || *terms != newTerms() {
+		t.Fatalf("expected the bid to keep its contract terms, got %+v", terms)
+	}
+
+	won := newContract()
+
+	if err = repositories.Transfers.SettleAuction(transferId, placed[0].Id, 3000, won, deadline); err != nil {
+		t.Fatal(err)
+	}
+
+	if contract, err := repositories.Contracts.GetContract(player.Id); err != nil || contract.Id != won.Id {
+		t.Fatalf("expected the winning bid's contract to replace the old one, got %+v: %v", contract, err)
+	}
+
+	if err = repositories.Transfers.SettleAuction(transferId, placed[0].Id, 3000, newContract(), deadline); err == nil {
+		t.Fatal("expected a settled auction not to be settled again")
+	}
+
+	sellerTeam, _ := repositories.Teams.GetTeamById(seller.Team.Id)
+	firstTeam, _ = repositories.Teams.GetTeamById(first.Team.Id)
+	secondTeam, _ = repositories.Teams.GetTeamById(second.Team.Id)
+	moved, _ := repositories.Players.GetPlayer(player.Id)
+
+	if sellerTeam.AvailableCash != 2500 || firstTeam.AvailableCash != 2500 || secondTeam.AvailableCash != 5000 {
+		t.Fatalf("unexpected cash after settlement: seller %d, winner %d, loser %d",
+			sellerTeam.AvailableCash, firstTeam.AvailableCash, secondTeam.AvailableCash)
+	}
+
+	if moved.TeamId == nil || *moved.TeamId != first.Team.Id || moved.MarketValue != 3000 {
+		t.Fatalf("unexpected player after settlement: %+v", moved)
+	}
+
+	settled, _ := repositories.Transfers.FindBids(transferId)
+
+	if settled[0].Status != domain.BidWon || settled[1].Status != domain.BidRefunded {
+		t.Fatalf("unexpected bid statuses after settlement: %+v", settled)
+	}
+
+	history, _ := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{PlayerId: player.Id})
+
+	if len(history) != 1 || history[0].Price != 2500 || history[0].ToTeamName != first.Team.Name {
+		t.Fatalf("expected the auction in the transfer history, got %+v", history)
+	}
+}
+
+func testSealedAuctions(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "uma", 0, domain.Forward, domain.Defender)
+	bidder := createAccount(t, repositories, "vic", 5000)
+	rival := createAccount(t, repositories, "walt", 5000)
+	unsold, sold := seller.Team.Players[0], seller.Team.Players[1]
+	now := time.Now().UTC().Truncate(time.Second)
+	deadline := now.Add(time.Hour)
+
+	unsoldId, _ := repositories.Transfers.NewAuction(unsold.Id, 1000, unsold.MarketValue, domain.Auction{Deadline: deadline, Sealed: true})
+	soldId, _ := repositories.Transfers.NewAuction(sold.Id, 1000, sold.MarketValue, domain.Auction{Deadline: deadline, Sealed: true})
+
+	if _, err := repositories.Transfers.PlaceBid(soldId, bidder.Team.Id, 2000, newTerms(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repositories.Transfers.PlaceBid(soldId, rival.Team.Id, 1500, newTerms(), now); err != nil {
+		t.Fatalf("expected a lower sealed bid to be accepted: %v", err)
+	}
+
+	if listing, _ := repositories.Transfers.GetTransfer(soldId); listing.Auction == nil || listing.Auction.HighestBid != nil || listing.Auction.Bids != 2 {
+		t.Fatalf("expected a sealed auction to hide its highest bid, got %+v", listing.Auction)
+	}
+
+	if err := repositories.Transfers.SettleAuction(unsoldId, 0, 0, nil, deadline); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repositories.Transfers.GetTransfer(unsoldId); err != sql.ErrNoRows {
+		t.Fatalf("expected an unsold auction to leave the market, got %v", err)
+	}
+
+	if kept, _ := repositories.Players.GetPlayer(unsold.Id); kept.TeamId == nil || *kept.TeamId != seller.Team.Id {
+		t.Fatalf("expected an unsold player to stay with the seller, got %+v", kept)
+	}
+
+	if err := repositories.Teams.DeleteTeam(seller.Team.Id, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	bidderTeam, _ := repositories.Teams.GetTeamById(bidder.Team.Id)
+	rivalTeam, _ := repositories.Teams.GetTeamById(rival.Team.Id)
+
+	if bidderTeam.AvailableCash != 5000 || rivalTeam.AvailableCash != 5000 {
+		t.Fatalf("expected bids to be refunded when the selling team is deleted, got %d and %d",
+			bidderTeam.AvailableCash, rivalTeam.AvailableCash)
+	}
+
+	if due, _ := repositories.Transfers.FindDueAuctionIds(deadline); len(due) != 0 {
+		t.Fatalf("expected no auctions left to settle, got %v", due)
+	}
+
+	withdrawn, err := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{PlayerId: sold.Id})
+
+	if err != nil || len(withdrawn) != 1 || withdrawn[0].Status != domain.TransferWithdrawn || withdrawn[0].WithdrawnAt == nil {
+		t.Fatalf("expected the auction of a deleted team to be withdrawn, got %+v: %v", withdrawn, err)
+	}
+
+	if bids, _ := repositories.Transfers.FindBids(soldId); len(bids) != 2 || bids[0].Status != domain.BidRefunded || bids[1].Status != domain.BidRefunded {
+		t.Fatalf("expected the bids to be kept as refunded, got %+v", bids)
+	}
+}
+
+func createOffer(t *testing.T, repositories repository.Repositories, player domain.Player, buyerId, amount int, now time.Time) *domain.Offer {
+	t.Helper()
+
+	terms := newTerms()
+	offer := &domain.Offer{PlayerId: player.Id, BuyerId: buyerId, SellerId: *player.TeamId, Amount: amount, ExpiresAt: now.Add(time.Hour), CreatedAt: now,
+		Contract: &terms}
+
+	if err := repositories.Offers.CreateOffer(offer); err != nil {
+		t.Fatal(err)
+	}
+
+	return offer
+}
+
+func testOffers(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "xena", 0, domain.Forward)
+	buyer := createAccount(t, repositories, "yuri", 5000)
+	rival := createAccount(t, repositories, "zack", 5000)
+	player := seller.Team.Players[0]
+	now := time.Now().UTC().Truncate(time.Second)
+
+	if err := repositories.Offers.CreateOffer(&domain.Offer{PlayerId: player.Id, BuyerId: buyer.Team.Id, SellerId: rival.Team.Id,
+		Amount: 1000, ExpiresAt: now.Add(time.Hour), CreatedAt: now}); err == nil {
+		t.Fatal("expected an offer naming the wrong selling team to be rejected")
+	}
+
+	offer := createOffer(t, repositories, player, buyer.Team.Id, 2000, now)
+	competing := createOffer(t, repositories, player, rival.Team.Id, 2500, now)
+	listingId, _ := repositories.Transfers.NewTransfer(player.Id, 9000, player.MarketValue, nil)
+
+	stored, err := repositories.Offers.GetOffer(offer.Id)
+
+	if err != nil || stored.Status != domain.OfferOpen || stored.AwaitingTeamId != seller.Team.Id || len(stored.Events) != 1 {
+		t.Fatalf("unexpected new offer: %+v: %v", stored, err)
+	}
+
+	if stored.BuyerName != buyer.Team.Name || stored.SellerName != seller.Team.Name || stored.PlayerName == "" {
+		t.Fatalf("expected offer to be described with names, got %+v", stored)
+	}
+
+	if stored.Contract == nil || *stored.Contract != newTerms() {
+		t.Fatalf("expected the offer to keep its contract terms, got %+v", stored.Contract)
+	}
+
+	if err = repositories.Offers.CounterOffer(offer.Id, buyer.Team.Id, 2100, now.Add(time.Hour), now); err == nil {
+		t.Fatal("expected a team to wait for the other side before countering")
+	}
+
+	if err = repositories.Offers.CounterOffer(offer.Id, seller.Team.Id, 3000, now.Add(2*time.Hour), now); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Offers.AcceptOffer(offer.Id, seller.Team.Id, 3500, newContract(), now); err == nil {
+		t.Fatal("expected a team not to accept its own counter-offer")
+	}
+
+	agreed := newContract()
+
+	if err = repositories.Offers.AcceptOffer(offer.Id, buyer.Team.Id, 3500, agreed, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if contract, err := repositories.Contracts.GetContract(player.Id); err != nil || contract.Id != agreed.Id {
+		t.Fatalf("expected the offer's contract to replace the old one, got %+v: %v", contract, err)
+	}
+
+	accepted, _ := repositories.Offers.GetOffer(offer.Id)
+
+	if accepted.Status != domain.OfferAccepted || accepted.Amount != 3000 || accepted.TransferId == nil || len(accepted.Events) != 3 {
+		t.Fatalf("unexpected accepted offer: %+v", accepted)
+	}
+
+	if accepted.Events[1].Action != domain.OfferActionCounter || accepted.Events[2].Action != domain.OfferActionAccept {
+		t.Fatalf("unexpected negotiation thread: %+v", accepted.Events)
+	}
+
+	if other, _ := repositories.Offers.GetOffer(competing.Id); other.Status != domain.OfferCancelled {
+		t.Fatalf("expected competing offers to be cancelled, got %q", other.Status)
+	}
+
+	sellerTeam, _ := repositories.Teams.GetTeamById(seller.Team.Id)
+	buyerTeam, _ := repositories.Teams.GetTeamById(buyer.Team.Id)
+	moved, _ := repositories.Players.GetPlayer(player.Id)
+
+	if sellerTeam.AvailableCash != 3000 || buyerTeam.AvailableCash != 2000 {
+		t.Fatalf("unexpected cash after offer: seller %d, buyer %d", sellerTeam.AvailableCash, buyerTeam.AvailableCash)
+	}
+
+	if moved.TeamId == nil || *moved.TeamId != buyer.Team.Id || moved.MarketValue != 3500 {
+		t.Fatalf("unexpected player after offer: %+v", moved)
+	}
+
+	if _, err = repositories.Transfers.GetTransfer(listingId); err != sql.ErrNoRows {
+		t.Fatalf("expected the player's listing to be withdrawn, got %v", err)
+	}
+
+	history, _ := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{PlayerId: player.Id, Status: domain.TransferTransferred})
+
+	if len(history) != 1 || history[0].Id != *accepted.TransferId || history[0].Price != 3000 || history[0].ToTeamName != buyer.Team.Name {
+		t.Fatalf("expected the accepted offer in the transfer history, got %+v", history)
+	}
+
+	if offers, _ := repositories.Offers.FindOffers(seller.Team.Id, ""); len(offers) != 2 {
+		t.Fatalf("expected both offers for the selling team, got %d", len(offers))
+	}
+
+	if offers, _ := repositories.Offers.FindOffers(rival.Team.Id, domain.OfferOpen); len(offers) != 0 {
+		t.Fatalf("expected no open offers left, got %+v", offers)
+	}
+}
+
+func testOfferExpiry(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "abel", 0, domain.Forward, domain.Defender, domain.Midfielder)
+	buyer := createAccount(t, repositories, "bea", 5000)
+	expiring, rejected, auctioned := seller.Team.Players[0], seller.Team.Players[1], seller.Team.Players[2]
+	now := time.Now().UTC().Truncate(time.Second)
+	later := now.Add(2 * time.Hour)
+
+	offer := createOffer(t, repositories, expiring, buyer.Team.Id, 1000, now)
+
+	if err := repositories.Offers.CounterOffer(offer.Id, seller.Team.Id, 1500, later, later); err == nil {
+		t.Fatal("expected an expired offer not to be countered")
+	}
+
+	if err := repositories.Offers.AcceptOffer(offer.Id, seller.Team.Id, 1200, newContract(), later); err == nil {
+		t.Fatal("expected an expired offer not to be accepted")
+	}
+
+	if err := repositories.Offers.CloseOffer(offer.Id, buyer.Team.Id, domain.OfferWithdrawn, later); err == nil {
+		t.Fatal("expected an expired offer not to be withdrawn")
+	}
+
+	declined := createOffer(t, repositories, rejected, buyer.Team.Id, 1000, now)
+
+	if err := repositories.Offers.CloseOffer(declined.Id, seller.Team.Id, domain.OfferRejected, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, _ := repositories.Offers.GetOffer(declined.Id); stored.Status != domain.OfferRejected || stored.Events[1].Action != domain.OfferActionReject {
+		t.Fatalf("unexpected rejected offer: %+v", stored)
+	}
+
+	_, _ = repositories.Transfers.NewAuction(auctioned.Id, 500, auctioned.MarketValue, domain.Auction{Deadline: later})
+	blocked := createOffer(t, repositories, auctioned, buyer.Team.Id, 1000, now)
+
+	if err := repositories.Offers.AcceptOffer(blocked.Id, seller.Team.Id, 1200, newContract(), now); err == nil {
+		t.Fatal("expected an offer for an auctioned player not to be accepted")
+	}
+
+	if team, _ := repositories.Teams.GetTeamById(buyer.Team.Id); team.AvailableCash != 5000 {
+		t.Fatalf("expected failed offers to leave cash untouched, got %d", team.AvailableCash)
+	}
+}
+
+func proposeLoan(t *testing.T, repositories repository.Repositories, player domain.Player, borrowerId, fee int, days, rounds, buyOption *int, now time.Time) *domain.Loan {
+	t.Helper()
+
+	loan := &domain.Loan{PlayerId: player.Id, LenderId: *player.TeamId, BorrowerId: borrowerId, Fee: fee,
+		DurationDays: days, DurationRounds: rounds, BuyOptionPrice: buyOption, ProposedAt: now}
+
+	if err := repositories.Loans.CreateLoan(loan); err != nil {
+		t.Fatal(err)
+	}
+
+	return loan
+}
+
+func testLoans(t *testing.T, repositories repository.Repositories) {
+	lender := createAccount(t, repositories, "carl", 0, domain.Forward)
+	borrower := createAccount(t, repositories, "dora", 5000)
+	rival := createAccount(t, repositories, "emil", 5000)
+	player := lender.Team.Players[0]
+	now := time.Now().UTC().Truncate(time.Second)
+	days, rounds, buyOption := 7, 3, 3000
+
+	if err := repositories.Loans.CreateLoan(&domain.Loan{PlayerId: player.Id, LenderId: rival.Team.Id, BorrowerId: borrower.Team.Id,
+		DurationDays: &days, ProposedAt: now}); err == nil {
+		t.Fatal("expected a loan naming the wrong lending team to be rejected")
+	}
+
+	loan := proposeLoan(t, repositories, player, borrower.Team.Id, 1000, &days, nil, &buyOption, now)
+	competing := proposeLoan(t, repositories, player, rival.Team.Id, 500, nil, &rounds, nil, now)
+	offer := createOffer(t, repositories, player, rival.Team.Id, 2500, now)
+	listingId, _ := repositories.Transfers.NewTransfer(player.Id, 9000, player.MarketValue, nil)
+
+	if err := repositories.Loans.StartLoan(loan.Id, borrower.Team.Id, now); err == nil {
+		t.Fatal("expected only the lending team to start a loan")
+	}
+
+	if err := repositories.Loans.StartLoan(loan.Id, lender.Team.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	active, _ := repositories.Loans.GetLoan(loan.Id)
+
+	if active.Status != domain.LoanActive || active.EndsAt == nil || !active.EndsAt.Equal(now.AddDate(0, 0, days)) || active.LenderName != lender.Team.Name {
+		t.Fatalf("unexpected active loan: %+v", active)
+	}
+
+	if other, _ := repositories.Loans.GetLoan(competing.Id); other.Status != domain.LoanCancelled {
+		t.Fatalf("expected competing loan proposals to be cancelled, got %q", other.Status)
+	}
+
+	if other, _ := repositories.Offers.GetOffer(offer.Id); other.Status != domain.OfferCancelled {
+		t.Fatalf("expected open offers to be cancelled, got %q", other.Status)
+	}
+
+	if _, err := repositories.Transfers.GetTransfer(listingId); err != sql.ErrNoRows {
+		t.Fatalf("expected the player's listing to be withdrawn, got %v", err)
+	}
+
+	loaned, _ := repositories.Players.GetPlayer(player.Id)
+	lenderTeam, _ := repositories.Teams.GetTeamById(lender.Team.Id)
+	borrowerTeam, _ := repositories.Teams.GetTeamById(borrower.Team.Id)
+
+	if loaned.TeamId == nil || *loaned.TeamId != borrower.Team.Id || lenderTeam.AvailableCash != 1000 || borrowerTeam.AvailableCash != 4000 {
+		t.Fatalf("unexpected state after loan start: player team %v, cash %d/%d", loaned.TeamId, lenderTeam.AvailableCash, borrowerTeam.AvailableCash)
+	}
+
+	if _, err := repositories.Players.GetPlayerOutOfTransferList(borrower.Id, player.Id); err == nil {
+		t.Fatal("expected the borrowing team not to list a loaned player")
+	}
+
+	if err := repositories.Offers.CreateOffer(&domain.Offer{PlayerId: player.Id, BuyerId: rival.Team.Id, SellerId: borrower.Team.Id,
+		Amount: 1000, ExpiresAt: now.Add(time.Hour), CreatedAt: now}); err == nil {
+		t.Fatal("expected offers for a loaned player to be rejected")
+	}
+
+	if due, _ := repositories.Loans.FindDueLoanIds(now.AddDate(0, 0, days-1)); len(due) != 0 {
+		t.Fatalf("expected no loans due before the end date, got %v", due)
+	}
+
+	if due, _ := repositories.Loans.FindDueLoanIds(now.AddDate(0, 0, days)); len(due) != 1 || due[0] != loan.Id {
+		t.Fatalf("expected the loan to be due at its end date, got %v", due)
+	}
+
+	if err := repositories.Loans.BuyLoanedPlayer(loan.Id, lender.Team.Id, 3500, newContract(), now); err == nil {
+		t.Fatal("expected only the borrowing team to exercise the buy option")
+	}
+
+	signed := newContract()
+
+	if err := repositories.Loans.BuyLoanedPlayer(loan.Id, borrower.Team.Id, 3500, signed, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if contract, err := repositories.Contracts.GetContract(player.Id); err != nil || contract.Id != signed.Id {
+		t.Fatalf("expected the buy option's contract to replace the old one, got %+v: %v", contract, err)
+	}
+
+	bought, _ := repositories.Loans.GetLoan(loan.Id)
+	owned, _ := repositories.Players.GetPlayer(player.Id)
+	lenderTeam, _ = repositories.Teams.GetTeamById(lender.Team.Id)
+	borrowerTeam, _ = repositories.Teams.GetTeamById(borrower.Team.Id)
+
+	if bought.Status != domain.LoanBought || bought.TransferId == nil || bought.EndedAt == nil {
+		t.Fatalf("unexpected bought loan: %+v", bought)
+	}
+
+	if owned.TeamId == nil || *owned.TeamId != borrower.Team.Id || owned.MarketValue != 3500 || lenderTeam.AvailableCash != 4000 || borrowerTeam.AvailableCash != 1000 {
+		t.Fatalf("unexpected state after buy option: %+v, cash %d/%d", owned, lenderTeam.AvailableCash, borrowerTeam.AvailableCash)
+	}
+
+	history, _ := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{PlayerId: player.Id, Status: domain.TransferTransferred})
+
+	if len(history) != 1 || history[0].Id != *bought.TransferId || history[0].Price != buyOption || history[0].FromTeamName != lender.Team.Name {
+		t.Fatalf("expected the buy option in the transfer history, got %+v", history)
+	}
+
+	if due, _ := repositories.Loans.FindDueLoanIds(now.AddDate(0, 0, days+1)); len(due) != 0 {
+		t.Fatalf("expected bought loans not to be returned, got %v", due)
+	}
+}
+
+func testLoanReturns(t *testing.T, repositories repository.Repositories) {
+	lender := createAccount(t, repositories, "finn", 0, domain.Forward, domain.Midfielder, domain.Defender)
+	borrower := createAccount(t, repositories, "gwen", 5000)
+	opponent := createAccount(t, repositories, "hugo", 0)
+	forward, midfielder, defender := lender.Team.Players[0], lender.Team.Players[1], lender.Team.Players[2]
+	now := time.Now().UTC().Truncate(time.Second)
+	days, rounds := 30, 2
+
+	loan := proposeLoan(t, repositories, forward, borrower.Team.Id, 0, nil, &rounds, nil, now)
+
+	if err := repositories.Loans.StartLoan(loan.Id, lender.Team.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= rounds; i++ {
+		if due, _ := repositories.Loans.FindDueLoanIds(now); len(due) != 0 {
+			t.Fatalf("expected no loans due after %d rounds, got %v", i-1, due)
+		}
+
+		played := &domain.Match{HomeTeamId: &borrower.Team.Id, AwayTeamId: &opponent.Team.Id, Seed: int64(i), PlayedAt: now.Add(time.Duration(i) * time.Hour)}
+
+		if err := repositories.Matches.CreateMatch(played); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if stored, _ := repositories.Loans.GetLoan(loan.Id); stored.RoundsPlayed != rounds {
+		t.Fatalf("expected %d rounds played, got %d", rounds, stored.RoundsPlayed)
+	}
+
+	if due, _ := repositories.Loans.FindDueLoanIds(now); len(due) != 1 || due[0] != loan.Id {
+		t.Fatalf("expected the loan to be due after its rounds, got %v", due)
+	}
+
+	if err := repositories.Loans.ReturnLoan(loan.Id, now.Add(3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repositories.Loans.ReturnLoan(loan.Id, now.Add(3*time.Hour)); err == nil {
+		t.Fatal("expected a returned loan not to be returned again")
+	}
+
+	returned, _ := repositories.Players.GetPlayer(forward.Id)
+
+	if returned.TeamId == nil || *returned.TeamId != lender.Team.Id {
+		t.Fatalf("expected the player back with the lending team, got %v", returned.TeamId)
+	}
+
+	declined := proposeLoan(t, repositories, defender, borrower.Team.Id, 100, &days, nil, nil, now)
+
+	if err := repositories.Loans.CloseLoan(declined.Id, lender.Team.Id, domain.LoanRejected, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repositories.Loans.StartLoan(declined.Id, lender.Team.Id, now); err == nil {
+		t.Fatal("expected a rejected loan not to start")
+	}
+
+	stranded := proposeLoan(t, repositories, midfielder, borrower.Team.Id, 100, &days, nil, nil, now)
+
+	if err := repositories.Loans.StartLoan(stranded.Id, lender.Team.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repositories.Teams.DeleteTeam(borrower.Team.Id, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if recalled, _ := repositories.Players.GetPlayer(midfielder.Id); recalled.TeamId == nil || *recalled.TeamId != lender.Team.Id {
+		t.Fatalf("expected deleting the borrowing team to return the player, got %v", recalled.TeamId)
+	}
+
+	if loans, _ := repositories.Loans.FindLoans(lender.Team.Id, ""); len(loans) != 0 {
+		t.Fatalf("expected loans with the deleted team to be removed, got %+v", loans)
+	}
+}
+
+func testSwaps(t *testing.T, repositories repository.Repositories) {
+	proposer := createAccount(t, repositories, "ivan", 1000, domain.Forward, domain.Midfielder)
+	receiver := createAccount(t, repositories, "jade", 0, domain.Defender, domain.GoalKeeper)
+	rival := createAccount(t, repositories, "kurt", 5000)
+	forward, midfielder := proposer.Team.Players[0], proposer.Team.Players[1]
+	defender, keeper := receiver.Team.Players[0], receiver.Team.Players[1]
+	now := time.Now().UTC().Truncate(time.Second)
+
+	swapOf := func(players ...domain.Player) *domain.Swap {
+		swap := &domain.Swap{ProposerId: proposer.Team.Id, ReceiverId: receiver.Team.Id, CashAdjustment: 600, ProposedAt: now}
+
+		for _, player := range players {
+			swap.Players = append(swap.Players, domain.SwapPlayer{PlayerId: player.Id, FromTeamId: *player.TeamId})
+		}
+
+		return swap
+	}
+
+	if err := repositories.Swaps.CreateSwap(&domain.Swap{ProposerId: proposer.Team.Id, ReceiverId: receiver.Team.Id, ProposedAt: now,
+		Players: []domain.SwapPlayer{{PlayerId: forward.Id, FromTeamId: receiver.Team.Id}}}); err == nil {
+		t.Fatal("expected a swap naming the wrong owning team to be rejected")
+	}
+
+	swap := swapOf(forward, midfielder, defender)
+	competing := swapOf(midfielder, keeper)
+
+	for _, s := range []*domain.Swap{swap, competing} {
+		if err := repositories.Swaps.CreateSwap(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	offer := createOffer(t, repositories, defender, rival.Team.Id, 2000, now)
+	listingId, _ := repositories.Transfers.NewTransfer(forward.Id, 9000, forward.MarketValue, nil)
+	values := map[int]int{forward.Id: 1100, midfielder.Id: 1200, defender.Id: 1300}
+
+	if stored, _ := repositories.Swaps.GetSwap(swap.Id); stored.Status != domain.SwapProposed || len(stored.Players) != 3 || stored.ReceiverName != receiver.Team.Name {
+		t.Fatalf("unexpected proposed swap: %+v", stored)
+	}
+
+	if err := repositories.Swaps.CompleteSwap(swap.Id, proposer.Team.Id, values, swapContracts(values), now); err == nil {
+		t.Fatal("expected only the receiving team to complete a swap")
+	}
+
+	if err := repositories.Swaps.CompleteSwap(swap.Id, receiver.Team.Id, map[int]int{forward.Id: 1100}, swapContracts(values), now); err == nil {
+		t.Fatal("expected a swap without every new market value to fail")
+	}
+
+	contracts := swapContracts(values)
+
+	if err := repositories.Swaps.CompleteSwap(swap.Id, receiver.Team.Id, values, contracts, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	for playerId, signed := range contracts {
+		if contract, err := repositories.Contracts.GetContract(playerId); err != nil || contract.Id != signed.Id {
+			t.Fatalf("expected the swap to replace the contract of player %d, got %+v: %v", playerId, contract, err)
+		}
+	}
+
+	for playerId, teamId := range map[int]int{forward.Id: receiver.Team.Id, midfielder.Id: receiver.Team.Id, defender.Id: proposer.Team.Id} {
+		if player, _ := repositories.Players.GetPlayer(playerId); player.TeamId == nil || *player.TeamId != teamId || player.MarketValue != values[playerId] {
+			t.Fatalf("unexpected player %d after swap: %+v", playerId, player)
+		}
+	}
+
+	proposerTeam, _ := repositories.Teams.GetTeamById(proposer.Team.Id)
+	receiverTeam, _ := repositories.Teams.GetTeamById(receiver.Team.Id)
+
+	if proposerTeam.AvailableCash != 400 || receiverTeam.AvailableCash != 600 {
+		t.Fatalf("unexpected cash after swap: %d/%d", proposerTeam.AvailableCash, receiverTeam.AvailableCash)
+	}
+
+	completed, _ := repositories.Swaps.GetSwap(swap.Id)
+
+	if completed.Status != domain.SwapCompleted || completed.RespondedAt == nil {
+		t.Fatalf("unexpected completed swap: %+v", completed)
+	}
+
+	for _, player := range completed.Players {
+		if player.TransferId == nil {
+			t.Fatalf("expected every swapped player to have a transfer, got %+v", player)
+		}
+	}
+
+	if other, _ := repositories.Swaps.GetSwap(competing.Id); other.Status != domain.SwapCancelled {
+		t.Fatalf("expected swaps sharing players to be cancelled, got %q", other.Status)
+	}
+
+	if other, _ := repositories.Offers.GetOffer(offer.Id); other.Status != domain.OfferCancelled {
+		t.Fatalf("expected offers for swapped players to be cancelled, got %q", other.Status)
+	}
+
+	if _, err := repositories.Transfers.GetTransfer(listingId); err != sql.ErrNoRows {
+		t.Fatalf("expected the listing of a swapped player to be withdrawn, got %v", err)
+	}
+
+	history, _ := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{TeamId: proposer.Team.Id, Status: domain.TransferTransferred})
+
+	if len(history) != 3 {
+		t.Fatalf("expected three swap transfers in the history, got %+v", history)
+	}
+
+	expensive := &domain.Swap{ProposerId: receiver.Team.Id, ReceiverId: proposer.Team.Id, CashAdjustment: 5000, ProposedAt: now,
+		Players: []domain.SwapPlayer{{PlayerId: keeper.Id, FromTeamId: receiver.Team.Id}, {PlayerId: defender.Id, FromTeamId: proposer.Team.Id}}}
+
+	if err := repositories.Swaps.CreateSwap(expensive); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repositories.Swaps.CompleteSwap(expensive.Id, proposer.Team.Id, map[int]int{keeper.Id: 1, defender.Id: 1}, swapContracts(map[int]int{keeper.Id: 1, defender.Id: 1}), now); err == nil {
+		t.Fatal("expected a swap the payer cannot afford to fail")
+	}
+
+	if player, _ := repositories.Players.GetPlayer(keeper.Id); player.TeamId == nil || *player.TeamId != receiver.Team.Id || player.MarketValue == 1 {
+		t.Fatalf("expected a failed swap to leave players untouched, got %+v", player)
+	}
+
+	if err := repositories.Swaps.CloseSwap(expensive.Id, proposer.Team.Id, domain.SwapRejected, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if swaps, _ := repositories.Swaps.FindSwaps(receiver.Team.Id, domain.SwapRejected); len(swaps) != 1 || swaps[0].Id != expensive.Id {
+		t.Fatalf("expected the rejected swap to be listed, got %+v", swaps)
+	}
+}
+
+func testTransferWindows(t *testing.T, repositories repository.Repositories) {
+	now := time.Now().UTC().Truncate(time.Second)
+	l := &domain.League{Name: "Premier League", Country: "England", Status: domain.LeagueOpen, Seed: 5, CreatedAt: now}
+
+	if err := repositories.Leagues.CreateLeague(l); err != nil {
+		t.Fatal(err)
+	}
+
+	opensAt, closesAt := now.Add(-time.Hour), now.Add(time.Hour)
+	opensAfter, closesAfter := 0, 3
+	dated := &domain.TransferWindow{Name: "Summer", OpensAt: &opensAt, ClosesAt: &closesAt, ListingPolicy: domain.WindowExpire, CreatedAt: now}
+	rounds := &domain.TransferWindow{Name: "Mid season", LeagueId: &l.Id, OpensAfterRound: &opensAfter, ClosesAfterRound: &closesAfter,
+		ListingPolicy: domain.WindowCarryOver, CreatedAt: now}
+
+	for _, window := range []*domain.TransferWindow{dated, rounds} {
+		if err := repositories.Windows.CreateWindow(window); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	windows, err := repositories.Windows.FindWindows()
+
+	if err != nil || len(windows) != 2 || windows[0].Id != dated.Id || windows[1].Id != rounds.Id {
+		t.Fatalf("expected both windows in creation order, got %+v: %v", windows, err)
+	}
+
+	if w := windows[0]; w.Name != "Summer" || !w.OpensAt.Equal(opensAt) || !w.ClosesAt.Equal(closesAt) || w.LeagueId != nil || w.ListingPolicy != domain.WindowExpire {
+		t.Fatalf("unexpected dated window: %+v", w)
+	}
+
+	if w := windows[1]; w.LeagueId == nil || *w.LeagueId != l.Id || w.LeagueStatus != domain.LeagueOpen || w.LeagueRound != 0 || *w.ClosesAfterRound != 3 {
+		t.Fatalf("unexpected round window: %+v", w)
+	}
+
+	closed := domain.WindowClosed
+
+	if err = repositories.Windows.UpdateManualState(dated.Id, &closed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Windows.MarkWindowClosed(dated.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Windows.MarkWindowClosed(dated.Id, now); err == nil {
+		t.Fatal("expected a window to be marked closed only once")
+	}
+
+	if w, _ := repositories.Windows.GetWindow(dated.Id); w.ManualState == nil || *w.ManualState != closed || w.ClosedAt == nil || !w.ClosedAt.Equal(now) {
+		t.Fatalf("expected a manually closed window, got %+v", w)
+	}
+
+	open := domain.WindowOpen
+
+	if err = repositories.Windows.UpdateManualState(dated.Id, &open); err != nil {
+		t.Fatal(err)
+	}
+
+	if w, _ := repositories.Windows.GetWindow(dated.Id); w.ManualState == nil || *w.ManualState != open || w.ClosedAt != nil {
+		t.Fatalf("expected reopening to clear the closing time, got %+v", w)
+	}
+
+	if err = repositories.Windows.DeleteWindow(rounds.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repositories.Windows.GetWindow(rounds.Id); err != sql.ErrNoRows {
+		t.Fatalf("expected a deleted window to be gone, got %v", err)
+	}
+
+	if err = repositories.Windows.DeleteWindow(rounds.Id); err != sql.ErrNoRows {
+		t.Fatalf("expected deleting a missing window to report no rows, got %v", err)
+	}
+}
+
+func testExpireListings(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "lena", 0, domain.Forward, domain.Defender)
+	bidder := createAccount(t, repositories, "milo", 5000)
+	forward, defender := seller.Team.Players[0], seller.Team.Players[1]
+	now := time.Now().UTC().Truncate(time.Second)
+
+	listingId, err := repositories.Transfers.NewTransfer(forward.Id, 3000, forward.MarketValue, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auctionId, err := repositories.Transfers.NewAuction(defender.Id, 1000, defender.MarketValue, domain.Auction{Deadline: now.Add(time.Hour)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repositories.Transfers.PlaceBid(auctionId, bidder.Team.Id, 1500, newTerms(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := repositories.Transfers.ExpireListings(now)
+
+	if err != nil || expired != 2 {
+		t.Fatalf("expected both listings to expire, got %d: %v", expired, err)
+	}
+
+	for _, transferId := range []int{listingId, auctionId} {
+		if _, err = repositories.Transfers.GetTransfer(transferId); err != sql.ErrNoRows {
+			t.Fatalf("expected listing %d to be off the market, got %v", transferId, err)
+		}
+	}
+
+	if team, _ := repositories.Teams.GetTeamById(bidder.Team.Id); team.AvailableCash != 5000 {
+		t.Fatalf("expected the bid to be refunded, got %d", team.AvailableCash)
+	}
+
+	if bids, _ := repositories.Transfers.FindBids(auctionId); len(bids) != 1 || bids[0].Status != domain.BidRefunded {
+		t.Fatalf("expected a refunded bid, got %+v", bids)
+	}
+
+	if expired, err = repositories.Transfers.ExpireListings(now); err != nil || expired != 0 {
+		t.Fatalf("expected nothing left to expire, got %d: %v", expired, err)
+	}
+}
+
+func testListingExpiry(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "nora", 0, domain.Forward, domain.Defender)
+	buyer := createAccount(t, repositories, "otto", 5000)
+	forward, defender := seller.Team.Players[0], seller.Team.Players[1]
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(time.Hour)
+
+	listingId, err := repositories.Transfers.NewTransfer(forward.Id, 3000, forward.MarketValue, &expiresAt)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	openEnded, err := repositories.Transfers.NewTransfer(defender.Id, 3000, defender.MarketValue, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if listing, _ := repositories.Transfers.GetTransfer(listingId); listing.ExpiresAt == nil || !listing.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("expected the listing to carry its expiry, got %+v", listing.ExpiresAt)
+	}
+
+	if expired, err := repositories.Transfers.ExpireDueListings(now); err != nil || expired != 0 {
+		t.Fatalf("expected nothing to expire yet, got %d: %v", expired, err)
+	}
+
+	later := expiresAt.Add(time.Minute)
+
+	if err = repositories.Transfers.ConfirmTransfer(listingId, buyer.Team.Id, 4000, newContract(), later); err == nil {
+		t.Fatal("expected a listing past its expiry not to be bought")
+	}
+
+	if expired, err := repositories.Transfers.ExpireDueListings(later); err != nil || expired != 1 {
+		t.Fatalf("expected one listing to expire, got %d: %v", expired, err)
+	}
+
+	if _, err = repositories.Transfers.GetTransfer(openEnded); err != nil {
+		t.Fatalf("expected a listing without expiry to stay listed, got %v", err)
+	}
+
+	history, err := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{TeamId: seller.Team.Id, Status: domain.TransferExpired})
+
+	if err != nil || len(history) != 1 {
+		t.Fatalf("expected the expired listing in the history, got %+v: %v", history, err)
+	}
+
+	if h := history[0]; h.Id != listingId || h.Status != domain.TransferExpired || h.FromTeamId == nil || *h.FromTeamId != seller.Team.Id ||
+		h.ToTeamId != nil || h.TransferredAt != nil || h.ExpiredAt == nil || !h.ExpiredAt.Equal(expiresAt) {
+		t.Fatalf("unexpected expired history entry: %+v", h)
+	}
+
+	if transferred, _ := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{TeamId: seller.Team.Id, Status: domain.TransferTransferred}); len(transferred) != 0 {
+		t.Fatalf("expected no completed transfers, got %+v", transferred)
+	}
+
+	if _, err = repositories.Transfers.NewTransfer(forward.Id, 2000, forward.MarketValue, nil); err != nil {
+		t.Fatalf("expected an expired player to be relisted, got %v", err)
+	}
+}
+
+func testLedger(t *testing.T, repositories repository.Repositories) {
+	seller := createAccount(t, repositories, "lena", 0, domain.Forward, domain.Forward)
+	buyer := createAccount(t, repositories, "marc", 5000)
+	rival := createAccount(t, repositories, "nils", 5000)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	listed := seller.Team.Players[0]
+	transferId, err := repositories.Transfers.NewTransfer(listed.Id, 1000, listed.MarketValue, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(transferId, buyer.Team.Id, 1500, newContract(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	auctioned := seller.Team.Players[1]
+	auctionId, err := repositories.Transfers.NewAuction(auctioned.Id, 1000, auctioned.MarketValue, domain.Auction{Deadline: now.Add(time.Hour)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bid := range []struct{ teamId, amount int }{{buyer.Team.Id, 1500}, {rival.Team.Id, 2000}, {buyer.Team.Id, 2500}} {
+		if _, err = repositories.Transfers.PlaceBid(auctionId, bid.teamId, bid.amount, newTerms(), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	balances, err := repositories.Ledger.FindBalances()
+
+	if err != nil || len(balances) != 3 || balances[2].ActiveBids != 2000 || balances[2].LedgerEscrow != 2000 || balances[2].LedgerCash != 3000 {
+		t.Fatalf("expected live bids to be held in escrow, got %+v: %v", balances, err)
+	}
+
+	placed, _ := repositories.Transfers.FindBids(auctionId)
+
+	if err = repositories.Transfers.SettleAuction(auctionId, placed[0].Id, 3000, newContract(), now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	team, _ := repositories.Teams.GetTeamById(rival.Team.Id)
+	team.AvailableCash += 1000
+
+	if err = repositories.Teams.UpdateTeamById(team, now); err != nil {
+		t.Fatal(err)
+	}
+
+	balances, err = repositories.Ledger.FindBalances()
+
+	if err != nil || len(balances) != 3 {
+		t.Fatalf("expected a balance per team, got %+v: %v", balances, err)
+	}
+
+	expected := map[int]int{seller.Team.Id: 3500, buyer.Team.Id: 1500, rival.Team.Id: 6000}
+
+	for _, balance := range balances {
+		if balance.AvailableCash != expected[balance.TeamId] || balance.LedgerCash != balance.AvailableCash ||
+			balance.ActiveBids != 0 || balance.LedgerEscrow != 0 {
+			t.Fatalf("expected team %d to match the ledger at %d, got %+v", balance.TeamId, expected[balance.TeamId], balance)
+		}
+	}
+
+	transactions, err := repositories.Ledger.FindTeamTransactions(buyer.Team.Id)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	categories := make([]string, 0, len(transactions))
+
+	for _, transaction := range transactions {
+		categories = append(categories, fmt.Sprintf("%s:%d", transaction.Category, transaction.Amount))
+	}
+
+	want := []string{"OPENING_BALANCE:5000", "TRANSFER_FEE:1000", "BID_RESERVED:1500", "BID_RESERVED:1000", "BID_RELEASED:2500", "TRANSFER_FEE:2500"}
+
+	if !reflect.DeepEqual(categories, want) {
+		t.Fatalf("expected buyer ledger %v, got %v", want, categories)
+	}
+
+	if fee := transactions[5]; fee.FromAccount != domain.LedgerTeam || *fee.FromTeamId != buyer.Team.Id || fee.ToAccount != domain.LedgerTeam ||
+		*fee.ToTeamId != seller.Team.Id || fee.ReferenceType != domain.ReferenceTransfer || *fee.ReferenceId != auctionId {
+		t.Fatalf("expected the auction fee to move from buyer to seller, got %+v", fee)
+	}
+
+	if err = repositories.Teams.DeleteTeam(rival.Team.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if transactions, _ = repositories.Ledger.FindTeamTransactions(rival.Team.Id); len(transactions) != 0 {
+		t.Fatalf("expected a deleted team to be detached from the ledger, got %+v", transactions)
+	}
+}
+
+func testNegativeOpeningBalance(t *testing.T, repositories repository.Repositories) {
+	debtor := createAccount(t, repositories, "otto", -700)
+	balances, err := repositories.Ledger.FindBalances()
+
+	if err != nil || len(balances) != 1 || balances[0].TeamId != debtor.Team.Id || balances[0].LedgerCash != -700 {
+		t.Fatalf("expected a negative opening balance to be in the ledger, got %+v: %v", balances, err)
+	}
+
+	transactions, _ := repositories.Ledger.FindTeamTransactions(debtor.Team.Id)
+
+	if len(transactions) != 1 || transactions[0].Amount != 700 || transactions[0].FromAccount != domain.LedgerTeam ||
+		transactions[0].ToAccount != domain.LedgerExternal {
+		t.Fatalf("expected the opening balance to move cash out of the team, got %+v", transactions)
+	}
+}
+
+func testContracts(t *testing.T, repositories repository.Repositories) {
+	club := createAccount(t, repositories, "olga", 1000)
+	buyer := createAccount(t, repositories, "piet", 5000)
+	now := time.Now().UTC().Truncate(time.Second)
+	signed := make([]domain.Player, 0, 2)
+
+	for i, endsAt := range []time.Time{now.AddDate(1, 0, 0), now.Add(-time.Hour)} {
+		player := domain.Player{
+			FirstName:   fmt.Sprintf("Signed%d", i),
+			LastName:    "olga",
+			Country:     "Brazil",
+			Age:         25,
+			Position:    domain.Midfielder,
+			MarketValue: 1000000,
+			TeamId:      &club.Team.Id,
+			Contract:    &domain.Contract{Wage: 300, StartsAt: now.AddDate(-1, 0, 0), EndsAt: endsAt, NextWageAt: now.Add(-2 * time.Hour)},
+		}
+
+		if err := repositories.Players.NewPlayer(&player); err != nil {
+			t.Fatal(err)
+		}
+
+		if player.Contract.Id == 0 || player.Contract.PlayerId != player.Id {
+			t.Fatalf("expected the contract to be signed with the player, got %+v", player.Contract)
+		}
+
+		signed = append(signed, player)
+	}
+
+	regular, veteran := signed[0], signed[1]
+
+	if contracts, err := repositories.Contracts.FindTeamContracts(club.Team.Id); err != nil || len(contracts) != 2 {
+		t.Fatalf("expected two team contracts, got %+v: %v", contracts, err)
+	}
+
+	renewal := &domain.Contract{PlayerId: regular.Id, Wage: 400, StartsAt: now, EndsAt: now.AddDate(2, 0, 0), NextWageAt: now.Add(-time.Hour)}
+
+	if err := repositories.Contracts.RenewContract(buyer.Team.Id, renewal, now); err == nil {
+		t.Fatal("expected a contract renewal by another team to fail")
+	}
+
+	if err := repositories.Contracts.RenewContract(club.Team.Id, renewal, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if contract, err := repositories.Contracts.GetContract(regular.Id); err != nil || contract.Id != renewal.Id || contract.Wage != 400 {
+		t.Fatalf("expected the renewed contract to be active, got %+v: %v", contract, err)
+	}
+
+	due, err := repositories.Contracts.FindDueWages(now)
+
+	if err != nil || len(due) != 2 {
+		t.Fatalf("expected two wages due, got %+v: %v", due, err)
+	}
+
+	for _, contract := range due {
+		if err = repositories.Contracts.PayWage(contract, contract.NextWageAt.AddDate(0, 0, 7), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = repositories.Contracts.PayWage(due[0], due[0].NextWageAt.AddDate(0, 0, 7), now); err == nil {
+		t.Fatal("expected a wage to be paid only once")
+	}
+
+	if due, _ = repositories.Contracts.FindDueWages(now); len(due) != 0 {
+		t.Fatalf("expected no wages due after payment, got %+v", due)
+	}
+
+	team, _ := repositories.Teams.GetTeamById(club.Team.Id)
+
+	if team.AvailableCash != 300 {
+		t.Fatalf("expected wages to be deducted from the team cash, got %d", team.AvailableCash)
+	}
+
+	transactions, _ := repositories.Ledger.FindTeamTransactions(club.Team.Id)
+
+	if len(transactions) != 3 || transactions[1].Category != domain.LedgerWages || transactions[1].ReferenceType != domain.ReferenceContract ||
+		transactions[1].ToAccount != domain.LedgerExternal {
+		t.Fatalf("expected wages to be posted to the ledger, got %+v", transactions)
+	}
+
+	if _, err = repositories.Transfers.NewTransfer(veteran.Id, 1000, veteran.MarketValue, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := repositories.Contracts.FindExpiredContractIds(now)
+
+	if err != nil || !reflect.DeepEqual(expired, []int{veteran.Contract.Id}) {
+		t.Fatalf("expected the veteran contract to have expired, got %v: %v", expired, err)
+	}
+
+	if err = repositories.Contracts.ExpireContract(veteran.Contract.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Contracts.ExpireContract(veteran.Contract.Id, now); err == nil {
+		t.Fatal("expected an expired contract not to expire twice")
+	}
+
+	if player, _ := repositories.Players.GetPlayer(veteran.Id); player.TeamId != nil {
+		t.Fatalf("expected the veteran to become a free agent, got team %v", *player.TeamId)
+	}
+
+	if listed, _ := repositories.Transfers.IsPlayerListed(veteran.Id); listed {
+		t.Fatal("expected the free agent listing to expire")
+	}
+
+	if _, err = repositories.Contracts.GetContract(veteran.Id); err != sql.ErrNoRows {
+		t.Fatalf("expected a free agent to have no active contract, got %v", err)
+	}
+
+	transferId, err := repositories.Transfers.NewTransfer(regular.Id, 1000, regular.MarketValue, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Transfers.ConfirmTransfer(transferId, buyer.Team.Id, 1500, newContract(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	if contract, err := repositories.Contracts.GetContract(regular.Id); err != nil || contract.Wage != 100 {
+		t.Fatalf("expected the transfer contract to replace the renewal, got %+v: %v", contract, err)
+	}
+
+	if contracts, _ := repositories.Contracts.FindTeamContracts(club.Team.Id); len(contracts) != 0 {
+		t.Fatalf("expected no contracts left at the selling team, got %+v", contracts)
+	}
+
+	if contracts, _ := repositories.Contracts.FindTeamContracts(buyer.Team.Id); len(contracts) != 1 || contracts[0].PlayerId != regular.Id {
+		t.Fatalf("expected the buyer to hold the new contract, got %+v", contracts)
+	}
+}
+
+func testReleaseClause(t *testing.T, repositories repository.Repositories) {
+	club := createAccount(t, repositories, "ruth", 0)
+	buyer := createAccount(t, repositories, "saul", 5000)
+	poor := createAccount(t, repositories, "tina", 1000)
+	now := time.Now().UTC().Truncate(time.Second)
+	clause := 2000
+	player := domain.Player{
+		FirstName:   "Clause",
+		LastName:    "ruth",
+		Country:     "Brazil",
+		Age:         24,
+		Position:    domain.Forward,
+		MarketValue: 1500,
+		TeamId:      &club.Team.Id,
+		Contract:    &domain.Contract{Wage: 300, ReleaseClause: &clause, StartsAt: now, EndsAt: now.AddDate(2, 0, 0), NextWageAt: now.AddDate(0, 0, 7)},
+	}
+
+	if err := repositories.Players.NewPlayer(&player); err != nil {
+		t.Fatal(err)
+	}
+
+	offer := createOffer(t, repositories, player, poor.Team.Id, 500, now)
+	listingId, _ := repositories.Transfers.NewTransfer(player.Id, 9000, player.MarketValue, nil)
+
+	for _, attempt := range []struct {
+		buyerId, releaseClause int
+		reason                 string
+	}{
+		{club.Team.Id, clause, "the owning team"},
+		{buyer.Team.Id, 1500, "a stale release clause"},
+		{poor.Team.Id, clause, "a team without the funds"},
+	} {
+		contract := newContract()
+		contract.PlayerId = player.Id
+
+		if err := repositories.Contracts.PayReleaseClause(attempt.buyerId, attempt.releaseClause, 3000, contract, now); err == nil {
+			t.Fatalf("expected %s not to pay the release clause", attempt.reason)
+		}
+	}
+
+	contract := newContract()
+	contract.PlayerId = player.Id
+
+	if err := repositories.Contracts.PayReleaseClause(buyer.Team.Id, clause, 3000, contract, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if moved, _ := repositories.Players.GetPlayer(player.Id); moved.TeamId == nil || *moved.TeamId != buyer.Team.Id || moved.MarketValue != 3000 {
+		t.Fatalf("expected the player to join the buying team, got %+v", moved)
+	}
+
+	if current, err := repositories.Contracts.GetContract(player.Id); err != nil || current.Id != contract.Id {
+		t.Fatalf("expected the buyer's contract to replace the old one, got %+v: %v", current, err)
+	}
+
+	clubTeam, _ := repositories.Teams.GetTeamById(club.Team.Id)
+	buyerTeam, _ := repositories.Teams.GetTeamById(buyer.Team.Id)
+
+	if clubTeam.AvailableCash != clause || buyerTeam.AvailableCash != 5000-clause {
+		t.Fatalf("expected the release clause to move %d between the teams, got %d and %d", clause, clubTeam.AvailableCash, buyerTeam.AvailableCash)
+	}
+
+	if cancelled, _ := repositories.Offers.GetOffer(offer.Id); cancelled.Status != domain.OfferCancelled {
+		t.Fatalf("expected open offers to be cancelled, got %s", cancelled.Status)
+	}
+
+	if _, err := repositories.Transfers.GetTransfer(listingId); err != sql.ErrNoRows {
+		t.Fatalf("expected the listing to be withdrawn, got %v", err)
+	}
+
+	history, _ := repositories.Transfers.FindCompletedTransfers(domain.TransferHistoryFilter{PlayerId: player.Id, Status: domain.TransferTransferred})
+
+	if len(history) != 1 || history[0].Price != clause || history[0].ToTeamId == nil || *history[0].ToTeamId != buyer.Team.Id {
+		t.Fatalf("expected the buy-out in the transfer history, got %+v", history)
+	}
+}
+
+func testReleasePlayer(t *testing.T, repositories repository.Repositories) {
+	club := createAccount(t, repositories, "uma", 0, domain.Forward)
+	rival := createAccount(t, repositories, "vlad", 5000, domain.Defender)
+	now := time.Now().UTC().Truncate(time.Second)
+	players, _ := repositories.Players.GetPlayersByTeamId(club.Team.Id)
+	rivals, _ := repositories.Players.GetPlayersByTeamId(rival.Team.Id)
+	player := players[0]
+
+	offer := createOffer(t, repositories, player, rival.Team.Id, 500, now)
+	listingId, _ := repositories.Transfers.NewTransfer(player.Id, 9000, player.MarketValue, nil)
+	swap := &domain.Swap{ProposerId: rival.Team.Id, ReceiverId: club.Team.Id, ProposedAt: now, Players: []domain.SwapPlayer{
+		{PlayerId: player.Id, FromTeamId: club.Team.Id},
+		{PlayerId: rivals[0].Id, FromTeamId: rival.Team.Id},
+	}}
+
+	if err := repositories.Swaps.CreateSwap(swap); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repositories.Contracts.ReleasePlayer(rival.Team.Id, player.Id, now); err == nil {
+		t.Fatal("expected only the owning team to release a player")
+	}
+
+	if err := repositories.Contracts.ReleasePlayer(club.Team.Id, player.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if released, _ := repositories.Players.GetPlayer(player.Id); released.TeamId != nil {
+		t.Fatalf("expected the released player to be a free agent, got %+v", released)
+	}
+
+	if _, err := repositories.Contracts.GetContract(player.Id); err != sql.ErrNoRows {
+		t.Fatalf("expected the contract to be terminated, got %v", err)
+	}
+
+	if cancelled, _ := repositories.Offers.GetOffer(offer.Id); cancelled.Status != domain.OfferCancelled {
+		t.Fatalf("expected open offers to be cancelled, got %s", cancelled.Status)
+	}
+
+	if _, err := repositories.Transfers.GetTransfer(listingId); err != sql.ErrNoRows {
+		t.Fatalf("expected the listing to be withdrawn, got %v", err)
+	}
+
+	if cancelled, _ := repositories.Swaps.GetSwap(swap.Id); cancelled.Status != domain.SwapCancelled {
+		t.Fatalf("expected proposed swaps to be cancelled, got %s", cancelled.Status)
+	}
+
+	if err := repositories.Contracts.ReleasePlayer(club.Team.Id, player.Id, now); err == nil {
+		t.Fatal("expected a free agent not to be released again")
+	}
+}
+
+func testFreeAgents(t *testing.T, repositories repository.Repositories) {
+	club := createAccount(t, repositories, "quin", 150000, domain.Forward)
+	poor := createAccount(t, repositories, "rita", 1000)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for i, position := range []domain.PlayerPosition{domain.Defender, domain.Forward, domain.Forward} {
+		player := domain.Player{
+			FirstName:   fmt.Sprintf("Free%d", i),
+			LastName:    "Agent",
+			Country:     "Chile",
+			Age:         uint8(20 + i*5),
+			Position:    position,
+			MarketValue: 1000000 + i*500000,
+		}
+
+		if err := repositories.Players.NewPlayer(&player); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	freeAgents, err := repositories.Players.FindFreeAgents(domain.FreeAgentFilter{Limit: 10})
+
+	if err != nil || len(freeAgents) != 3 || freeAgents[0].FirstName != "Free2" || freeAgents[2].FirstName != "Free0" {
+		t.Fatalf("expected the free agents by descending market value, got %+v: %v", freeAgents, err)
+	}
+
+	filters := map[string]domain.FreeAgentFilter{
+		"position": {Position: domain.Forward, Limit: 10},
+		"age":      {MinAge: 22, MaxAge: 40, Limit: 10},
+		"value":    {MinMarketValue: 1200000, Limit: 10},
+		"name":     {PlayerName: "free", Country: "chile", MaxMarketValue: 2000000, Limit: 2},
+	}
+
+	for name, filter := range filters {
+		if freeAgents, err = repositories.Players.FindFreeAgents(filter); err != nil || len(freeAgents) != 2 {
+			t.Fatalf("expected the %s filter to match two free agents, got %+v: %v", name, freeAgents, err)
+		}
+	}
+
+	signed := freeAgents[0]
+	contract := newContract()
+	contract.PlayerId = signed.Id
+
+	if err = repositories.Contracts.SignFreeAgent(poor.Team.Id, contract, 100000, now); err == nil {
+		t.Fatal("expected a team without enough cash not to sign the free agent")
+	}
+
+	if err = repositories.Contracts.SignFreeAgent(club.Team.Id, contract, 100000, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Contracts.SignFreeAgent(poor.Team.Id, &domain.Contract{PlayerId: signed.Id}, 0, now); err == nil {
+		t.Fatal("expected a signed player not to be signed again")
+	}
+
+	if player, _ := repositories.Players.GetPlayer(signed.Id); player.TeamId == nil || *player.TeamId != club.Team.Id {
+		t.Fatalf("expected the free agent to join the team, got %+v", player)
+	}
+
+	if current, err := repositories.Contracts.GetContract(signed.Id); err != nil || current.Id != contract.Id {
+		t.Fatalf("expected the signing contract to be active, got %+v: %v", current, err)
+	}
+
+	team, _ := repositories.Teams.GetTeamById(club.Team.Id)
+
+	if team.AvailableCash != 50000 {
+		t.Fatalf("expected the signing fee to be deducted, got %d", team.AvailableCash)
+	}
+
+	transactions, _ := repositories.Ledger.FindTeamTransactions(club.Team.Id)
+
+	if last := transactions[len(transactions)-1]; last.Category != domain.LedgerSigningFee || last.Amount != 100000 ||
+		last.ToAccount != domain.LedgerExternal || *last.ReferenceId != contract.Id {
+		t.Fatalf("expected the signing fee to be posted to the ledger, got %+v", last)
+	}
+
+	if freeAgents, _ = repositories.Players.FindFreeAgents(domain.FreeAgentFilter{Limit: 10}); len(freeAgents) != 2 {
+		t.Fatalf("expected the signed player to leave the pool, got %+v", freeAgents)
+	}
+
+	if err = repositories.Teams.DeleteTeam(club.Team.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if freeAgents, _ = repositories.Players.FindFreeAgents(domain.FreeAgentFilter{Limit: 10}); len(freeAgents) != 4 {
+		t.Fatalf("expected the players of a deleted team to join the pool, got %+v", freeAgents)
+	}
+}
+
+func testAccountTokens(t *testing.T, repositories repository.Repositories) {
+	account := createAccount(t, repositories, "liam", 0)
+	now := time.Now()
+	tokens := []*domain.AccountToken{
+		{AccountId: account.Id, Purpose: domain.PasswordResetPurpose, Token: "valid", ExpiresAt: now.Add(time.Hour)},
+		{AccountId: account.Id, Purpose: domain.PasswordResetPurpose, Token: "expired", ExpiresAt: now.Add(-time.Hour)},
+		{AccountId: account.Id, Purpose: domain.PasswordResetPurpose, Token: "revoked", ExpiresAt: now.Add(time.Hour)},
+	}
+
+	for _, token := range tokens {
+		if err := repositories.AccountTokens.CreateToken(token); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := repositories.AccountTokens.UseToken(domain.UnlockAccountPurpose, "valid", now); err == nil {
+		t.Fatal("expected token to be bound to its purpose")
+	}
+
+	if accountId, err := repositories.AccountTokens.UseToken(domain.PasswordResetPurpose, "valid", now); err != nil || accountId != account.Id {
+		t.Fatalf("expected token to be used: %d, %v", accountId, err)
+	}
+
+	if _, err := repositories.AccountTokens.UseToken(domain.PasswordResetPurpose, "valid", now); err == nil {
+		t.Fatal("expected token to be single use")
+	}
+
+	if _, err := repositories.AccountTokens.UseToken(domain.PasswordResetPurpose, "expired", now); err == nil {
+		t.Fatal("expected expired token to be rejected")
+	}
+
+	if err := repositories.AccountTokens.RevokeTokens(account.Id, domain.PasswordResetPurpose, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repositories.AccountTokens.UseToken(domain.PasswordResetPurpose, "revoked", now); err == nil {
+		t.Fatal("expected revoked token to be rejected")
+	}
+}
+
+func testSessions(t *testing.T, repositories repository.Repositories) {
+	account := createAccount(t, repositories, "mona", 0)
+	now := time.Now()
+	newToken := func(token, family, accessTokenId string) {
+		err := repositories.Sessions.CreateRefreshToken(&domain.RefreshToken{
+			AccountId:       account.Id,
+			Family:          family,
+			Token:           token,
+			AccessTokenId:   accessTokenId,
+			AccessExpiresAt: now.Add(time.Hour),
+			ExpiresAt:       now.Add(24 * time.Hour),
+			CreatedAt:       now,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newToken("refresh-1", "family-a", "access-1")
+	newToken("refresh-2", "family-b", "access-2")
+
+	refreshToken, consumed, err := repositories.Sessions.UseRefreshToken("refresh-1", now)
+
+	if err != nil || !consumed || refreshToken.Family != "family-a" || refreshToken.AccessTokenId != "access-1" {
+		t.Fatalf("expected refresh token to be consumed: %+v, %v", refreshToken, err)
+	}
+
+	refreshToken, consumed, err = repositories.Sessions.UseRefreshToken("refresh-1", now)
+
+	if err != nil || consumed || refreshToken.UsedAt == nil {
+		t.Fatalf("expected reused refresh token to be reported as used: %+v, %v", refreshToken, err)
+	}
+
+	if _, _, err = repositories.Sessions.UseRefreshToken("unknown", now); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows for unknown refresh token, got %v", err)
+	}
+
+	if err = repositories.Sessions.RevokeFamily("family-a", now); err != nil {
+		t.Fatal(err)
+	}
+
+	if revoked, _ := repositories.Sessions.IsAccessTokenRevoked("access-1"); !revoked {
+		t.Fatal("expected access token of revoked family to be revoked")
+	}
+
+	if revoked, _ := repositories.Sessions.IsAccessTokenRevoked("access-2"); revoked {
+		t.Fatal("expected access token of other family to remain valid")
+	}
+
+	if err = repositories.Sessions.RevokeSession("access-2", account.Id, now.Add(time.Hour), now); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, consumed, _ = repositories.Sessions.UseRefreshToken("refresh-2", now); consumed {
+		t.Fatal("expected refresh token of logged out session to be revoked")
+	}
+
+	if revoked, _ := repositories.Sessions.IsAccessTokenRevoked("access-2"); !revoked {
+		t.Fatal("expected logged out access token to be revoked")
+	}
+}
+
+func testMatches(t *testing.T, repositories repository.Repositories) {
+	positions := []domain.PlayerPosition{domain.GoalKeeper, domain.Defender, domain.Defender, domain.Defender, domain.Defender,
+		domain.Midfielder, domain.Midfielder, domain.Midfielder, domain.Midfielder, domain.Forward, domain.Forward, domain.Forward}
+	home := createAccount(t, repositories, "nina", 0, positions...)
+	away := createAccount(t, repositories, "omar", 0, positions...)
+
+	played, err := match.Simulate(*home.Team, *away.Team, 7)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	played.PlayedAt = time.Now().Truncate(time.Second)
+
+	if err = repositories.Matches.CreateMatch(played); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := repositories.Matches.GetMatch(played.Id)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.HomeGoals != played.HomeGoals || stored.AwayGoals != played.AwayGoals || stored.Seed != 7 ||
+		stored.HomeTeamName != "nina FC" || stored.AwayTeamName != "omar FC" || !stored.PlayedAt.Equal(played.PlayedAt) {
+		t.Fatalf("unexpected stored match: %+v", stored)
+	}
+
+	if !reflect.DeepEqual(stored.Events, played.Events) {
+		t.Fatalf("expected events %+v, got %+v", played.Events, stored.Events)
+	}
+
+	ratings := map[int]domain.PlayerRating{}
+
+	for _, rating := range stored.Ratings {
+		ratings[rating.PlayerId] = rating
+	}
+
+	for _, rating := range played.Ratings {
+		if ratings[rating.PlayerId] != rating {
+			t.Fatalf("expected rating %+v, got %+v", rating, ratings[rating.PlayerId])
+		}
+	}
+
+	matches, err := repositories.Matches.FindMatchesByTeamId(away.Team.Id)
+
+	if err != nil || len(matches) != 1 || matches[0].Id != played.Id {
+		t.Fatalf("expected the match in the team history, got %+v: %v", matches, err)
+	}
+
+	if err = repositories.Teams.DeleteTeam(home.Team.Id, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored, err = repositories.Matches.GetMatch(played.Id); err != nil || stored.HomeTeamId != nil || stored.HomeTeamName != "" ||
+		*stored.AwayTeamId != away.Team.Id || stored.AwayTeamName != "omar FC" || len(stored.Events) != len(played.Events) {
+		t.Fatalf("expected the match to outlive the deleted team, got %+v: %v", stored, err)
+	}
+
+	if matches, err = repositories.Matches.FindMatchesByTeamId(away.Team.Id); err != nil || len(matches) != 1 {
+		t.Fatalf("expected the match to stay in the remaining team's history, got %+v: %v", matches, err)
+	}
+}
+
+func testLeagues(t *testing.T, repositories repository.Repositories) {
+	positions := []domain.PlayerPosition{domain.GoalKeeper, domain.Defender, domain.Defender, domain.Defender, domain.Defender,
+		domain.Midfielder, domain.Midfielder, domain.Midfielder, domain.Midfielder, domain.Forward, domain.Forward}
+	var teams []*domain.Team
+
+	for _, username := range []string{"paul", "quinn", "rosa"} {
+		teams = append(teams, createAccount(t, repositories, username, 0, positions...).Team)
+	}
+
+	l := &domain.League{Name: "Serie A", Country: "Brazil", Status: domain.LeagueOpen, Seed: 11, CreatedAt: time.Now().Truncate(time.Second)}
+
+	if err := repositories.Leagues.CreateLeague(l); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+
+	for i, team := range teams {
+		if err := repositories.Leagues.EnrolTeam(l.Id, team.Id, now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := repositories.Leagues.EnrolTeam(l.Id, teams[0].Id, now); err == nil {
+		t.Fatal("expected a team to be enrolled only once")
+	}
+
+	teamIds, err := repositories.Leagues.FindLeagueTeamIds(l.Id)
+
+	if err != nil || !reflect.DeepEqual(teamIds, []int{teams[0].Id, teams[1].Id, teams[2].Id}) {
+		t.Fatalf("expected teams in enrolment order, got %v: %v", teamIds, err)
+	}
+
+	rounds := league.DoubleRoundRobin(teamIds)
+	var fixtures []domain.Fixture
+
+	for i, pairings := range rounds {
+		for _, pairing := range pairings {
+			fixtures = append(fixtures, domain.Fixture{Round: i + 1, HomeTeamId: pairing.HomeTeamId, AwayTeamId: pairing.AwayTeamId})
+		}
+	}
+
+	if err = repositories.Leagues.StartLeague(l.Id, len(rounds), fixtures); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Leagues.StartLeague(l.Id, len(rounds), fixtures); err == nil {
+		t.Fatal("expected a running league not to start again")
+	}
+
+	if err = repositories.Leagues.EnrolTeam(l.Id, createAccount(t, repositories, "sam", 0).Team.Id, now); err == nil {
+		t.Fatal("expected enrolment to be closed once the league started")
+	}
+
+	stored, err := repositories.Leagues.FindFixtures(l.Id, 0)
+
+	if err != nil || len(stored) != len(fixtures) {
+		t.Fatalf("expected %d fixtures, got %d: %v", len(fixtures), len(stored), err)
+	}
+
+	round, err := repositories.Leagues.FindFixtures(l.Id, 1)
+
+	if err != nil || len(round) != 1 || round[0].MatchId != nil || round[0].HomeTeamName == "" {
+		t.Fatalf("expected one unplayed fixture in the first round, got %+v: %v", round, err)
+	}
+
+	byId := map[int]*domain.Team{}
+
+	for _, team := range teams {
+		team.Players, _ = repositories.Players.GetPlayersByTeamId(team.Id)
+		byId[team.Id] = team
+	}
+
+	round[0].Match, err = match.Simulate(*byId[round[0].HomeTeamId], *byId[round[0].AwayTeamId], 3)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	round[0].Match.PlayedAt = time.Now().Truncate(time.Second)
+
+	if err = repositories.Leagues.RecordRound(l.Id, 1, round, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Leagues.RecordRound(l.Id, 1, round, false); err == nil {
+		t.Fatal("expected a round to be recorded only once")
+	}
+
+	played, err := repositories.Leagues.FindFixtures(l.Id, 1)
+
+	if err != nil || played[0].MatchId == nil || *played[0].MatchId != round[0].Match.Id ||
+		*played[0].HomeGoals != round[0].Match.HomeGoals || *played[0].AwayGoals != round[0].Match.AwayGoals {
+		t.Fatalf("expected the fixture result to be recorded, got %+v: %v", played, err)
+	}
+
+	current, err := repositories.Leagues.GetLeague(l.Id)
+
+	if err != nil || current.CurrentRound != 1 || current.Status != domain.LeagueRunning || current.Rounds != len(rounds) {
+		t.Fatalf("unexpected league after the first round: %+v: %v", current, err)
+	}
+
+	standings, err := repositories.Leagues.FindStandings(l.Id)
+
+	if err != nil || len(standings) != 3 {
+		t.Fatalf("expected three standings, got %+v: %v", standings, err)
+	}
+
+	home, away := round[0].Match.HomeGoals, round[0].Match.AwayGoals
+	totalPoints := 0
+
+	for i, standing := range standings {
+		if standing.Position != i+1 {
+			t.Fatalf("expected position %d, got %+v", i+1, standing)
+		}
+
+		totalPoints += standing.Points
+
+		if standing.TeamId == round[0].HomeTeamId && (standing.Played != 1 || standing.GoalsFor != home || standing.GoalsAgainst != away ||
+			standing.GoalDifference != home-away || len(standing.Form) != 1) {
+			t.Fatalf("unexpected home standing: %+v", standing)
+		}
+	}
+
+	expectedPoints := 3
+
+	if home == away {
+		expectedPoints = 2
+	}
+
+	if totalPoints != expectedPoints {
+		t.Fatalf("unexpected total points %d for a %d-%d result", totalPoints, home, away)
+	}
+
+	deleted := round[0].HomeTeamId
+
+	if err = repositories.Teams.DeleteTeam(deleted, time.Now()); err != repository.ErrTeamInRunningLeague {
+		t.Fatalf("expected a team of a running league to be kept, got %v", err)
+	}
+
+	if standings, _ = repositories.Leagues.FindStandings(l.Id); len(standings) != 3 {
+		t.Fatalf("expected the team to stay in the standings, got %+v", standings)
+	}
+
+	for r := 2; r <= len(rounds); r++ {
+		if round, err = repositories.Leagues.FindFixtures(l.Id, r); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range round {
+			round[i].Match = match.Forfeit(domain.Team{Id: round[i].HomeTeamId}, *byId[round[i].AwayTeamId], int64(r))
+			round[i].Match.PlayedAt = time.Now().Truncate(time.Second)
+		}
+
+		if err = repositories.Leagues.RecordRound(l.Id, r, round, r == len(rounds)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if current, err = repositories.Leagues.GetLeague(l.Id); err != nil || current.Status != domain.LeagueFinished {
+		t.Fatalf("expected the league to finish, got %+v: %v", current, err)
+	}
+
+	if err = repositories.Teams.DeleteTeam(deleted, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if standings, _ = repositories.Leagues.FindStandings(l.Id); len(standings) != 2 {
+		t.Fatalf("expected deleted teams to leave the standings, got %+v", standings)
+	}
+
+	if stored, _ = repositories.Leagues.FindFixtures(l.Id, 0); len(stored) != 2 {
+		t.Fatalf("expected the fixtures of deleted teams to be removed, got %+v", stored)
+	}
+}
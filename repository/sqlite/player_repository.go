@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository/mysql"
+)
+
+type PlayerRepository struct {
+	*mysql.PlayerRepository
+	db *sql.DB
+}
+
+func NewPlayerRepository(db *sql.DB) *PlayerRepository {
+	return &PlayerRepository{
+		PlayerRepository: mysql.NewPlayerRepository(db),
+		db:               db,
+	}
+}
+
+func (pr *PlayerRepository) UpdatePlayer(accountId int, player *domain.Player) error {
+	_, err :=
+		pr.db.Exec("UPDATE player SET first_name = ?, last_name = ?, country = ? "+
+			"WHERE id = ? AND team_id IN (SELECT id FROM team WHERE account_id = ?)",
+			player.FirstName, player.LastName, player.Country, player.Id, accountId)
+
+	return err
+}
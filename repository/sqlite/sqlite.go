@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/repository/mysql"
+)
+
+var (
+	_ repository.PlayerRepository   = (*PlayerRepository)(nil)
+	_ repository.TransferRepository = (*TransferRepository)(nil)
+)
+
+func NewRepositories(db *sql.DB) repository.Repositories {
+	playerRepository := NewPlayerRepository(db)
+	teamRepository := mysql.NewTeamRepository(db)
+	matchRepository := mysql.NewMatchRepository(db)
+
+	return repository.Repositories{
+		Players:       playerRepository,
+		Teams:         teamRepository,
+		Accounts:      mysql.NewAccountRepository(db, teamRepository, playerRepository.PlayerRepository),
+		Transfers:     NewTransferRepository(db),
+		AccountTokens: mysql.NewAccountTokenRepository(db),
+		Sessions:      mysql.NewSessionRepository(db),
+		Matches:       matchRepository,
+		Leagues:       mysql.NewLeagueRepository(db, matchRepository),
+		Offers:        mysql.NewOfferRepository(db),
+		Loans:         mysql.NewLoanRepository(db),
+		Swaps:         mysql.NewSwapRepository(db),
+		Windows:       mysql.NewTransferWindowRepository(db),
+		Ledger:        mysql.NewLedgerRepository(db),
+		Contracts:     mysql.NewContractRepository(db),
+	}
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/migration"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/repository/repositorytest"
+	_ "github.com/mattn/go-sqlite3"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openDatabase(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on&_busy_timeout=5000")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := migration.NewMigrator(db, "sqlite")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = migrator.Up(0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.Repositories {
+		return NewRepositories(openDatabase(t))
+	})
+}
+
+func TestDeletedBidderReleasesEscrow(t *testing.T) {
+	db := openDatabase(t)
+	repositories := NewRepositories(db)
+	now := time.Now().UTC().Truncate(time.Second)
+	var teams []*domain.Team
+
+	for _, username := range []string{"seller", "bidder"} {
+		account := &domain.Account{
+			Username: username + "@example.com",
+			Password: "hash",
+			Profile:  domain.UserProfile,
+			Team: &domain.Team{Name: username, AvailableCash: 5000, Players: []domain.Player{
+				{FirstName: "Test", LastName: username, Age: 25, Position: domain.Forward, MarketValue: 1000000},
+			}},
+		}
+
+		if err := repositories.Accounts.CreateAccount(account, now); err != nil {
+			t.Fatal(err)
+		}
+
+		teams = append(teams, account.Team)
+	}
+
+	seller, bidder := teams[0], teams[1]
+	auctionId, err := repositories.Transfers.NewAuction(seller.Players[0].Id, 1000, 1000000, domain.Auction{Deadline: now.Add(time.Hour)})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repositories.Transfers.PlaceBid(auctionId, bidder.Id, 2000, domain.ContractTerms{Wage: 100, Years: 1}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = repositories.Teams.DeleteTeam(bidder.Id, now); err != nil {
+		t.Fatal(err)
+	}
+
+	var escrow int
+	err = db.QueryRow("SELECT COALESCE(SUM(CASE WHEN to_account = ? THEN amount ELSE 0 END), 0) - "+
+		"COALESCE(SUM(CASE WHEN from_account = ? THEN amount ELSE 0 END), 0) FROM ledger_transaction",
+		domain.LedgerEscrow, domain.LedgerEscrow).Scan(&escrow)
+
+	if err != nil || escrow != 0 {
+		t.Fatalf("expected the deleted bidder's escrow to be released, got %d: %v", escrow, err)
+	}
+
+	if listing, err := repositories.Transfers.GetTransfer(auctionId); err != nil || listing.Auction == nil || listing.Auction.HighestBid != nil {
+		t.Fatalf("expected the auction to drop the deleted bidder's bid, got %+v: %v", listing.Auction, err)
+	}
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"database/sql"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository/mysql"
+)
+
+type TransferRepository struct {
+	*mysql.TransferRepository
+	db *sql.DB
+}
+
+func NewTransferRepository(db *sql.DB) *TransferRepository {
+	return &TransferRepository{
+		TransferRepository: mysql.NewTransferRepository(db),
+		db:                 db,
+	}
+}
+
+func (tr *TransferRepository) UpdateTransfer(accountId int, transfer *domain.Transfer) error {
+	_, err :=
+		tr.db.Exec("UPDATE transfer_list SET asked_price = ? "+
+			"WHERE id = ? AND status = ? AND mode = ? AND player_id IN (SELECT p.id FROM player p JOIN team t ON t.id = p.team_id WHERE t.account_id = ?)",
+			transfer.AskedPrice, transfer.Id, domain.TransferListed, domain.TransferFixedPrice, accountId)
+
+	return err
+}
@@ -6,34 +6,54 @@ import (
 	"errors"
 	"github.com/dgrijalva/jwt-go"
 	_ "github.com/dgrijalva/jwt-go"
+	"github.com/giancarlobastos/soccer-manager-api/config"
 	"github.com/giancarlobastos/soccer-manager-api/domain"
 	"github.com/giancarlobastos/soccer-manager-api/service"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"golang.org/x/crypto/bcrypt"
 	"net/http"
-	"strconv"
 	"time"
 )
 
-var jwtKey = []byte("you should get it from OS environment!")
-
 type AuthenticationRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
 type AuthenticationResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type Claims struct {
+	jwt.StandardClaims
+	AccountId    int    `json:"aid"`
+	Profile      string `json:"profile"`
+	TokenVersion int    `json:"ver"`
 }
 
 type AuthenticationMiddleware struct {
 	accountService  *service.AccountService
+	sessionService  *service.SessionService
+	jwtKey          []byte
+	issuer          string
+	accessTokenTTL  time.Duration
 	routeProfileMap map[string]string
 }
 
-func NewAuthenticationMiddleware(as *service.AccountService, m map[string]string) *AuthenticationMiddleware {
+func NewAuthenticationMiddleware(as *service.AccountService, ss *service.SessionService, cfg config.JWTConfig, m map[string]string) *AuthenticationMiddleware {
 	return &AuthenticationMiddleware{
 		accountService:  as,
+		sessionService:  ss,
+		jwtKey:          []byte(cfg.Key),
+		issuer:          cfg.Issuer,
+		accessTokenTTL:  cfg.TTL.Duration,
 		routeProfileMap: m,
 	}
 }
@@ -42,14 +62,20 @@ func (amw *AuthenticationMiddleware) Middleware(next http.Handler) http.Handler
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		route := mux.CurrentRoute(r)
 		if route != nil {
-			if role, ok := amw.routeProfileMap[route.GetName()]; ok && role == "USER" {
+			if profile, ok := amw.routeProfileMap[route.GetName()]; ok {
 				if claims, err := amw.getClaims(w, r); err != nil {
 					http.Error(w, err.Error(), http.StatusForbidden)
+				} else if account, err := amw.getSessionAccount(claims); err != nil {
+					http.Error(w, err.Error(), http.StatusUnauthorized)
+				} else if !hasProfile(account.Profile, profile) {
+					http.Error(w, "insufficient privileges", http.StatusForbidden)
 				} else {
-					accountId, _ := strconv.Atoi(claims.Id)
 					user := domain.User{
-						AccountId: accountId,
-						Username:  claims.Subject,
+						AccountId:      account.Id,
+						Username:       account.Username,
+						Profile:        account.Profile,
+						TokenId:        claims.Id,
+						TokenExpiresAt: time.Unix(claims.ExpiresAt, 0),
 					}
 
 					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "user", user)))
@@ -73,7 +99,7 @@ func (amw *AuthenticationMiddleware) Authenticate(w http.ResponseWriter, r *http
 		return
 	}
 
-	token, err := amw.generateToken(authenticationRequest.Username, authenticationRequest.Password)
+	authenticationResponse, err := amw.generateToken(authenticationRequest.Username, authenticationRequest.Password)
 
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -81,13 +107,64 @@ func (amw *AuthenticationMiddleware) Authenticate(w http.ResponseWriter, r *http
 		return
 	}
 
-	response, _ := json.Marshal(AuthenticationResponse{Token: *token})
+	response, _ := json.Marshal(authenticationResponse)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Write(response)
 }
 
-func (amw *AuthenticationMiddleware) getClaims(w http.ResponseWriter, r *http.Request) (*jwt.StandardClaims, error) {
+func (amw *AuthenticationMiddleware) Refresh(w http.ResponseWriter, r *http.Request) {
+	var refreshRequest RefreshRequest
+
+	err := json.NewDecoder(r.Body).Decode(&refreshRequest)
+
+	if err != nil || len(refreshRequest.RefreshToken) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	authenticationResponse, err := amw.refreshToken(refreshRequest.RefreshToken)
+
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	response, _ := json.Marshal(authenticationResponse)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(response)
+}
+
+func (amw *AuthenticationMiddleware) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := amw.sessionService.RevokeSession(amw.GetPrincipal(r)); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (amw *AuthenticationMiddleware) getSessionAccount(claims *Claims) (*domain.Account, error) {
+	if amw.sessionService.IsRevoked(claims.Id) {
+		return nil, errors.New("session revoked")
+	}
+
+	account, err := amw.accountService.GetAccountById(claims.AccountId)
+
+	if err != nil || account.Locked || account.TokenVersion != claims.TokenVersion {
+		return nil, errors.New("session expired")
+	}
+
+	return account, nil
+}
+
+func hasProfile(granted, required string) bool {
+	return granted == required || granted == domain.AdminProfile
+}
+
+func (amw *AuthenticationMiddleware) getClaims(w http.ResponseWriter, r *http.Request) (*Claims, error) {
 	bearerToken := r.Header.Get("Authorization")
 
 	if len(bearerToken) < 8 {
@@ -96,17 +173,21 @@ func (amw *AuthenticationMiddleware) getClaims(w http.ResponseWriter, r *http.Re
 	}
 
 	bearerToken = bearerToken[7:]
-	claims := &jwt.StandardClaims{}
+	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(bearerToken, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, errors.New("unexpected signing method")
+		}
+
+		return amw.jwtKey, nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	if !token.Valid {
+	if !token.Valid || !claims.VerifyIssuer(amw.issuer, amw.issuer != "") {
 		w.WriteHeader(http.StatusUnauthorized)
 		return nil, errors.New("unauthorized")
 	}
@@ -114,7 +195,7 @@ func (amw *AuthenticationMiddleware) getClaims(w http.ResponseWriter, r *http.Re
 	return claims, nil
 }
 
-func (amw *AuthenticationMiddleware) generateToken(username, password string) (*string, error) {
+func (amw *AuthenticationMiddleware) generateToken(username, password string) (*AuthenticationResponse, error) {
 	account, err := amw.accountService.GetAccountByUsername(username)
 
 	if err != nil {
@@ -125,30 +206,70 @@ func (amw *AuthenticationMiddleware) generateToken(username, password string) (*
 		return nil, errors.New("account locked")
 	}
 
-	expiresAt := time.Now().Add(time.Hour).Unix()
-
 	err = bcrypt.CompareHashAndPassword([]byte(account.Password), []byte(password))
 
-	if err != nil && err == bcrypt.ErrMismatchedHashAndPassword {
+	if err == bcrypt.ErrMismatchedHashAndPassword {
 		_ = amw.accountService.RegisterFailedLoginAttempt(username)
 		return nil, errors.New("password mismatch")
+	} else if err != nil {
+		return nil, err
 	}
 
 	_ = amw.accountService.ResetLoginAttempts(username)
+	return amw.issueTokens(account, "")
+}
+
+func (amw *AuthenticationMiddleware) refreshToken(token string) (*AuthenticationResponse, error) {
+	refreshToken, err := amw.sessionService.ConsumeRefreshToken(token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := amw.accountService.GetAccountById(refreshToken.AccountId)
+
+	if err != nil || !account.Confirmed || account.Locked {
+		return nil, service.ErrInvalidRefreshToken
+	}
+
+	return amw.issueTokens(account, refreshToken.Family)
+}
+
+func (amw *AuthenticationMiddleware) issueTokens(account *domain.Account, family string) (*AuthenticationResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(amw.accessTokenTTL)
+	tokenId := uuid.New().String()
 	token := jwt.NewWithClaims(jwt.GetSigningMethod("HS256"),
-		&jwt.StandardClaims{
-			Id:        strconv.Itoa(account.Id),
-			Subject:   account.Username,
-			ExpiresAt: expiresAt,
+		&Claims{
+			StandardClaims: jwt.StandardClaims{
+				Id:        tokenId,
+				Issuer:    amw.issuer,
+				Subject:   account.Username,
+				IssuedAt:  now.Unix(),
+				ExpiresAt: expiresAt.Unix(),
+			},
+			AccountId:    account.Id,
+			Profile:      account.Profile,
+			TokenVersion: account.TokenVersion,
 		})
 
-	tokenString, err := token.SignedString(jwtKey)
+	tokenString, err := token.SignedString(amw.jwtKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := amw.sessionService.IssueRefreshToken(account.Id, family, tokenId, expiresAt)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &tokenString, nil
+	return &AuthenticationResponse{
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(amw.accessTokenTTL.Seconds()),
+	}, nil
 }
 
 func (amw *AuthenticationMiddleware) GetPrincipal(r *http.Request) domain.User {
@@ -1,31 +1,54 @@
 package service
 
 import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
 	"github.com/giancarlobastos/soccer-manager-api/domain"
 	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/squad"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
-	"math/rand"
-	"strconv"
+	"time"
 )
 
 type AccountService struct {
-	accountRepository *repository.AccountRepository
-	teamRepository    *repository.TeamRepository
-	playerRepository  *repository.PlayerRepository
-	emailService      *EmailService
+	accountRepository      repository.AccountRepository
+	teamRepository         repository.TeamRepository
+	playerRepository       repository.PlayerRepository
+	accountTokenRepository repository.AccountTokenRepository
+	sessionRepository      repository.SessionRepository
+	emailService           Mailer
+	squadGenerator         *squad.Generator
+	gameConfig             config.GameConfig
 }
 
+const (
+	unlockTokenTTL        = time.Hour
+	passwordResetTokenTTL = 30 * time.Minute
+)
+
 func NewAccountService(
-	ar *repository.AccountRepository,
-	tr *repository.TeamRepository,
-	pr *repository.PlayerRepository) *AccountService {
+	ar repository.AccountRepository,
+	tr repository.TeamRepository,
+	pr repository.PlayerRepository,
+	atr repository.AccountTokenRepository,
+	sr repository.SessionRepository,
+	es Mailer,
+	sg *squad.Generator,
+	gc config.GameConfig) *AccountService {
 	return &AccountService{
-		accountRepository: ar,
-		teamRepository:    tr,
-		playerRepository:  pr,
-		emailService:      NewEmailService(),
+		accountRepository:      ar,
+		teamRepository:         tr,
+		playerRepository:       pr,
+		accountTokenRepository: atr,
+		sessionRepository:      sr,
+		emailService:           es,
+		squadGenerator:         sg,
+		gameConfig:             gc,
 	}
 }
 
@@ -51,6 +74,23 @@ func (as *AccountService) GetAccount(accountId int) (*domain.Account, error) {
 	}
 
 	account.Team.Players = players
+	summary, err := as.teamRepository.GetSquadSummary(team.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	account.Team.Summary = &summary
+
+	return &account, nil
+}
+
+func (as *AccountService) GetAccountById(accountId int) (*domain.Account, error) {
+	account, err := as.accountRepository.GetAccountById(accountId)
+
+	if err != nil {
+		return nil, err
+	}
 
 	return &account, nil
 }
@@ -66,6 +106,18 @@ func (as *AccountService) GetAccountByUsername(username string) (*domain.Account
 }
 
 func (as *AccountService) CreateAccount(firstName, lastName, email, password string) (*domain.Account, error) {
+	account, err := as.newAccount(firstName, lastName, email, password, domain.UserProfile, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = as.emailService.SendVerificationEmail(account)
+
+	return account, err
+}
+
+func (as *AccountService) newAccount(firstName, lastName, email, password, profile string, confirmed bool) (*domain.Account, error) {
 	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 10)
 
 	if err != nil {
@@ -81,51 +133,33 @@ func (as *AccountService) CreateAccount(firstName, lastName, email, password str
 		VerificationToken: uuid.New().String(),
 		LoginAttempts:     0,
 		Locked:            false,
-		Confirmed:         false,
+		Confirmed:         confirmed,
+		Profile:           profile,
 		Team:              team,
 	}
 
-	err = as.accountRepository.CreateAccount(account)
+	err = as.accountRepository.CreateAccount(account, time.Now())
 
 	if err != nil {
 		return nil, err
 	}
 
-	err = as.emailService.SendVerificationEmail(account)
-
-	return account, err
+	return account, nil
 }
 
 func (as *AccountService) createTeam(name string) *domain.Team {
-	team := &domain.Team{
-		Name:          name,
-		Country:       "Brazil",
-		AvailableCash: 5000000,
-		Players:       make([]domain.Player, 0, 20),
-	}
+	now := time.Now()
+	players := as.squadGenerator.Generate(as.gameConfig.Country, now.UnixNano())
 
-	as.createPlayers(team)
-	return team
-}
-
-func (as *AccountService) createPlayers(team *domain.Team) {
-	as.createPlayersByPosition(team, domain.GoalKeeper, 3)
-	as.createPlayersByPosition(team, domain.Defender, 6)
-	as.createPlayersByPosition(team, domain.Midfielder, 6)
-	as.createPlayersByPosition(team, domain.Forward, 5)
-}
+	for i := range players {
+		players[i].Contract = defaultContract(players[i], as.gameConfig, now)
+	}
 
-func (as *AccountService) createPlayersByPosition(team *domain.Team, position domain.PlayerPosition, quantity int) {
-	for i := 0; i < quantity; i++ {
-		player := domain.Player{
-			FirstName:   string(position),
-			LastName:    strconv.Itoa(i),
-			Country:     "Brazil",
-			Age:         uint8(rand.Intn(22) + 18),
-			Position:    position,
-			MarketValue: 1000000,
-		}
-		team.Players = append(team.Players, player)
+	return &domain.Team{
+		Name:          name,
+		Country:       as.gameConfig.Country,
+		AvailableCash: as.gameConfig.StartingCash,
+		Players:       players,
 	}
 }
 
@@ -135,9 +169,187 @@ func (as *AccountService) VerifyAccount(token string) bool {
 }
 
 func (as *AccountService) RegisterFailedLoginAttempt(username string) error {
-	return as.accountRepository.RegisterFailedLoginAttempt(username)
+	return as.accountRepository.RegisterFailedLoginAttempt(username, &domain.AccountLockEvent{
+		Action:    domain.LockAction,
+		Reason:    "too many failed login attempts",
+		Source:    domain.FailedLoginsLockSource,
+		CreatedAt: time.Now(),
+	})
 }
 
 func (as *AccountService) ResetLoginAttempts(username string) error {
 	return as.accountRepository.ResetLoginAttempts(username)
 }
+
+func (as *AccountService) LockAccount(actorAccountId, accountId int, reason string) error {
+	return as.setLocked(&actorAccountId, accountId, true, reason)
+}
+
+func (as *AccountService) UnlockAccount(actorAccountId, accountId int, reason string) error {
+	return as.setLocked(&actorAccountId, accountId, false, reason)
+}
+
+func (as *AccountService) setLocked(actorAccountId *int, accountId int, locked bool, reason string) error {
+	action := domain.UnlockAction
+
+	if locked {
+		action = domain.LockAction
+	}
+
+	return as.accountRepository.SetLocked(accountId, locked, &domain.AccountLockEvent{
+		AccountId:      accountId,
+		ActorAccountId: actorAccountId,
+		Action:         action,
+		Reason:         reason,
+		Source:         domain.AdminLockSource,
+		CreatedAt:      time.Now(),
+	})
+}
+
+func (as *AccountService) GetLockEvents(accountId int) ([]domain.AccountLockEvent, error) {
+	if _, err := as.accountRepository.GetAccountById(accountId); err != nil {
+		return nil, err
+	}
+
+	events, err := as.accountRepository.FindLockEvents(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if events == nil {
+		events = []domain.AccountLockEvent{}
+	}
+
+	return events, nil
+}
+
+func (as *AccountService) RequestAccountUnlock(username string) error {
+	account, err := as.accountRepository.GetAccountByUsername(username)
+
+	if err == sql.ErrNoRows {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	selfService, err := as.lockedByFailedLogins(account)
+
+	if err != nil || !selfService {
+		return err
+	}
+
+	token, err := as.newAccountToken(account.Id, domain.UnlockAccountPurpose, unlockTokenTTL)
+
+	if err != nil {
+		return err
+	}
+
+	return as.emailService.SendUnlockEmail(&account, token)
+}
+
+func (as *AccountService) UnlockAccountWithToken(token string) bool {
+	accountId, err := as.accountTokenRepository.UseToken(domain.UnlockAccountPurpose, hashToken(token), time.Now())
+
+	if err != nil {
+		return false
+	}
+
+	return as.accountRepository.UnlockFailedLogins(accountId, &domain.AccountLockEvent{
+		AccountId:      accountId,
+		ActorAccountId: &accountId,
+		Action:         domain.UnlockAction,
+		Reason:         "self-service unlock",
+		Source:         domain.SelfServiceLockSource,
+		CreatedAt:      time.Now(),
+	}) == nil
+}
+
+func (as *AccountService) lockedByFailedLogins(account domain.Account) (bool, error) {
+	if !account.Locked {
+		return false, nil
+	}
+
+	events, err := as.accountRepository.FindLockEvents(account.Id)
+
+	if err != nil {
+		return false, err
+	}
+
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Action == domain.LockAction {
+			return events[i].Source == domain.FailedLoginsLockSource, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (as *AccountService) RequestPasswordReset(username string) error {
+	account, err := as.accountRepository.GetAccountByUsername(username)
+
+	if err == sql.ErrNoRows {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	token, err := as.newAccountToken(account.Id, domain.PasswordResetPurpose, passwordResetTokenTTL)
+
+	if err != nil {
+		return err
+	}
+
+	return as.emailService.SendPasswordResetEmail(&account, token, passwordResetTokenTTL)
+}
+
+func (as *AccountService) ResetPassword(token, password string) error {
+	if len(password) == 0 {
+		return errors.New("password cannot be empty")
+	}
+
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	accountId, err := as.accountRepository.ResetPassword(hashToken(token), string(encryptedPassword), &domain.AccountLockEvent{
+		Action:    domain.UnlockAction,
+		Reason:    "password reset",
+		Source:    domain.PasswordResetLockSource,
+		CreatedAt: now,
+	}, now)
+
+	if err == sql.ErrNoRows {
+		return errors.New("invalid token")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return as.sessionRepository.RevokeAccountSessions(accountId, now)
+}
+
+func (as *AccountService) newAccountToken(accountId int, purpose string, ttl time.Duration) (string, error) {
+	token := uuid.New().String()
+	err := as.accountTokenRepository.CreateToken(&domain.AccountToken{
+		AccountId: accountId,
+		Purpose:   purpose,
+		Token:     hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+
+	return token, err
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/repository/memory"
+	"testing"
+	"time"
+)
+
+type fakeMailer struct {
+	unlockTokens []string
+	resetTokens  []string
+}
+
+func (fm *fakeMailer) SendVerificationEmail(account *domain.Account) error {
+	return nil
+}
+
+func (fm *fakeMailer) SendUnlockEmail(account *domain.Account, token string) error {
+	fm.unlockTokens = append(fm.unlockTokens, token)
+	return nil
+}
+
+func (fm *fakeMailer) SendPasswordResetEmail(account *domain.Account, token string, ttl time.Duration) error {
+	fm.resetTokens = append(fm.resetTokens, token)
+	return nil
+}
+
+func newAccountService() (*AccountService, repository.Repositories, *fakeMailer) {
+	repositories := memory.NewRepositories()
+	mailer := &fakeMailer{}
+	as := NewAccountService(repositories.Accounts, repositories.Teams, repositories.Players, repositories.AccountTokens,
+		repositories.Sessions, mailer, nil, config.Default().Game)
+
+	return as, repositories, mailer
+}
+
+func newTestAccount(t *testing.T, repositories repository.Repositories, username string, cash int) *domain.Account {
+	account := &domain.Account{
+		Username:  username,
+		FirstName: username,
+		Confirmed: true,
+		Profile:   domain.UserProfile,
+		Team: &domain.Team{
+			Name:          username + "'s Team",
+			AvailableCash: cash,
+			Players: []domain.Player{
+				{FirstName: "Test", LastName: username, Age: 25, Position: domain.Forward, MarketValue: 1000000},
+			},
+		},
+	}
+
+	if err := repositories.Accounts.CreateAccount(account, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	return account
+}
+
+func lockAccount(t *testing.T, as *AccountService, account *domain.Account, source string) {
+	switch source {
+	case domain.FailedLoginsLockSource:
+		for i := 0; i < 3; i++ {
+			if err := as.RegisterFailedLoginAttempt(account.Username); err != nil {
+				t.Fatal(err)
+			}
+		}
+	case domain.AdminLockSource:
+		if err := as.LockAccount(account.Id, account.Id, "suspicious activity"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func isLocked(t *testing.T, as *AccountService, accountId int) bool {
+	account, err := as.GetAccountById(accountId)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return account.Locked
+}
+
+func TestSelfServiceUnlock(t *testing.T) {
+	tests := []struct {
+		name       string
+		lockSource string
+		emailed    bool
+	}{
+		{"failed login lock", domain.FailedLoginsLockSource, true},
+		{"admin lock", domain.AdminLockSource, false},
+		{"not locked", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			as, repositories, mailer := newAccountService()
+			account := newTestAccount(t, repositories, "user@example.com", 0)
+			lockAccount(t, as, account, test.lockSource)
+
+			if err := as.RequestAccountUnlock(account.Username); err != nil {
+				t.Fatal(err)
+			}
+
+			if emailed := len(mailer.unlockTokens) == 1; emailed != test.emailed {
+				t.Fatalf("expected unlock email to be sent: %v, got %d emails", test.emailed, len(mailer.unlockTokens))
+			}
+
+			if !test.emailed {
+				return
+			}
+
+			token := mailer.unlockTokens[0]
+
+			if !as.UnlockAccountWithToken(token) {
+				t.Fatal("expected the unlock token to unlock the account")
+			}
+
+			if isLocked(t, as, account.Id) {
+				t.Fatal("expected the account to be unlocked")
+			}
+
+			if as.UnlockAccountWithToken(token) {
+				t.Fatal("expected the unlock token to be single use")
+			}
+		})
+	}
+}
+
+func TestUnlockTokenCannotLiftAdminLock(t *testing.T) {
+	as, repositories, _ := newAccountService()
+	account := newTestAccount(t, repositories, "user@example.com", 0)
+	lockAccount(t, as, account, domain.FailedLoginsLockSource)
+	token, err := as.newAccountToken(account.Id, domain.UnlockAccountPurpose, unlockTokenTTL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lockAccount(t, as, account, domain.AdminLockSource)
+
+	if as.UnlockAccountWithToken(token) {
+		t.Fatal("expected an unlock token not to lift an admin lock")
+	}
+
+	if !isLocked(t, as, account.Id) {
+		t.Fatal("expected the account to stay locked")
+	}
+}
+
+func TestRequestAccountUnlockForUnknownUser(t *testing.T) {
+	as, _, mailer := newAccountService()
+
+	if err := as.RequestAccountUnlock("nobody@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mailer.unlockTokens) != 0 {
+		t.Fatal("expected no unlock email for an unknown user")
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		lockSource string
+		locked     bool
+	}{
+		{"failed login lock is lifted", domain.FailedLoginsLockSource, false},
+		{"admin lock is kept", domain.AdminLockSource, true},
+		{"unlocked account", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			as, repositories, mailer := newAccountService()
+			ss := NewSessionService(repositories.Sessions, time.Hour)
+			account := newTestAccount(t, repositories, "user@example.com", 0)
+			lockAccount(t, as, account, test.lockSource)
+			refreshToken, err := ss.IssueRefreshToken(account.Id, "", "access-token", time.Now().Add(time.Minute))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = as.RequestPasswordReset(account.Username); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(mailer.resetTokens) != 1 {
+				t.Fatalf("expected one password reset email, got %d", len(mailer.resetTokens))
+			}
+
+			if err = as.ResetPassword(mailer.resetTokens[0], "new password"); err != nil {
+				t.Fatal(err)
+			}
+
+			if locked := isLocked(t, as, account.Id); locked != test.locked {
+				t.Fatalf("expected locked to be %v, got %v", test.locked, locked)
+			}
+
+			if _, err = ss.ConsumeRefreshToken(refreshToken); err == nil {
+				t.Fatal("expected the password reset to revoke existing sessions")
+			}
+
+			if err = as.ResetPassword(mailer.resetTokens[0], "another password"); err == nil {
+				t.Fatal("expected the password reset token to be single use")
+			}
+		})
+	}
+}
+
+func TestLockEventsRecordSources(t *testing.T) {
+	as, repositories, mailer := newAccountService()
+	account := newTestAccount(t, repositories, "user@example.com", 0)
+	lockAccount(t, as, account, domain.FailedLoginsLockSource)
+
+	if err := as.RequestAccountUnlock(account.Username); err != nil {
+		t.Fatal(err)
+	}
+
+	as.UnlockAccountWithToken(mailer.unlockTokens[0])
+	lockAccount(t, as, account, domain.AdminLockSource)
+
+	if err := as.UnlockAccount(account.Id, account.Id, "resolved"); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := as.GetLockEvents(account.Id)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{domain.FailedLoginsLockSource, domain.SelfServiceLockSource, domain.AdminLockSource, domain.AdminLockSource}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d lock events, got %d", len(expected), len(events))
+	}
+
+	for i, event := range events {
+		if event.Source != expected[i] {
+			t.Fatalf("expected event %d to come from %s, got %s", i, expected[i], event.Source)
+		}
+	}
+}
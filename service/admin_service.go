@@ -0,0 +1,505 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"time"
+)
+
+type AdminService struct {
+	accountService     *AccountService
+	accountRepository  repository.AccountRepository
+	teamRepository     repository.TeamRepository
+	playerRepository   repository.PlayerRepository
+	transferRepository repository.TransferRepository
+	gameConfig         config.GameConfig
+}
+
+func NewAdminService(
+	as *AccountService,
+	ar repository.AccountRepository,
+	tr repository.TeamRepository,
+	pr repository.PlayerRepository,
+	tfr repository.TransferRepository,
+	gc config.GameConfig) *AdminService {
+	return &AdminService{
+		accountService:     as,
+		accountRepository:  ar,
+		teamRepository:     tr,
+		playerRepository:   pr,
+		transferRepository: tfr,
+		gameConfig:         gc,
+	}
+}
+
+func (ads *AdminService) GetAccounts() ([]domain.AdminAccount, error) {
+	accounts, err := ads.accountRepository.FindAccounts()
+
+	if err != nil {
+		return nil, err
+	}
+
+	adminAccounts := make([]domain.AdminAccount, 0, len(accounts))
+
+	for _, account := range accounts {
+		adminAccounts = append(adminAccounts, toAdminAccount(account))
+	}
+
+	return adminAccounts, nil
+}
+
+func (ads *AdminService) GetAccount(accountId int) (*domain.AdminAccount, error) {
+	account, err := ads.accountRepository.GetAccountById(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := ads.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if err == nil {
+		account.Team = team
+	}
+
+	adminAccount := toAdminAccount(account)
+	return &adminAccount, nil
+}
+
+func (ads *AdminService) CreateAccount(firstName, lastName, email, password, profile string, confirmed bool) (*domain.AdminAccount, error) {
+	if profile == "" {
+		profile = domain.UserProfile
+	}
+
+	if !isValidProfile(profile) {
+		return nil, fmt.Errorf("invalid profile: %s", profile)
+	}
+
+	account, err := ads.accountService.newAccount(firstName, lastName, email, password, profile, confirmed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !confirmed {
+		if err = ads.accountService.emailService.SendVerificationEmail(account); err != nil {
+			return nil, err
+		}
+	}
+
+	adminAccount := toAdminAccount(*account)
+	return &adminAccount, nil
+}
+
+func (ads *AdminService) UpdateAccount(accountId int, patchJSON []byte) (*domain.AdminAccount, error) {
+	adminAccount, err := ads.GetAccount(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	original := *adminAccount
+
+	if err = applyPatch(adminAccount, patchJSON); err != nil {
+		return nil, err
+	}
+
+	if adminAccount.Locked != original.Locked || adminAccount.LoginAttempts != original.LoginAttempts {
+		return nil, errors.New("locked and loginAttempts can only be changed through the lock and unlock endpoints")
+	}
+
+	if !isValidProfile(adminAccount.Profile) {
+		return nil, fmt.Errorf("invalid profile: %s", adminAccount.Profile)
+	}
+
+	account := &domain.Account{
+		Id:        accountId,
+		Username:  adminAccount.Username,
+		FirstName: adminAccount.FirstName,
+		LastName:  adminAccount.LastName,
+		Profile:   adminAccount.Profile,
+		Confirmed: adminAccount.Confirmed,
+	}
+
+	if err = ads.accountRepository.UpdateAccount(account); err != nil {
+		return nil, err
+	}
+
+	return ads.GetAccount(accountId)
+}
+
+func (ads *AdminService) DeleteAccount(accountId int) error {
+	return ads.accountRepository.DeleteAccount(accountId, time.Now())
+}
+
+func (ads *AdminService) GetTeams() ([]domain.AdminTeam, error) {
+	teams, err := ads.teamRepository.FindTeams()
+
+	if err != nil {
+		return nil, err
+	}
+
+	adminTeams := make([]domain.AdminTeam, 0, len(teams))
+
+	for _, team := range teams {
+		adminTeams = append(adminTeams, toAdminTeam(team))
+	}
+
+	return adminTeams, nil
+}
+
+func (ads *AdminService) GetTeam(teamId int) (*domain.AdminTeam, error) {
+	team, err := ads.teamRepository.GetTeamById(teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	adminTeam := toAdminTeam(*team)
+	return &adminTeam, nil
+}
+
+func (ads *AdminService) CreateTeam(adminTeam domain.AdminTeam) (*domain.AdminTeam, error) {
+	if err := ads.validateTeamOwner(0, adminTeam.AccountId); err != nil {
+		return nil, err
+	}
+
+	team := &domain.Team{
+		Name:          adminTeam.Name,
+		Country:       adminTeam.Country,
+		AvailableCash: adminTeam.AvailableCash,
+		AccountId:     adminTeam.AccountId,
+	}
+
+	if err := ads.teamRepository.NewTeam(team, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ads.GetTeam(team.Id)
+}
+
+func (ads *AdminService) UpdateTeam(teamId int, patchJSON []byte) (*domain.AdminTeam, error) {
+	adminTeam, err := ads.GetTeam(teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = applyPatch(adminTeam, patchJSON); err != nil {
+		return nil, err
+	}
+
+	if err = ads.validateTeamOwner(teamId, adminTeam.AccountId); err != nil {
+		return nil, err
+	}
+
+	team := &domain.Team{
+		Id:            teamId,
+		Name:          adminTeam.Name,
+		Country:       adminTeam.Country,
+		AvailableCash: adminTeam.AvailableCash,
+		AccountId:     adminTeam.AccountId,
+	}
+
+	if err = ads.teamRepository.UpdateTeamById(team, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ads.GetTeam(teamId)
+}
+
+func (ads *AdminService) DeleteTeam(teamId int) error {
+	return ads.teamRepository.DeleteTeam(teamId, time.Now())
+}
+
+func (ads *AdminService) validateTeamOwner(teamId, accountId int) error {
+	if _, err := ads.accountRepository.GetAccountById(accountId); err != nil {
+		return errors.New("account not found")
+	}
+
+	team, err := ads.teamRepository.GetTeamByAccountId(accountId)
+
+	if err == sql.ErrNoRows {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if team.Id != teamId {
+		return errors.New("account already owns a team")
+	}
+
+	return nil
+}
+
+func (ads *AdminService) GetPlayers() ([]domain.AdminPlayer, error) {
+	players, err := ads.playerRepository.FindPlayers()
+
+	if err != nil {
+		return nil, err
+	}
+
+	adminPlayers := make([]domain.AdminPlayer, 0, len(players))
+
+	for _, player := range players {
+		adminPlayers = append(adminPlayers, toAdminPlayer(player))
+	}
+
+	return adminPlayers, nil
+}
+
+func (ads *AdminService) GetPlayer(playerId int) (*domain.AdminPlayer, error) {
+	player, err := ads.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	adminPlayer := toAdminPlayer(player)
+	return &adminPlayer, nil
+}
+
+func (ads *AdminService) CreatePlayer(adminPlayer domain.AdminPlayer) (*domain.AdminPlayer, error) {
+	if err := ads.validatePlayer(adminPlayer); err != nil {
+		return nil, err
+	}
+
+	player := fromAdminPlayer(adminPlayer)
+
+	if player.TeamId != nil {
+		player.Contract = defaultContract(player, ads.gameConfig, time.Now())
+	}
+
+	if err := ads.playerRepository.NewPlayer(&player); err != nil {
+		return nil, err
+	}
+
+	return ads.GetPlayer(player.Id)
+}
+
+const maxFreeAgentBatch = 50
+
+func (ads *AdminService) CreateFreeAgents(country string, position domain.PlayerPosition, count int, seed *int64) ([]domain.AdminPlayer, error) {
+	if !domain.IsValidPosition(position) {
+		return nil, fmt.Errorf("invalid position: %s", position)
+	}
+
+	if count < 1 || count > maxFreeAgentBatch {
+		return nil, fmt.Errorf("free agents must be created in batches of 1 to %d", maxFreeAgentBatch)
+	}
+
+	if country == "" {
+		country = ads.gameConfig.Country
+	}
+
+	if seed == nil {
+		now := time.Now().UnixNano()
+		seed = &now
+	}
+
+	players := ads.accountService.squadGenerator.GeneratePlayers(country, position, count, *seed)
+	adminPlayers := make([]domain.AdminPlayer, 0, len(players))
+
+	for i := range players {
+		if err := ads.playerRepository.NewPlayer(&players[i]); err != nil {
+			return nil, err
+		}
+
+		adminPlayers = append(adminPlayers, toAdminPlayer(players[i]))
+	}
+
+	return adminPlayers, nil
+}
+
+func (ads *AdminService) UpdatePlayer(playerId int, patchJSON []byte) (*domain.AdminPlayer, error) {
+	adminPlayer, err := ads.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var teamId *int
+
+	if adminPlayer.TeamId != nil {
+		id := *adminPlayer.TeamId
+		teamId = &id
+	}
+
+	if err = applyPatch(adminPlayer, patchJSON); err != nil {
+		return nil, err
+	}
+
+	if !sameTeam(teamId, adminPlayer.TeamId) {
+		return nil, errors.New("a player's team can only change through a transfer")
+	}
+
+	adminPlayer.Id = playerId
+
+	if err = ads.validatePlayer(*adminPlayer); err != nil {
+		return nil, err
+	}
+
+	player := fromAdminPlayer(*adminPlayer)
+
+	if err = ads.playerRepository.UpdatePlayerById(&player); err != nil {
+		return nil, err
+	}
+
+	return ads.GetPlayer(playerId)
+}
+
+func sameTeam(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func (ads *AdminService) DeletePlayer(playerId int) error {
+	return ads.playerRepository.DeletePlayer(playerId, time.Now())
+}
+
+func (ads *AdminService) validatePlayer(player domain.AdminPlayer) error {
+	if !domain.IsValidPosition(player.Position) {
+		return fmt.Errorf("invalid position: %s", player.Position)
+	}
+
+	if player.MarketValue < 0 {
+		return errors.New("market value cannot be negative")
+	}
+
+	a := player.Attributes
+
+	for _, value := range []int{a.Pace, a.Shooting, a.Passing, a.Defending, a.Goalkeeping, a.Stamina, a.Potential} {
+		if value < 0 || value > 99 {
+			return errors.New("player attributes must be between 0 and 99")
+		}
+	}
+
+	if player.TeamId != nil {
+		if _, err := ads.teamRepository.GetTeamById(*player.TeamId); err != nil {
+			return errors.New("team not found")
+		}
+	}
+
+	return nil
+}
+
+func (ads *AdminService) NewTransfer(playerId, askedPrice int) (transferId int, err error) {
+	if askedPrice <= 0 {
+		return 0, errAskedPrice
+	}
+
+	player, err := ads.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if player.TeamId == nil {
+		return 0, errors.New("player without team cannot be put in the transfer list")
+	}
+
+	listed, err := ads.transferRepository.IsPlayerListed(playerId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if listed {
+		return 0, errors.New("player is already in the transfer list")
+	}
+
+	return ads.transferRepository.NewTransfer(playerId, askedPrice, player.MarketValue, nil)
+}
+
+func isValidProfile(profile string) bool {
+	return profile == domain.UserProfile || profile == domain.AdminProfile
+}
+
+func applyPatch(v interface{}, patchJSON []byte) error {
+	patch, err := jsonpatch.DecodePatch(patchJSON)
+
+	if err != nil {
+		return err
+	}
+
+	original, _ := json.Marshal(v)
+	patched, err := patch.Apply(original)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(patched, v)
+}
+
+func toAdminAccount(account domain.Account) domain.AdminAccount {
+	adminAccount := domain.AdminAccount{
+		Id:            account.Id,
+		Username:      account.Username,
+		FirstName:     account.FirstName,
+		LastName:      account.LastName,
+		Profile:       account.Profile,
+		Confirmed:     account.Confirmed,
+		Locked:        account.Locked,
+		LoginAttempts: account.LoginAttempts,
+	}
+
+	if account.Team != nil {
+		teamId := account.Team.Id
+		adminAccount.TeamId = &teamId
+	}
+
+	return adminAccount
+}
+
+func toAdminTeam(team domain.Team) domain.AdminTeam {
+	return domain.AdminTeam{
+		Id:            team.Id,
+		Name:          team.Name,
+		Country:       team.Country,
+		AvailableCash: team.AvailableCash,
+		AccountId:     team.AccountId,
+	}
+}
+
+func toAdminPlayer(player domain.Player) domain.AdminPlayer {
+	return domain.AdminPlayer{
+		Id:          player.Id,
+		FirstName:   player.FirstName,
+		LastName:    player.LastName,
+		Country:     player.Country,
+		Age:         player.Age,
+		Position:    player.Position,
+		MarketValue: player.MarketValue,
+		Attributes:  player.Attributes,
+		TeamId:      player.TeamId,
+	}
+}
+
+func fromAdminPlayer(player domain.AdminPlayer) domain.Player {
+	return domain.Player{
+		Id:          player.Id,
+		FirstName:   player.FirstName,
+		LastName:    player.LastName,
+		Country:     player.Country,
+		Age:         player.Age,
+		Position:    player.Position,
+		MarketValue: player.MarketValue,
+		Attributes:  player.Attributes,
+		TeamId:      player.TeamId,
+	}
+}
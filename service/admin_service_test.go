@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"testing"
+)
+
+func TestUpdatePlayerKeepsTeam(t *testing.T) {
+	as, repositories, _ := newAccountService()
+	ads := NewAdminService(as, repositories.Accounts, repositories.Teams, repositories.Players, repositories.Transfers, config.Default().Game)
+	owner := newTestAccount(t, repositories, "owner@example.com", 0)
+	other := newTestAccount(t, repositories, "other@example.com", 0)
+	playerId := owner.Team.Players[0].Id
+
+	tests := []struct {
+		name  string
+		patch string
+		valid bool
+	}{
+		{"rename", `[{"op":"replace","path":"/firstName","value":"Renamed"}]`, true},
+		{"move to another team", fmt.Sprintf(`[{"op":"replace","path":"/teamId","value":%d}]`, other.Team.Id), false},
+		{"release to free agency", `[{"op":"replace","path":"/teamId","value":null}]`, false},
+		{"keep the same team", fmt.Sprintf(`[{"op":"replace","path":"/teamId","value":%d}]`, owner.Team.Id), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ads.UpdatePlayer(playerId, []byte(test.patch))
+
+			if (err == nil) != test.valid {
+				t.Fatalf("expected valid=%v, got %v", test.valid, err)
+			}
+
+			player, err := repositories.Players.GetPlayer(playerId)
+
+			if err != nil || player.TeamId == nil || *player.TeamId != owner.Team.Id {
+				t.Fatalf("expected the player to stay with team %d, got %+v: %v", owner.Team.Id, player, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/valuation"
+	"strings"
+	"time"
+)
+
+var ErrContractRejected = errors.New("the player rejected the contract")
+
+type ContractService struct {
+	contractRepository repository.ContractRepository
+	playerRepository   repository.PlayerRepository
+	teamRepository     repository.TeamRepository
+	windowService      *TransferWindowService
+	gameConfig         config.GameConfig
+}
+
+func NewContractService(cr repository.ContractRepository, pr repository.PlayerRepository, tr repository.TeamRepository, ws *TransferWindowService, gc config.GameConfig) *ContractService {
+	return &ContractService{
+		contractRepository: cr,
+		playerRepository:   pr,
+		teamRepository:     tr,
+		windowService:      ws,
+		gameConfig:         gc,
+	}
+}
+
+func (cs *ContractService) GetDemand(accountId, playerId int) (*domain.ContractDemand, error) {
+	player, err := cs.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := cs.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	demand := &domain.ContractDemand{
+		PlayerId: player.Id,
+		Wage:     valuation.WageDemand(player.MarketValue, player.Age),
+		MaxYears: valuation.MaxContractYears(player.Age),
+	}
+
+	if player.TeamId != nil && *player.TeamId == team.Id {
+		if demand.Wage, err = cs.renewalWage(player); err != nil {
+			return nil, err
+		}
+	}
+
+	return demand, nil
+}
+
+func (cs *ContractService) RenewContract(accountId, playerId int, terms domain.ContractTerms) (*domain.Contract, error) {
+	team, err := cs.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := cs.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if player.TeamId == nil || *player.TeamId != team.Id {
+		return nil, errors.New("player not in your team")
+	}
+
+	minimumWage, err := cs.renewalWage(player)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	contract, err := negotiateContract(player, terms, minimumWage, cs.gameConfig, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cs.contractRepository.RenewContract(team.Id, contract, now); err != nil {
+		return nil, err
+	}
+
+	return contract, nil
+}
+
+func (cs *ContractService) PayReleaseClause(accountId, playerId int, terms *domain.ContractTerms) (*domain.Contract, error) {
+	if terms == nil {
+		return nil, errors.New("a contract must be agreed with the player")
+	}
+
+	now := time.Now()
+
+	if err := cs.windowService.EnsureOpen(now); err != nil {
+		return nil, err
+	}
+
+	buyer, err := cs.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := cs.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if player.TeamId == nil {
+		return nil, errors.New("free agents have no release clause, sign them instead")
+	}
+
+	if *player.TeamId == buyer.Id {
+		return nil, errors.New("teams cannot pay the release clause of their own players")
+	}
+
+	current, err := cs.contractRepository.GetContract(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case current.ReleaseClause == nil:
+		return nil, errors.New("player has no release clause")
+	case buyer.AvailableCash < *current.ReleaseClause:
+		return nil, errors.New("insufficient funds")
+	}
+
+	contract, err := negotiateContract(player, *terms, valuation.WageDemand(player.MarketValue, player.Age), cs.gameConfig, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cs.contractRepository.PayReleaseClause(buyer.Id, *current.ReleaseClause, newMarketValue(*current.ReleaseClause), contract, now); err != nil {
+		return nil, err
+	}
+
+	return contract, nil
+}
+
+func (cs *ContractService) PayDueWages(now time.Time) (paid int, err error) {
+	contracts, err := cs.contractRepository.FindDueWages(now)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var failed []string
+
+	for _, contract := range contracts {
+		if err = cs.contractRepository.PayWage(contract, contract.NextWageAt.Add(cs.gameConfig.WagePeriod.Duration), now); err != nil {
+			failed = append(failed, fmt.Sprintf("%d: %v", contract.Id, err))
+			continue
+		}
+
+		paid++
+	}
+
+	if len(failed) > 0 {
+		return paid, errors.New("wages not paid: " + strings.Join(failed, "; "))
+	}
+
+	return paid, nil
+}
+
+func (cs *ContractService) ExpireDueContracts(now time.Time) (expired int, err error) {
+	contractIds, err := cs.contractRepository.FindExpiredContractIds(now)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var failed []string
+
+	for _, contractId := range contractIds {
+		if err = cs.contractRepository.ExpireContract(contractId, now); err != nil {
+			failed = append(failed, fmt.Sprintf("%d: %v", contractId, err))
+			continue
+		}
+
+		expired++
+	}
+
+	if len(failed) > 0 {
+		return expired, errors.New("contracts not expired: " + strings.Join(failed, "; "))
+	}
+
+	return expired, nil
+}
+
+func (cs *ContractService) renewalWage(player domain.Player) (int, error) {
+	current, err := cs.contractRepository.GetContract(player.Id)
+
+	if err != nil {
+		return 0, errors.New("player has no contract to renew")
+	}
+
+	wage := valuation.WageDemand(player.MarketValue, player.Age)
+
+	if current.Wage > wage {
+		wage = current.Wage
+	}
+
+	return wage, nil
+}
+
+func negotiateContract(player domain.Player, terms domain.ContractTerms, minimumWage int, gc config.GameConfig, now time.Time) (*domain.Contract, error) {
+	maxYears := valuation.MaxContractYears(player.Age)
+
+	switch {
+	case terms.Years < 1 || terms.Years > maxYears:
+		return nil, fmt.Errorf("%w: contract length must be between 1 and %d years", ErrContractRejected, maxYears)
+	case terms.Wage < minimumWage:
+		return nil, fmt.Errorf("%w: the player demands a wage of at least %d", ErrContractRejected, minimumWage)
+	case terms.ReleaseClause != nil && *terms.ReleaseClause < player.MarketValue:
+		return nil, fmt.Errorf("%w: the release clause cannot be below the player's market value of %d", ErrContractRejected, player.MarketValue)
+	}
+
+	return newContract(player.Id, terms, gc, now), nil
+}
+
+func agreedContract(player domain.Player, terms *domain.ContractTerms, gc config.GameConfig, now time.Time) *domain.Contract {
+	if terms == nil {
+		return defaultContract(player, gc, now)
+	}
+
+	return newContract(player.Id, *terms, gc, now)
+}
+
+func defaultContract(player domain.Player, gc config.GameConfig, now time.Time) *domain.Contract {
+	terms := domain.ContractTerms{Wage: valuation.WageDemand(player.MarketValue, player.Age), Years: gc.ContractYears}
+	return newContract(player.Id, terms, gc, now)
+}
+
+func newContract(playerId int, terms domain.ContractTerms, gc config.GameConfig, now time.Time) *domain.Contract {
+	now = now.UTC().Truncate(time.Second)
+
+	return &domain.Contract{
+		PlayerId:      playerId,
+		Wage:          terms.Wage,
+		ReleaseClause: terms.ReleaseClause,
+		StartsAt:      now,
+		EndsAt:        now.AddDate(terms.Years, 0, 0),
+		NextWageAt:    now.Add(gc.WagePeriod.Duration),
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"testing"
+	"time"
+)
+
+func TestNegotiateContract(t *testing.T) {
+	player := domain.Player{Id: 1, Age: 25, MarketValue: 1000000}
+	below, above := 999999, 2000000
+
+	tests := []struct {
+		name     string
+		terms    domain.ContractTerms
+		rejected bool
+	}{
+		{"agreed", domain.ContractTerms{Wage: 10000, Years: 3}, false},
+		{"agreed with a release clause", domain.ContractTerms{Wage: 10000, Years: 3, ReleaseClause: &above}, false},
+		{"too short", domain.ContractTerms{Wage: 10000, Years: 0}, true},
+		{"too long", domain.ContractTerms{Wage: 10000, Years: 6}, true},
+		{"wage below demand", domain.ContractTerms{Wage: 9999, Years: 3}, true},
+		{"release clause below market value", domain.ContractTerms{Wage: 10000, Years: 3, ReleaseClause: &below}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Now()
+			contract, err := negotiateContract(player, test.terms, 10000, config.Default().Game, now)
+
+			if rejected := errors.Is(err, ErrContractRejected); rejected != test.rejected {
+				t.Fatalf("expected rejected to be %v, got %v", test.rejected, err)
+			}
+
+			if test.rejected {
+				return
+			}
+
+			if contract.PlayerId != player.Id || contract.Wage != test.terms.Wage || contract.ReleaseClause != test.terms.ReleaseClause {
+				t.Fatalf("expected the contract to carry the agreed terms, got %+v", contract)
+			}
+
+			if years := contract.EndsAt.Year() - contract.StartsAt.Year(); years != test.terms.Years {
+				t.Fatalf("expected a %d year contract, got %d years", test.terms.Years, years)
+			}
+		})
+	}
+}
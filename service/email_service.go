@@ -2,28 +2,64 @@ package service
 
 import (
 	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
 	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"net"
 	"net/smtp"
+	"strconv"
+	"time"
 )
 
+type Mailer interface {
+	SendVerificationEmail(account *domain.Account) error
+	SendUnlockEmail(account *domain.Account, token string) error
+	SendPasswordResetEmail(account *domain.Account, token string, ttl time.Duration) error
+}
+
 type EmailService struct {
-	auth *smtp.Auth
+	auth    *smtp.Auth
+	addr    string
+	from    string
+	baseURL string
 }
 
-func NewEmailService() *EmailService {
-	auth := smtp.PlainAuth("", "soccer.manager.api@gmail.com", "soccer.manager", "smtp.gmail.com")
+func NewEmailService(cfg config.SMTPConfig, baseURL string) *EmailService {
+	auth := smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
 	return &EmailService{
-		auth: &auth,
+		auth:    &auth,
+		addr:    net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from:    cfg.From,
+		baseURL: baseURL,
 	}
 }
 
 func (es *EmailService) SendVerificationEmail(account *domain.Account) error {
+	subject := "Verify your account!"
+	body := fmt.Sprintf("<html><body><p>Hi %s!</p><a href='%s/verify-account?token=%s'>Click here to verify your account!</a></body></html>",
+		account.FirstName, es.baseURL, account.VerificationToken)
+
+	return es.send(account.Username, subject, body)
+}
+
+func (es *EmailService) SendUnlockEmail(account *domain.Account, token string) error {
+	subject := "Unlock your account!"
+	body := fmt.Sprintf("<html><body><p>Hi %s!</p><a href='%s/unlock-account?token=%s'>Click here to unlock your account!</a></body></html>",
+		account.FirstName, es.baseURL, token)
+
+	return es.send(account.Username, subject, body)
+}
+
+func (es *EmailService) SendPasswordResetEmail(account *domain.Account, token string, ttl time.Duration) error {
+	subject := "Reset your password"
+	body := fmt.Sprintf("<html><body><p>Hi %s!</p><p>Use the code below to reset your password. It expires in %d minutes and can be used only once.</p><p><b>%s</b></p></body></html>",
+		account.FirstName, int(ttl.Minutes()), token)
+
+	return es.send(account.Username, subject, body)
+}
+
+func (es *EmailService) send(to, subject, body string) error {
 	mime := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
-	subject := "Subject: Verify your account!\n"
-	body := fmt.Sprintf("<html><body><p>Hi %s!</p><a href='http://localhost:8080/verify-account?token=%s'>Click here to verify your account!</a></body></html>",
-		account.FirstName, account.VerificationToken)
-	msg := []byte(subject + mime + body)
-	addr := "smtp.gmail.com:587"
+	msg := []byte("Subject: " + subject + "\n" + mime + body)
 
-	return smtp.SendMail(addr, *es.auth, "soccer.manager.api@gmail.com", []string{account.Username}, msg)
+	return smtp.SendMail(es.addr, *es.auth, es.from, []string{to}, msg)
 }
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+)
+
+type FinanceService struct {
+	ledgerRepository repository.LedgerRepository
+	teamRepository   repository.TeamRepository
+}
+
+func NewFinanceService(lr repository.LedgerRepository, tr repository.TeamRepository) *FinanceService {
+	return &FinanceService{
+		ledgerRepository: lr,
+		teamRepository:   tr,
+	}
+}
+
+func (fs *FinanceService) GetTeamFinances(accountId, teamId int) (*domain.TeamFinances, error) {
+	team, err := fs.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if team.Id != teamId {
+		return nil, errors.New("team not owned by account id")
+	}
+
+	transactions, err := fs.ledgerRepository.FindTeamTransactions(team.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	finances := &domain.TeamFinances{
+		TeamId:        team.Id,
+		AvailableCash: team.AvailableCash,
+		Totals:        make(map[string]domain.CategoryTotal),
+		Entries:       make([]domain.FinanceEntry, 0, len(transactions)),
+	}
+
+	for _, transaction := range transactions {
+		if holds(transaction.ToAccount, transaction.ToTeamId, domain.LedgerEscrow, team.Id) {
+			finances.Escrow += transaction.Amount
+		}
+
+		if holds(transaction.FromAccount, transaction.FromTeamId, domain.LedgerEscrow, team.Id) {
+			finances.Escrow -= transaction.Amount
+		}
+
+		entry := domain.FinanceEntry{
+			Id:            transaction.Id,
+			Category:      transaction.Category,
+			ReferenceType: transaction.ReferenceType,
+			ReferenceId:   transaction.ReferenceId,
+			CreatedAt:     transaction.CreatedAt,
+		}
+
+		switch {
+		case holds(transaction.ToAccount, transaction.ToTeamId, domain.LedgerTeam, team.Id):
+			entry.Amount = transaction.Amount
+			entry.CounterpartId = counterpart(transaction.FromTeamId, team.Id)
+		case holds(transaction.FromAccount, transaction.FromTeamId, domain.LedgerTeam, team.Id):
+			entry.Amount = -transaction.Amount
+			entry.CounterpartId = counterpart(transaction.ToTeamId, team.Id)
+		default:
+			continue
+		}
+
+		finances.Balance += entry.Amount
+		entry.Balance = finances.Balance
+		finances.Entries = append(finances.Entries, entry)
+
+		total := finances.Totals[entry.Category]
+
+		if entry.Amount > 0 {
+			total.Credits += entry.Amount
+		} else {
+			total.Debits -= entry.Amount
+		}
+
+		total.Net += entry.Amount
+		finances.Totals[entry.Category] = total
+	}
+
+	return finances, nil
+}
+
+func (fs *FinanceService) CheckLedger() ([]domain.LedgerBalance, error) {
+	balances, err := fs.ledgerRepository.FindBalances()
+
+	if err != nil {
+		return nil, err
+	}
+
+	drifted := make([]domain.LedgerBalance, 0)
+
+	for _, balance := range balances {
+		if balance.AvailableCash != balance.LedgerCash || balance.ActiveBids != balance.LedgerEscrow {
+			drifted = append(drifted, balance)
+		}
+	}
+
+	return drifted, nil
+}
+
+func holds(account string, teamId *int, kind string, id int) bool {
+	return account == kind && teamId != nil && *teamId == id
+}
+
+func counterpart(teamId *int, id int) *int {
+	if teamId == nil || *teamId == id {
+		return nil
+	}
+
+	return teamId
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/valuation"
+	"time"
+)
+
+type FreeAgentService struct {
+	playerRepository   repository.PlayerRepository
+	contractRepository repository.ContractRepository
+	teamRepository     repository.TeamRepository
+	gameConfig         config.GameConfig
+}
+
+func NewFreeAgentService(pr repository.PlayerRepository, cr repository.ContractRepository, tr repository.TeamRepository, gc config.GameConfig) *FreeAgentService {
+	return &FreeAgentService{
+		playerRepository:   pr,
+		contractRepository: cr,
+		teamRepository:     tr,
+		gameConfig:         gc,
+	}
+}
+
+func (fas *FreeAgentService) GetFreeAgents(filter domain.FreeAgentFilter) ([]domain.FreeAgent, error) {
+	if err := validateFreeAgentFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	players, err := fas.playerRepository.FindFreeAgents(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	freeAgents := make([]domain.FreeAgent, 0, len(players))
+
+	for _, player := range players {
+		freeAgents = append(freeAgents, domain.FreeAgent{
+			Player:     player,
+			SigningFee: valuation.SigningFee(player.MarketValue),
+			Wage:       valuation.WageDemand(player.MarketValue, player.Age),
+			MaxYears:   valuation.MaxContractYears(player.Age),
+		})
+	}
+
+	return freeAgents, nil
+}
+
+func (fas *FreeAgentService) SignFreeAgent(accountId, playerId int, terms domain.ContractTerms) (*domain.Player, error) {
+	team, err := fas.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := fas.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if player.TeamId != nil {
+		return nil, errors.New("player is not a free agent")
+	}
+
+	fee := valuation.SigningFee(player.MarketValue)
+
+	if team.AvailableCash < fee {
+		return nil, errors.New("insufficient funds")
+	}
+
+	now := time.Now()
+	contract, err := negotiateContract(player, terms, valuation.WageDemand(player.MarketValue, player.Age), fas.gameConfig, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = fas.contractRepository.SignFreeAgent(team.Id, contract, fee, now); err != nil {
+		return nil, err
+	}
+
+	player.TeamId = &team.Id
+	player.Contract = contract
+	return &player, nil
+}
+
+func (fas *FreeAgentService) ReleasePlayer(accountId, playerId int) (*domain.Player, error) {
+	team, err := fas.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := fas.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if player.TeamId == nil || *player.TeamId != team.Id {
+		return nil, errors.New("player not in your team")
+	}
+
+	if err = fas.contractRepository.ReleasePlayer(team.Id, playerId, time.Now()); err != nil {
+		return nil, err
+	}
+
+	player.TeamId = nil
+	player.Contract = nil
+	return &player, nil
+}
+
+func validateFreeAgentFilter(filter *domain.FreeAgentFilter) error {
+	if filter.Position != "" && !domain.IsValidPosition(filter.Position) {
+		return fmt.Errorf("invalid position: %s", filter.Position)
+	}
+
+	if filter.MinAge < 0 || filter.MaxAge < 0 || filter.MinMarketValue < 0 || filter.MaxMarketValue < 0 {
+		return errors.New("range filters cannot be negative")
+	}
+
+	if (filter.MaxAge > 0 && filter.MinAge > filter.MaxAge) ||
+		(filter.MaxMarketValue > 0 && filter.MinMarketValue > filter.MaxMarketValue) {
+		return errors.New("range filters must have min lower than max")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTransferPageSize
+	} else if filter.Limit > maxTransferPageSize {
+		filter.Limit = maxTransferPageSize
+	}
+
+	return nil
+}
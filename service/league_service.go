@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/league"
+	"github.com/giancarlobastos/soccer-manager-api/match"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"strings"
+	"time"
+)
+
+type LeagueService struct {
+	leagueRepository repository.LeagueRepository
+	teamRepository   repository.TeamRepository
+	matchService     *MatchService
+}
+
+func NewLeagueService(lr repository.LeagueRepository, tr repository.TeamRepository, ms *MatchService) *LeagueService {
+	return &LeagueService{
+		leagueRepository: lr,
+		teamRepository:   tr,
+		matchService:     ms,
+	}
+}
+
+func (ls *LeagueService) CreateLeague(name, country string, seed *int64) (*domain.League, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("league name is required")
+	}
+
+	now := time.Now()
+
+	if seed == nil {
+		s := now.UnixNano()
+		seed = &s
+	}
+
+	l := &domain.League{
+		Name:      name,
+		Country:   country,
+		Status:    domain.LeagueOpen,
+		Seed:      *seed,
+		CreatedAt: now,
+	}
+
+	if err := ls.leagueRepository.CreateLeague(l); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (ls *LeagueService) GetLeagues() ([]domain.League, error) {
+	leagues, err := ls.leagueRepository.FindLeagues()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(make([]domain.League, 0, len(leagues)), leagues...), nil
+}
+
+func (ls *LeagueService) GetLeague(leagueId int) (*domain.League, error) {
+	l, err := ls.leagueRepository.GetLeague(leagueId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+func (ls *LeagueService) EnrolTeam(user domain.User, leagueId, teamId int) error {
+	l, err := ls.leagueRepository.GetLeague(leagueId)
+
+	if err != nil {
+		return err
+	}
+
+	team, err := ls.teamRepository.GetTeamById(teamId)
+
+	if err != nil {
+		return err
+	}
+
+	if user.Profile != domain.AdminProfile {
+		own, err := ls.teamRepository.GetTeamByAccountId(user.AccountId)
+
+		if err != nil || own.Id != teamId {
+			return errors.New("only the account's own team can be enrolled")
+		}
+	}
+
+	if l.Country != "" && !strings.EqualFold(l.Country, team.Country) {
+		return errors.New("team country does not match the league country")
+	}
+
+	return ls.leagueRepository.EnrolTeam(leagueId, teamId, time.Now())
+}
+
+func (ls *LeagueService) StartLeague(leagueId int) (*domain.League, error) {
+	if _, err := ls.leagueRepository.GetLeague(leagueId); err != nil {
+		return nil, err
+	}
+
+	teamIds, err := ls.leagueRepository.FindLeagueTeamIds(leagueId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(teamIds) < 2 {
+		return nil, errors.New("a league needs at least two teams")
+	}
+
+	rounds := league.DoubleRoundRobin(teamIds)
+	var fixtures []domain.Fixture
+
+	for i, pairings := range rounds {
+		for _, pairing := range pairings {
+			fixtures = append(fixtures, domain.Fixture{
+				LeagueId:   leagueId,
+				Round:      i + 1,
+				HomeTeamId: pairing.HomeTeamId,
+				AwayTeamId: pairing.AwayTeamId,
+			})
+		}
+	}
+
+	if err = ls.leagueRepository.StartLeague(leagueId, len(rounds), fixtures); err != nil {
+		return nil, err
+	}
+
+	return ls.GetLeague(leagueId)
+}
+
+func (ls *LeagueService) PlayNextRound(leagueId int) ([]domain.Fixture, error) {
+	l, err := ls.leagueRepository.GetLeague(leagueId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Status != domain.LeagueRunning {
+		return nil, errors.New("league is not running")
+	}
+
+	round := l.CurrentRound + 1
+	fixtures, err := ls.leagueRepository.FindFixtures(leagueId, round)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	for i := range fixtures {
+		home, err := ls.matchService.getLineUp(fixtures[i].HomeTeamId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		away, err := ls.matchService.getLineUp(fixtures[i].AwayTeamId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		seed := l.Seed + int64(fixtures[i].Id)
+
+		if !match.CanPlay(*home) || !match.CanPlay(*away) {
+			fixtures[i].Match = match.Forfeit(*home, *away, seed)
+			fixtures[i].Match.PlayedAt = now
+			continue
+		}
+
+		played, err := match.Simulate(*home, *away, seed)
+
+		if err != nil {
+			return nil, err
+		}
+
+		played.PlayedAt = now
+		fixtures[i].Match = played
+	}
+
+	if err = ls.leagueRepository.RecordRound(leagueId, round, fixtures, round == l.Rounds); err != nil {
+		return nil, err
+	}
+
+	return ls.GetFixtures(leagueId, round)
+}
+
+func (ls *LeagueService) GetFixtures(leagueId, round int) ([]domain.Fixture, error) {
+	if _, err := ls.leagueRepository.GetLeague(leagueId); err != nil {
+		return nil, err
+	}
+
+	fixtures, err := ls.leagueRepository.FindFixtures(leagueId, round)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(make([]domain.Fixture, 0, len(fixtures)), fixtures...), nil
+}
+
+func (ls *LeagueService) GetStandings(leagueId int) ([]domain.Standing, error) {
+	if _, err := ls.leagueRepository.GetLeague(leagueId); err != nil {
+		return nil, err
+	}
+
+	standings, err := ls.leagueRepository.FindStandings(leagueId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(make([]domain.Standing, 0, len(standings)), standings...), nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/match"
+	"github.com/giancarlobastos/soccer-manager-api/repository/memory"
+	"testing"
+	"time"
+)
+
+func TestPlayNextRoundForfeitsShortTeams(t *testing.T) {
+	repositories := memory.NewRepositories()
+	ls := NewLeagueService(repositories.Leagues, repositories.Teams,
+		NewMatchService(repositories.Matches, repositories.Teams, repositories.Players))
+	short := newTestAccount(t, repositories, "short@example.com", 0)
+	full := &domain.Account{Username: "full@example.com", Confirmed: true, Profile: domain.UserProfile, Team: &domain.Team{Name: "Full"}}
+
+	for len(full.Team.Players) < match.LineUpSize {
+		full.Team.Players = append(full.Team.Players, domain.Player{FirstName: "Test", LastName: "Full", Age: 25,
+			Position: domain.Midfielder, MarketValue: 1000000})
+	}
+
+	if err := repositories.Accounts.CreateAccount(full, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := ls.CreateLeague("Forfeits", "", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, team := range []*domain.Team{full.Team, short.Team} {
+		if err = ls.EnrolTeam(domain.User{Profile: domain.AdminProfile}, l.Id, team.Id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err = ls.StartLeague(l.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	for round := 1; round <= 2; round++ {
+		fixtures, err := ls.PlayNextRound(l.Id)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(fixtures) != 1 || fixtures[0].HomeGoals == nil || fixtures[0].AwayGoals == nil {
+			t.Fatalf("expected round %d to be played, got %+v", round, fixtures)
+		}
+
+		fullGoals, shortGoals := *fixtures[0].HomeGoals, *fixtures[0].AwayGoals
+
+		if fixtures[0].AwayTeamId == full.Team.Id {
+			fullGoals, shortGoals = shortGoals, fullGoals
+		}
+
+		if fullGoals != 3 || shortGoals != 0 {
+			t.Fatalf("expected a 3-0 forfeit in round %d, got %d-%d", round, fullGoals, shortGoals)
+		}
+	}
+
+	if l, err = ls.GetLeague(l.Id); err != nil || l.Status != domain.LeagueFinished {
+		t.Fatalf("expected the league to finish, got %+v: %v", l, err)
+	}
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/valuation"
+	"strings"
+	"time"
+)
+
+type LoanService struct {
+	loanRepository   repository.LoanRepository
+	playerRepository repository.PlayerRepository
+	teamRepository   repository.TeamRepository
+	windowService    *TransferWindowService
+	gameConfig       config.GameConfig
+}
+
+func NewLoanService(lr repository.LoanRepository, pr repository.PlayerRepository, tr repository.TeamRepository, ws *TransferWindowService, gc config.GameConfig) *LoanService {
+	return &LoanService{
+		loanRepository:   lr,
+		playerRepository: pr,
+		teamRepository:   tr,
+		windowService:    ws,
+		gameConfig:       gc,
+	}
+}
+
+const (
+	maxLoanDays   = 365
+	maxLoanRounds = 60
+)
+
+var loanStatuses = map[string]bool{
+	domain.LoanProposed:  true,
+	domain.LoanActive:    true,
+	domain.LoanReturned:  true,
+	domain.LoanBought:    true,
+	domain.LoanRejected:  true,
+	domain.LoanWithdrawn: true,
+	domain.LoanCancelled: true,
+}
+
+func (ls *LoanService) ProposeLoan(accountId int, loan domain.Loan) (*domain.Loan, error) {
+	switch {
+	case loan.Fee < 0:
+		return nil, errors.New("loan fee cannot be negative")
+	case (loan.DurationDays == nil) == (loan.DurationRounds == nil):
+		return nil, errors.New("loan duration must be given either in days or in rounds")
+	case loan.DurationDays != nil && (*loan.DurationDays <= 0 || *loan.DurationDays > maxLoanDays):
+		return nil, fmt.Errorf("loan duration must be between 1 and %d days", maxLoanDays)
+	case loan.DurationRounds != nil && (*loan.DurationRounds <= 0 || *loan.DurationRounds > maxLoanRounds):
+		return nil, fmt.Errorf("loan duration must be between 1 and %d rounds", maxLoanRounds)
+	case loan.BuyOptionPrice != nil && *loan.BuyOptionPrice <= 0:
+		return nil, errors.New("buy option price must be positive")
+	}
+
+	borrower, err := ls.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := ls.playerRepository.GetPlayer(loan.PlayerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case player.TeamId == nil:
+		return nil, errors.New("player does not belong to a team")
+	case *player.TeamId == borrower.Id:
+		return nil, errors.New("teams cannot loan their own players")
+	case borrower.AvailableCash < loan.Fee:
+		return nil, errors.New("insufficient funds")
+	}
+
+	loan.LenderId = *player.TeamId
+	loan.BorrowerId = borrower.Id
+	loan.ProposedAt = time.Now()
+
+	if err = ls.loanRepository.CreateLoan(&loan); err != nil {
+		return nil, err
+	}
+
+	return ls.getLoan(loan.Id)
+}
+
+func (ls *LoanService) GetLoans(accountId int, status string) ([]domain.Loan, error) {
+	if status != "" && !loanStatuses[status] {
+		return nil, fmt.Errorf("invalid status: %s", status)
+	}
+
+	loans := make([]domain.Loan, 0)
+	team, err := ls.teamRepository.GetTeamByAccountId(accountId)
+
+	if err == sql.ErrNoRows {
+		return loans, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := ls.loanRepository.FindLoans(team.Id, status)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(loans, found...), nil
+}
+
+func (ls *LoanService) GetLoan(user domain.User, loanId int) (*domain.Loan, error) {
+	if user.Profile == domain.AdminProfile {
+		return ls.getLoan(loanId)
+	}
+
+	loan, _, err := ls.partyLoan(user.AccountId, loanId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &loan, nil
+}
+
+func (ls *LoanService) AcceptLoan(accountId, loanId int) (*domain.Loan, error) {
+	if err := ls.windowService.EnsureOpen(time.Now()); err != nil {
+		return nil, err
+	}
+
+	loan, team, err := ls.partyLoan(accountId, loanId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if loan.LenderId != team.Id {
+		return nil, errors.New("only the lending team can accept a loan")
+	}
+
+	borrower, err := ls.teamRepository.GetTeamById(loan.BorrowerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if borrower.AvailableCash < loan.Fee {
+		return nil, errors.New("borrowing team has insufficient funds")
+	}
+
+	if err = ls.loanRepository.StartLoan(loanId, team.Id, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ls.getLoan(loanId)
+}
+
+func (ls *LoanService) RejectLoan(accountId, loanId int) (*domain.Loan, error) {
+	loan, team, err := ls.partyLoan(accountId, loanId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if loan.LenderId != team.Id {
+		return nil, errors.New("only the lending team can reject a loan")
+	}
+
+	if err = ls.loanRepository.CloseLoan(loanId, team.Id, domain.LoanRejected, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ls.getLoan(loanId)
+}
+
+func (ls *LoanService) WithdrawLoan(accountId, loanId int) (*domain.Loan, error) {
+	loan, team, err := ls.partyLoan(accountId, loanId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if loan.BorrowerId != team.Id {
+		return nil, errors.New("only the borrowing team can withdraw a loan")
+	}
+
+	if err = ls.loanRepository.CloseLoan(loanId, team.Id, domain.LoanWithdrawn, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ls.getLoan(loanId)
+}
+
+func (ls *LoanService) ExerciseBuyOption(accountId, loanId int, terms *domain.ContractTerms) (*domain.Loan, error) {
+	if terms == nil {
+		return nil, errors.New("a contract must be agreed with the player")
+	}
+
+	if err := ls.windowService.EnsureOpen(time.Now()); err != nil {
+		return nil, err
+	}
+
+	loan, team, err := ls.partyLoan(accountId, loanId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case loan.BorrowerId != team.Id:
+		return nil, errors.New("only the borrowing team can exercise the buy option")
+	case loan.BuyOptionPrice == nil:
+		return nil, errors.New("loan has no buy option")
+	case loan.Status != domain.LoanActive:
+		return nil, errors.New("loan is not active")
+	case team.AvailableCash < *loan.BuyOptionPrice:
+		return nil, errors.New("insufficient funds")
+	}
+
+	player, err := ls.playerRepository.GetPlayer(loan.PlayerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	contract, err := negotiateContract(player, *terms, valuation.WageDemand(player.MarketValue, player.Age), ls.gameConfig, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ls.loanRepository.BuyLoanedPlayer(loanId, team.Id, newMarketValue(*loan.BuyOptionPrice), contract, now); err != nil {
+		return nil, err
+	}
+
+	return ls.getLoan(loanId)
+}
+
+func (ls *LoanService) ReturnDueLoans(now time.Time) (returned int, err error) {
+	loanIds, err := ls.loanRepository.FindDueLoanIds(now)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var failed []string
+
+	for _, loanId := range loanIds {
+		if err = ls.loanRepository.ReturnLoan(loanId, now); err != nil {
+			failed = append(failed, fmt.Sprintf("%d: %v", loanId, err))
+			continue
+		}
+
+		returned++
+	}
+
+	if len(failed) > 0 {
+		return returned, errors.New("loans not returned: " + strings.Join(failed, "; "))
+	}
+
+	return returned, nil
+}
+
+func (ls *LoanService) partyLoan(accountId, loanId int) (domain.Loan, *domain.Team, error) {
+	team, err := ls.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return domain.Loan{}, nil, err
+	}
+
+	loan, err := ls.loanRepository.GetLoan(loanId)
+
+	if err != nil {
+		return domain.Loan{}, nil, err
+	}
+
+	if loan.LenderId != team.Id && loan.BorrowerId != team.Id {
+		return domain.Loan{}, nil, sql.ErrNoRows
+	}
+
+	return loan, team, nil
+}
+
+func (ls *LoanService) getLoan(loanId int) (*domain.Loan, error) {
+	loan, err := ls.loanRepository.GetLoan(loanId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &loan, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/match"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"time"
+)
+
+type MatchService struct {
+	matchRepository  repository.MatchRepository
+	teamRepository   repository.TeamRepository
+	playerRepository repository.PlayerRepository
+}
+
+func NewMatchService(mr repository.MatchRepository, tr repository.TeamRepository, pr repository.PlayerRepository) *MatchService {
+	return &MatchService{
+		matchRepository:  mr,
+		teamRepository:   tr,
+		playerRepository: pr,
+	}
+}
+
+func (ms *MatchService) PlayMatch(accountId, homeTeamId, awayTeamId int, seed *int64) (*domain.Match, error) {
+	if homeTeamId == awayTeamId {
+		return nil, errors.New("a team cannot play against itself")
+	}
+
+	team, err := ms.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, errors.New("account has no team")
+	}
+
+	if team.Id != homeTeamId && team.Id != awayTeamId {
+		return nil, errors.New("matches can only be played by the account's own team")
+	}
+
+	home, err := ms.getLineUp(homeTeamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	away, err := ms.getLineUp(awayTeamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if seed == nil {
+		s := now.UnixNano()
+		seed = &s
+	}
+
+	played, err := match.Simulate(*home, *away, *seed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	played.PlayedAt = now
+
+	if err = ms.matchRepository.CreateMatch(played); err != nil {
+		return nil, err
+	}
+
+	return played, nil
+}
+
+func (ms *MatchService) getLineUp(teamId int) (*domain.Team, error) {
+	team, err := ms.teamRepository.GetTeamById(teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	team.Players, err = ms.playerRepository.GetPlayersByTeamId(teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+func (ms *MatchService) GetMatch(matchId int) (*domain.Match, error) {
+	m, err := ms.matchRepository.GetMatch(matchId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (ms *MatchService) GetTeamMatches(teamId int) ([]domain.Match, error) {
+	if _, err := ms.teamRepository.GetTeamById(teamId); err != nil {
+		return nil, err
+	}
+
+	matches, err := ms.matchRepository.FindMatchesByTeamId(teamId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(make([]domain.Match, 0, len(matches)), matches...), nil
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/valuation"
+	"time"
+)
+
+type OfferService struct {
+	offerRepository  repository.OfferRepository
+	playerRepository repository.PlayerRepository
+	teamRepository   repository.TeamRepository
+	windowService    *TransferWindowService
+	gameConfig       config.GameConfig
+}
+
+func NewOfferService(ofr repository.OfferRepository, pr repository.PlayerRepository, tr repository.TeamRepository, ws *TransferWindowService, gc config.GameConfig) *OfferService {
+	return &OfferService{
+		offerRepository:  ofr,
+		playerRepository: pr,
+		teamRepository:   tr,
+		windowService:    ws,
+		gameConfig:       gc,
+	}
+}
+
+var offerStatuses = map[string]bool{
+	domain.OfferOpen:      true,
+	domain.OfferAccepted:  true,
+	domain.OfferRejected:  true,
+	domain.OfferWithdrawn: true,
+	domain.OfferExpired:   true,
+	domain.OfferCancelled: true,
+}
+
+func (ofs *OfferService) MakeOffer(accountId, playerId, amount int, terms *domain.ContractTerms) (*domain.Offer, error) {
+	if amount <= 0 {
+		return nil, errors.New("offer amount must be positive")
+	}
+
+	if terms == nil {
+		return nil, errors.New("a contract must be agreed with the player")
+	}
+
+	if err := ofs.windowService.EnsureOpen(time.Now()); err != nil {
+		return nil, err
+	}
+
+	buyer, err := ofs.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := ofs.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case player.TeamId == nil:
+		return nil, errors.New("free agents cannot receive offers, sign them instead")
+	case *player.TeamId == buyer.Id:
+		return nil, errors.New("teams cannot make offers for their own players")
+	case buyer.AvailableCash < amount:
+		return nil, errors.New("insufficient funds")
+	}
+
+	now := time.Now()
+
+	if _, err = negotiateContract(player, *terms, valuation.WageDemand(player.MarketValue, player.Age), ofs.gameConfig, now); err != nil {
+		return nil, err
+	}
+
+	open, err := ofs.offerRepository.FindOffers(buyer.Id, domain.OfferOpen)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, offer := range open {
+		if offer.PlayerId == playerId && offer.BuyerId == buyer.Id && offer.ExpiresAt.After(now) {
+			return nil, fmt.Errorf("offer %d for this player is still open", offer.Id)
+		}
+	}
+
+	offer := &domain.Offer{
+		PlayerId:  playerId,
+		BuyerId:   buyer.Id,
+		SellerId:  *player.TeamId,
+		Amount:    amount,
+		ExpiresAt: now.Add(ofs.gameConfig.OfferTTL.Duration),
+		CreatedAt: now,
+		Contract:  terms,
+	}
+
+	if err = ofs.offerRepository.CreateOffer(offer); err != nil {
+		return nil, err
+	}
+
+	return ofs.getOffer(offer.Id)
+}
+
+func (ofs *OfferService) GetOffers(accountId int, status string) ([]domain.Offer, error) {
+	if status != "" && !offerStatuses[status] {
+		return nil, fmt.Errorf("invalid status: %s", status)
+	}
+
+	offers := make([]domain.Offer, 0)
+	team, err := ofs.teamRepository.GetTeamByAccountId(accountId)
+
+	if err == sql.ErrNoRows {
+		return offers, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored := status
+
+	if status == domain.OfferExpired {
+		stored = domain.OfferOpen
+	}
+
+	found, err := ofs.offerRepository.FindOffers(team.Id, stored)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	for _, offer := range found {
+		expireOffer(&offer, now)
+
+		if status == "" || offer.Status == status {
+			offers = append(offers, offer)
+		}
+	}
+
+	return offers, nil
+}
+
+func (ofs *OfferService) GetOffer(user domain.User, offerId int) (*domain.Offer, error) {
+	if user.Profile == domain.AdminProfile {
+		return ofs.getOffer(offerId)
+	}
+
+	offer, _, err := ofs.partyOffer(user.AccountId, offerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ofs.getOffer(offer.Id)
+}
+
+func (ofs *OfferService) CounterOffer(accountId, offerId, amount int) (*domain.Offer, error) {
+	if amount <= 0 {
+		return nil, errors.New("offer amount must be positive")
+	}
+
+	_, team, err := ofs.partyOffer(accountId, offerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if err = ofs.offerRepository.CounterOffer(offerId, team.Id, amount, now.Add(ofs.gameConfig.OfferTTL.Duration), now); err != nil {
+		return nil, err
+	}
+
+	return ofs.getOffer(offerId)
+}
+
+func (ofs *OfferService) AcceptOffer(accountId, offerId int) (*domain.Offer, error) {
+	if err := ofs.windowService.EnsureOpen(time.Now()); err != nil {
+		return nil, err
+	}
+
+	offer, team, err := ofs.partyOffer(accountId, offerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.AwaitingTeamId != team.Id {
+		return nil, errors.New("offer is awaiting a response from the other team")
+	}
+
+	buyer, err := ofs.teamRepository.GetTeamById(offer.BuyerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if buyer.AvailableCash < offer.Amount {
+		return nil, errors.New("buying team has insufficient funds")
+	}
+
+	player, err := ofs.playerRepository.GetPlayer(offer.PlayerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	contract := agreedContract(player, offer.Contract, ofs.gameConfig, now)
+
+	if err = ofs.offerRepository.AcceptOffer(offerId, team.Id, newMarketValue(offer.Amount), contract, now); err != nil {
+		return nil, err
+	}
+
+	return ofs.getOffer(offerId)
+}
+
+func (ofs *OfferService) RejectOffer(accountId, offerId int) (*domain.Offer, error) {
+	offer, team, err := ofs.partyOffer(accountId, offerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.AwaitingTeamId != team.Id {
+		return nil, errors.New("offer is awaiting a response from the other team")
+	}
+
+	if err = ofs.offerRepository.CloseOffer(offerId, team.Id, domain.OfferRejected, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ofs.getOffer(offerId)
+}
+
+func (ofs *OfferService) WithdrawOffer(accountId, offerId int) (*domain.Offer, error) {
+	offer, team, err := ofs.partyOffer(accountId, offerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.BuyerId != team.Id {
+		return nil, errors.New("only the buying team can withdraw an offer")
+	}
+
+	if err = ofs.offerRepository.CloseOffer(offerId, team.Id, domain.OfferWithdrawn, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ofs.getOffer(offerId)
+}
+
+func (ofs *OfferService) partyOffer(accountId, offerId int) (domain.Offer, *domain.Team, error) {
+	team, err := ofs.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return domain.Offer{}, nil, err
+	}
+
+	offer, err := ofs.offerRepository.GetOffer(offerId)
+
+	if err != nil {
+		return domain.Offer{}, nil, err
+	}
+
+	if offer.BuyerId != team.Id && offer.SellerId != team.Id {
+		return domain.Offer{}, nil, sql.ErrNoRows
+	}
+
+	return offer, team, nil
+}
+
+func (ofs *OfferService) getOffer(offerId int) (*domain.Offer, error) {
+	offer, err := ofs.offerRepository.GetOffer(offerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expireOffer(&offer, time.Now())
+	return &offer, nil
+}
+
+func expireOffer(offer *domain.Offer, now time.Time) {
+	if offer.Status == domain.OfferOpen && !offer.ExpiresAt.After(now) {
+		offer.Status = domain.OfferExpired
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository/memory"
+	"testing"
+	"time"
+)
+
+func TestExpireOffer(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		status    string
+		expiresAt time.Time
+		expected  string
+	}{
+		{"open before expiry", domain.OfferOpen, now.Add(time.Minute), domain.OfferOpen},
+		{"open at expiry", domain.OfferOpen, now, domain.OfferExpired},
+		{"open after expiry", domain.OfferOpen, now.Add(-time.Minute), domain.OfferExpired},
+		{"accepted after expiry", domain.OfferAccepted, now.Add(-time.Minute), domain.OfferAccepted},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			offer := domain.Offer{Status: test.status, ExpiresAt: test.expiresAt}
+			expireOffer(&offer, now)
+
+			if offer.Status != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, offer.Status)
+			}
+		})
+	}
+}
+
+func TestExpiredOffersCannotBeAccepted(t *testing.T) {
+	repositories := memory.NewRepositories()
+	gc := config.Default().Game
+	ws := NewTransferWindowService(repositories.Windows, repositories.Transfers, repositories.Leagues, gc)
+	ofs := NewOfferService(repositories.Offers, repositories.Players, repositories.Teams, ws, gc)
+	seller := newTestAccount(t, repositories, "seller@example.com", 0)
+	buyer := newTestAccount(t, repositories, "buyer@example.com", 5000000)
+	createdAt := time.Now().Add(-gc.OfferTTL.Duration - time.Hour)
+
+	offer := &domain.Offer{
+		PlayerId:  seller.Team.Players[0].Id,
+		BuyerId:   buyer.Team.Id,
+		SellerId:  seller.Team.Id,
+		Amount:    1000000,
+		ExpiresAt: createdAt.Add(gc.OfferTTL.Duration),
+		CreatedAt: createdAt,
+		Contract:  &domain.ContractTerms{Wage: 10000, Years: 2},
+	}
+
+	if err := repositories.Offers.CreateOffer(offer); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := ofs.GetOffers(seller.Id, domain.OfferExpired)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(expired) != 1 || expired[0].Id != offer.Id {
+		t.Fatalf("expected the offer to be listed as expired, got %+v", expired)
+	}
+
+	if _, err = ofs.AcceptOffer(seller.Id, offer.Id); err == nil || err.Error() != "offer is no longer open" {
+		t.Fatalf("expected an expired offer not to be accepted, got %v", err)
+	}
+
+	open, err := ofs.GetOffers(buyer.Id, domain.OfferOpen)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(open) != 0 {
+		t.Fatalf("expected no open offers, got %+v", open)
+	}
+}
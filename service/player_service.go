@@ -8,18 +8,31 @@ import (
 )
 
 type PlayerService struct {
-	playerRepository *repository.PlayerRepository
+	playerRepository   repository.PlayerRepository
+	contractRepository repository.ContractRepository
 }
 
-func NewPlayerService(pr *repository.PlayerRepository) *PlayerService {
+func NewPlayerService(pr repository.PlayerRepository, cr repository.ContractRepository) *PlayerService {
 	return &PlayerService{
-		playerRepository: pr,
+		playerRepository:   pr,
+		contractRepository: cr,
 	}
 }
 
 func (ps *PlayerService) GetPlayer(playerId int) (*domain.Player, error) {
 	player, err := ps.playerRepository.GetPlayer(playerId)
-	return &player, err
+
+	if err != nil {
+		return &player, err
+	}
+
+	contract, err := ps.contractRepository.GetContract(playerId)
+
+	if err == nil {
+		player.Contract = &contract
+	}
+
+	return &player, nil
 }
 
 func (ps *PlayerService) UpdatePlayer(accountId int, playerId int, patchJSON []byte) (*domain.Player, error) {
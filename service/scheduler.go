@@ -0,0 +1,65 @@
+package service
+
+import (
+	"log"
+	"time"
+)
+
+type Task struct {
+	Report string
+	Run    func(now time.Time) (int, error)
+}
+
+type Scheduler struct {
+	task     Task
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewScheduler(task Task, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		task:     task,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (s *Scheduler) Start() {
+	go s.run()
+}
+
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.execute()
+
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Scheduler) execute() {
+	processed, err := s.task.Run(time.Now())
+
+	if processed > 0 {
+		log.Printf(s.task.Report, processed)
+	}
+
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReuse   = errors.New("refresh token reuse detected, session revoked")
+)
+
+type SessionService struct {
+	sessionRepository repository.SessionRepository
+	refreshTokenTTL   time.Duration
+}
+
+func NewSessionService(sr repository.SessionRepository, refreshTokenTTL time.Duration) *SessionService {
+	return &SessionService{
+		sessionRepository: sr,
+		refreshTokenTTL:   refreshTokenTTL,
+	}
+}
+
+func (ss *SessionService) IssueRefreshToken(accountId int, family, accessTokenId string, accessExpiresAt time.Time) (string, error) {
+	if family == "" {
+		family = uuid.New().String()
+	}
+
+	now := time.Now()
+	token := uuid.New().String()
+	err := ss.sessionRepository.CreateRefreshToken(&domain.RefreshToken{
+		AccountId:       accountId,
+		Family:          family,
+		Token:           hashToken(token),
+		AccessTokenId:   accessTokenId,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       now.Add(ss.refreshTokenTTL),
+		CreatedAt:       now,
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (ss *SessionService) ConsumeRefreshToken(token string) (*domain.RefreshToken, error) {
+	now := time.Now()
+	refreshToken, consumed, err := ss.sessionRepository.UseRefreshToken(hashToken(token), now)
+
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if consumed {
+		return &refreshToken, nil
+	}
+
+	if refreshToken.UsedAt != nil || refreshToken.RevokedAt != nil {
+		if err = ss.sessionRepository.RevokeFamily(refreshToken.Family, now); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrRefreshTokenReuse
+	}
+
+	return nil, ErrInvalidRefreshToken
+}
+
+func (ss *SessionService) RevokeSession(user domain.User) error {
+	return ss.sessionRepository.RevokeSession(user.TokenId, user.AccountId, user.TokenExpiresAt, time.Now())
+}
+
+func (ss *SessionService) RevokeAccountSessions(accountId int) error {
+	return ss.sessionRepository.RevokeAccountSessions(accountId, time.Now())
+}
+
+func (ss *SessionService) IsRevoked(accessTokenId string) bool {
+	revoked, err := ss.sessionRepository.IsAccessTokenRevoked(accessTokenId)
+	return err != nil || revoked
+}
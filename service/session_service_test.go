@@ -0,0 +1,94 @@
+package service
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/repository/memory"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenRotation(t *testing.T) {
+	repositories := memory.NewRepositories()
+	ss := NewSessionService(repositories.Sessions, time.Hour)
+	accessExpiresAt := time.Now().Add(time.Minute)
+	first, err := ss.IssueRefreshToken(1, "", "access-1", accessExpiresAt)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	consumed, err := ss.ConsumeRefreshToken(first)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := ss.IssueRefreshToken(consumed.AccountId, consumed.Family, "access-2", accessExpiresAt)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ss.ConsumeRefreshToken(first); err != ErrRefreshTokenReuse {
+		t.Fatalf("expected reuse of a rotated token to be detected, got %v", err)
+	}
+
+	if _, err = ss.ConsumeRefreshToken(second); err == nil {
+		t.Fatal("expected reuse detection to revoke the whole token family")
+	}
+
+	for _, accessTokenId := range []string{"access-1", "access-2"} {
+		if !ss.IsRevoked(accessTokenId) {
+			t.Fatalf("expected access token %s to be revoked", accessTokenId)
+		}
+	}
+}
+
+func TestConsumeRefreshToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		token func(ss *SessionService) string
+		err   error
+	}{
+		{"unknown token", time.Hour, func(ss *SessionService) string { return "unknown" }, ErrInvalidRefreshToken},
+		{"expired token", -time.Minute, func(ss *SessionService) string {
+			token, _ := ss.IssueRefreshToken(1, "", "access", time.Now().Add(time.Minute))
+			return token
+		}, ErrInvalidRefreshToken},
+		{"fresh token", time.Hour, func(ss *SessionService) string {
+			token, _ := ss.IssueRefreshToken(1, "", "access", time.Now().Add(time.Minute))
+			return token
+		}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ss := NewSessionService(memory.NewRepositories().Sessions, test.ttl)
+
+			if _, err := ss.ConsumeRefreshToken(test.token(ss)); err != test.err {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestRevokeAccountSessions(t *testing.T) {
+	ss := NewSessionService(memory.NewRepositories().Sessions, time.Hour)
+	token, err := ss.IssueRefreshToken(1, "", "access", time.Now().Add(time.Minute))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ss.RevokeAccountSessions(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if !ss.IsRevoked("access") {
+		t.Fatal("expected the access token to be revoked")
+	}
+
+	if _, err = ss.ConsumeRefreshToken(token); err == nil {
+		t.Fatal("expected the refresh token to be revoked")
+	}
+}
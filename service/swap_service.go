@@ -0,0 +1,256 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"time"
+)
+
+type SwapService struct {
+	swapRepository   repository.SwapRepository
+	playerRepository repository.PlayerRepository
+	teamRepository   repository.TeamRepository
+	windowService    *TransferWindowService
+	gameConfig       config.GameConfig
+}
+
+func NewSwapService(sr repository.SwapRepository, pr repository.PlayerRepository, tr repository.TeamRepository, ws *TransferWindowService, gc config.GameConfig) *SwapService {
+	return &SwapService{
+		swapRepository:   sr,
+		playerRepository: pr,
+		teamRepository:   tr,
+		windowService:    ws,
+		gameConfig:       gc,
+	}
+}
+
+const maxSwapPlayers = 5
+
+var swapStatuses = map[string]bool{
+	domain.SwapProposed:  true,
+	domain.SwapCompleted: true,
+	domain.SwapRejected:  true,
+	domain.SwapWithdrawn: true,
+	domain.SwapCancelled: true,
+}
+
+func (ss *SwapService) ProposeSwap(accountId, receiverId int, offeredIds, requestedIds []int, cashAdjustment int) (*domain.Swap, error) {
+	if len(offeredIds) == 0 || len(requestedIds) == 0 {
+		return nil, errors.New("a swap needs at least one player from each team")
+	}
+
+	if len(offeredIds) > maxSwapPlayers || len(requestedIds) > maxSwapPlayers {
+		return nil, fmt.Errorf("a swap can include at most %d players from each team", maxSwapPlayers)
+	}
+
+	proposer, err := ss.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if receiverId == proposer.Id {
+		return nil, errors.New("teams cannot swap players with themselves")
+	}
+
+	if _, err = ss.teamRepository.GetTeamById(receiverId); err != nil {
+		return nil, err
+	}
+
+	if cashAdjustment > proposer.AvailableCash {
+		return nil, errors.New("insufficient funds")
+	}
+
+	swap := &domain.Swap{
+		ProposerId:     proposer.Id,
+		ReceiverId:     receiverId,
+		CashAdjustment: cashAdjustment,
+		ProposedAt:     time.Now(),
+	}
+
+	seen := map[int]bool{}
+
+	sides := []struct {
+		teamId    int
+		playerIds []int
+	}{{proposer.Id, offeredIds}, {receiverId, requestedIds}}
+
+	for _, side := range sides {
+		teamId := side.teamId
+
+		for _, playerId := range side.playerIds {
+			if seen[playerId] {
+				return nil, fmt.Errorf("player %d is included more than once", playerId)
+			}
+
+			seen[playerId] = true
+			player, err := ss.playerRepository.GetPlayer(playerId)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if player.TeamId == nil || *player.TeamId != teamId {
+				return nil, fmt.Errorf("player %d does not belong to team %d", playerId, teamId)
+			}
+
+			swap.Players = append(swap.Players, domain.SwapPlayer{PlayerId: playerId, FromTeamId: teamId})
+		}
+	}
+
+	if err = ss.swapRepository.CreateSwap(swap); err != nil {
+		return nil, err
+	}
+
+	return ss.getSwap(swap.Id)
+}
+
+func (ss *SwapService) GetSwaps(accountId int, status string) ([]domain.Swap, error) {
+	if status != "" && !swapStatuses[status] {
+		return nil, fmt.Errorf("invalid status: %s", status)
+	}
+
+	swaps := make([]domain.Swap, 0)
+	team, err := ss.teamRepository.GetTeamByAccountId(accountId)
+
+	if err == sql.ErrNoRows {
+		return swaps, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := ss.swapRepository.FindSwaps(team.Id, status)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(swaps, found...), nil
+}
+
+func (ss *SwapService) GetSwap(user domain.User, swapId int) (*domain.Swap, error) {
+	if user.Profile == domain.AdminProfile {
+		return ss.getSwap(swapId)
+	}
+
+	swap, _, err := ss.partySwap(user.AccountId, swapId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &swap, nil
+}
+
+func (ss *SwapService) AcceptSwap(accountId, swapId int) (*domain.Swap, error) {
+	if err := ss.windowService.EnsureOpen(time.Now()); err != nil {
+		return nil, err
+	}
+
+	swap, team, err := ss.partySwap(accountId, swapId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if swap.ReceiverId != team.Id {
+		return nil, errors.New("only the receiving team can accept a swap")
+	}
+
+	if -swap.CashAdjustment > team.AvailableCash {
+		return nil, errors.New("insufficient funds")
+	}
+
+	now := time.Now()
+	newMarketValues := make(map[int]int)
+	contracts := make(map[int]*domain.Contract)
+
+	for _, swapped := range swap.Players {
+		player, err := ss.playerRepository.GetPlayer(swapped.PlayerId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		newMarketValues[player.Id] = newMarketValue(swapped.MarketValue)
+		contracts[player.Id] = defaultContract(player, ss.gameConfig, now)
+	}
+
+	if err = ss.swapRepository.CompleteSwap(swapId, team.Id, newMarketValues, contracts, now); err != nil {
+		return nil, err
+	}
+
+	return ss.getSwap(swapId)
+}
+
+func (ss *SwapService) RejectSwap(accountId, swapId int) (*domain.Swap, error) {
+	swap, team, err := ss.partySwap(accountId, swapId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if swap.ReceiverId != team.Id {
+		return nil, errors.New("only the receiving team can reject a swap")
+	}
+
+	if err = ss.swapRepository.CloseSwap(swapId, team.Id, domain.SwapRejected, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ss.getSwap(swapId)
+}
+
+func (ss *SwapService) WithdrawSwap(accountId, swapId int) (*domain.Swap, error) {
+	swap, team, err := ss.partySwap(accountId, swapId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if swap.ProposerId != team.Id {
+		return nil, errors.New("only the proposing team can withdraw a swap")
+	}
+
+	if err = ss.swapRepository.CloseSwap(swapId, team.Id, domain.SwapWithdrawn, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return ss.getSwap(swapId)
+}
+
+func (ss *SwapService) partySwap(accountId, swapId int) (domain.Swap, *domain.Team, error) {
+	team, err := ss.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return domain.Swap{}, nil, err
+	}
+
+	swap, err := ss.swapRepository.GetSwap(swapId)
+
+	if err != nil {
+		return domain.Swap{}, nil, err
+	}
+
+	if swap.ProposerId != team.Id && swap.ReceiverId != team.Id {
+		return domain.Swap{}, nil, sql.ErrNoRows
+	}
+
+	return swap, team, nil
+}
+
+func (ss *SwapService) getSwap(swapId int) (*domain.Swap, error) {
+	swap, err := ss.swapRepository.GetSwap(swapId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &swap, nil
+}
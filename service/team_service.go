@@ -9,14 +9,16 @@ import (
 )
 
 type TeamService struct {
-	teamRepository   *repository.TeamRepository
-	playerRepository *repository.PlayerRepository
+	teamRepository     repository.TeamRepository
+	playerRepository   repository.PlayerRepository
+	contractRepository repository.ContractRepository
 }
 
-func NewTeamService(tr *repository.TeamRepository, pr *repository.PlayerRepository) *TeamService {
+func NewTeamService(tr repository.TeamRepository, pr repository.PlayerRepository, cr repository.ContractRepository) *TeamService {
 	return &TeamService{
-		teamRepository:   tr,
-		playerRepository: pr,
+		teamRepository:     tr,
+		playerRepository:   pr,
+		contractRepository: cr,
 	}
 }
 
@@ -37,7 +39,28 @@ func (ts *TeamService) GetTeam(accountId, teamId int) (*domain.Team, error) {
 		return nil, err
 	}
 
+	contracts, err := ts.contractRepository.FindTeamContracts(team.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range players {
+		for j := range contracts {
+			if contracts[j].PlayerId == players[i].Id {
+				players[i].Contract = &contracts[j]
+			}
+		}
+	}
+
 	team.Players = players
+	summary, err := ts.teamRepository.GetSquadSummary(team.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	team.Summary = &summary
 
 	return team, nil
 }
@@ -72,5 +95,5 @@ func (ts *TeamService) UpdateTeam(accountId, teamId int, patchJSON []byte) (*dom
 		return nil, err
 	}
 
-	return team, nil
+	return ts.GetTeam(accountId, teamId)
 }
@@ -1,36 +1,72 @@
 package service
 
 import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/giancarlobastos/soccer-manager-api/config"
 	"github.com/giancarlobastos/soccer-manager-api/domain"
 	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/valuation"
 	"math/rand"
+	"strconv"
+	"strings"
+	"time"
 )
 
+var errAskedPrice = errors.New("asked price must be positive")
+
 type TransferService struct {
-	transferRepository *repository.TransferRepository
-	playerRepository   *repository.PlayerRepository
-	teamRepository     *repository.TeamRepository
+	transferRepository repository.TransferRepository
+	playerRepository   repository.PlayerRepository
+	teamRepository     repository.TeamRepository
+	windowService      *TransferWindowService
+	gameConfig         config.GameConfig
 }
 
-func NewTransferService(tfr *repository.TransferRepository, pr *repository.PlayerRepository, tr *repository.TeamRepository) *TransferService {
+func NewTransferService(tfr repository.TransferRepository, pr repository.PlayerRepository, tr repository.TeamRepository, ws *TransferWindowService, gc config.GameConfig) *TransferService {
 	return &TransferService{
 		transferRepository: tfr,
 		playerRepository:   pr,
 		teamRepository:     tr,
+		windowService:      ws,
+		gameConfig:         gc,
 	}
 }
 
-func (ts *TransferService) NewTransfer(accountId, playerId, askedPrice int) (transferId int, err error) {
+func (ts *TransferService) NewTransfer(accountId, playerId, askedPrice int, expiresAt *time.Time) (transferId int, err error) {
+	if askedPrice <= 0 {
+		return 0, errAskedPrice
+	}
+
+	now := time.Now()
+	latest := now.Add(ts.gameConfig.ListingMaxDuration.Duration)
+
+	if expiresAt == nil {
+		expiresAt = &latest
+	}
+
+	if !expiresAt.After(now) || expiresAt.After(latest) {
+		return 0, fmt.Errorf("listing expiry must be in the next %s", ts.gameConfig.ListingMaxDuration.Duration)
+	}
+
+	if err = ts.windowService.EnsureOpen(now); err != nil {
+		return 0, err
+	}
+
 	player, err := ts.playerRepository.GetPlayerOutOfTransferList(accountId, playerId)
 
 	if err != nil {
 		return 0, err
 	}
 
-	transferId, err = ts.transferRepository.NewTransfer(playerId, askedPrice, player.MarketValue)
+	expiry := expiresAt.UTC()
+	transferId, err = ts.transferRepository.NewTransfer(playerId, askedPrice, player.MarketValue, &expiry)
 
 	if err != nil {
 		return 0, err
@@ -39,7 +75,17 @@ func (ts *TransferService) NewTransfer(accountId, playerId, askedPrice int) (tra
 	return transferId, err
 }
 
-func (ts *TransferService) ConfirmTransfer(accountId, transferId int) error {
+func (ts *TransferService) ConfirmTransfer(accountId, transferId int, terms *domain.ContractTerms) error {
+	if terms == nil {
+		return errors.New("a contract must be agreed with the player")
+	}
+
+	now := time.Now()
+
+	if err := ts.windowService.EnsureOpen(now); err != nil {
+		return err
+	}
+
 	transfer, err := ts.transferRepository.GetTransfer(transferId)
 
 	if err != nil {
@@ -52,6 +98,10 @@ func (ts *TransferService) ConfirmTransfer(accountId, transferId int) error {
 		return err
 	}
 
+	if transfer.Mode == domain.TransferAuction {
+		return errors.New("auction listings are sold to the highest bidder")
+	}
+
 	if *transfer.Player.TeamId == buyer.Id {
 		return errors.New("destination team cannot be the same as the origin team")
 	}
@@ -60,13 +110,393 @@ func (ts *TransferService) ConfirmTransfer(accountId, transferId int) error {
 		return errors.New("insufficient funds")
 	}
 
-	newMarketValue := (transfer.AskedPrice * (rand.Intn(191) + 110)) / 100
-	return ts.transferRepository.ConfirmTransfer(transferId, buyer.Id, newMarketValue)
+	player := transfer.Player
+	contract, err := negotiateContract(player, *terms, valuation.WageDemand(player.MarketValue, player.Age), ts.gameConfig, now)
+
+	if err != nil {
+		return err
+	}
+
+	return ts.transferRepository.ConfirmTransfer(transferId, buyer.Id, newMarketValue(transfer.AskedPrice), contract, now)
+}
+
+func newMarketValue(price int) int {
+	return (price * (rand.Intn(191) + 110)) / 100
+}
+
+func (ts *TransferService) NewAuction(accountId, playerId, reservePrice int, deadline time.Time, sealed bool) (transferId int, err error) {
+	if reservePrice <= 0 {
+		return 0, errors.New("reserve price must be positive")
+	}
+
+	if now := time.Now(); !deadline.After(now) || deadline.Sub(now) > ts.gameConfig.MaxAuctionDuration.Duration {
+		return 0, fmt.Errorf("auction deadline must be in the next %s", ts.gameConfig.MaxAuctionDuration.Duration)
+	}
+
+	if err = ts.windowService.EnsureOpen(time.Now()); err != nil {
+		return 0, err
+	}
+
+	player, err := ts.playerRepository.GetPlayerOutOfTransferList(accountId, playerId)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return ts.transferRepository.NewAuction(playerId, reservePrice, player.MarketValue, domain.Auction{Deadline: deadline.UTC(), Sealed: sealed})
+}
+
+func (ts *TransferService) PlaceBid(accountId, transferId, amount int, terms *domain.ContractTerms) (domain.Bid, error) {
+	if terms == nil {
+		return domain.Bid{}, errors.New("a contract must be agreed with the player")
+	}
+
+	if err := ts.windowService.EnsureOpen(time.Now()); err != nil {
+		return domain.Bid{}, err
+	}
+
+	transfer, err := ts.transferRepository.GetTransfer(transferId)
+
+	if err != nil {
+		return domain.Bid{}, err
+	}
+
+	if transfer.Mode != domain.TransferAuction {
+		return domain.Bid{}, errors.New("listing is not an auction")
+	}
+
+	bidder, err := ts.teamRepository.GetTeamByAccountId(accountId)
+
+	if err != nil {
+		return domain.Bid{}, err
+	}
+
+	auction := transfer.Auction
+
+	switch {
+	case *transfer.Player.TeamId == bidder.Id:
+		return domain.Bid{}, errors.New("teams cannot bid on their own players")
+	case !time.Now().Before(auction.Deadline):
+		return domain.Bid{}, errors.New("auction has closed")
+	case amount < transfer.AskedPrice:
+		return domain.Bid{}, fmt.Errorf("bid must be at least the reserve price of %d", transfer.AskedPrice)
+	case !auction.Sealed && auction.HighestBid != nil && amount <= *auction.HighestBid:
+		return domain.Bid{}, fmt.Errorf("bid must beat the highest bid of %d", *auction.HighestBid)
+	}
+
+	player := transfer.Player
+
+	if _, err = negotiateContract(player, *terms, valuation.WageDemand(player.MarketValue, player.Age), ts.gameConfig, time.Now()); err != nil {
+		return domain.Bid{}, err
+	}
+
+	return ts.transferRepository.PlaceBid(transferId, bidder.Id, amount, *terms, time.Now())
+}
+
+func (ts *TransferService) GetBids(user domain.User, transferId int) ([]domain.Bid, error) {
+	transfer, err := ts.transferRepository.GetTransfer(transferId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Mode != domain.TransferAuction {
+		return nil, errors.New("listing is not an auction")
+	}
+
+	bids, err := ts.transferRepository.FindBids(transferId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]domain.Bid, 0, len(bids))
+
+	if user.Profile == domain.AdminProfile {
+		return append(visible, bids...), nil
+	}
+
+	bidderId := 0
+
+	if bidder, err := ts.teamRepository.GetTeamByAccountId(user.AccountId); err == nil {
+		bidderId = bidder.Id
+	}
+
+	for _, bid := range bids {
+		if bid.TeamId != bidderId {
+			if transfer.Auction.Sealed {
+				continue
+			}
+
+			bid.Contract = nil
+		}
+
+		visible = append(visible, bid)
+	}
+
+	return visible, nil
+}
+
+func (ts *TransferService) SettleDueAuctions(now time.Time) (settled int, err error) {
+	transferIds, err := ts.transferRepository.FindDueAuctionIds(now)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var failed []string
+
+	for _, transferId := range transferIds {
+		if err = ts.settleAuction(transferId, now); err != nil {
+			failed = append(failed, fmt.Sprintf("%d: %v", transferId, err))
+			continue
+		}
+
+		settled++
+	}
+
+	if len(failed) > 0 {
+		return settled, errors.New("auctions not settled: " + strings.Join(failed, "; "))
+	}
+
+	return settled, nil
+}
+
+func (ts *TransferService) ExpireDueListings(now time.Time) (int, error) {
+	return ts.transferRepository.ExpireDueListings(now)
+}
+
+func (ts *TransferService) settleAuction(transferId int, now time.Time) error {
+	bids, err := ts.transferRepository.FindBids(transferId)
+
+	if err != nil {
+		return err
+	}
+
+	for _, bid := range bids {
+		if bid.Status != domain.BidActive {
+			continue
+		}
+
+		transfer, err := ts.transferRepository.GetTransfer(transferId)
+
+		if err != nil {
+			return err
+		}
+
+		contract := agreedContract(transfer.Player, bid.Contract, ts.gameConfig, now)
+		return ts.transferRepository.SettleAuction(transferId, bid.Id, newMarketValue(bid.Amount), contract, now)
+	}
+
+	return ts.transferRepository.SettleAuction(transferId, 0, 0, nil, now)
+}
+
+func (ts *TransferService) WithdrawTransfer(user domain.User, transferId int) error {
+	transfer, err := ts.transferRepository.GetTransfer(transferId)
+
+	if err != nil {
+		return err
+	}
+
+	if user.Profile != domain.AdminProfile {
+		seller, err := ts.teamRepository.GetTeamByAccountId(user.AccountId)
+
+		if err != nil || seller.Id != *transfer.Player.TeamId {
+			return sql.ErrNoRows
+		}
+	}
+
+	if transfer.Auction != nil && transfer.Auction.Bids > 0 {
+		return errors.New("auction already has bids")
+	}
+
+	return ts.transferRepository.WithdrawTransfer(transferId, *transfer.Player.TeamId, time.Now())
+}
+
+func (ts *TransferService) GetTransfers(filter domain.TransferFilter) (*domain.TransferPage, error) {
+	if err := validateTransferFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	lookahead := filter
+	lookahead.Limit++
+	transfers, total, err := ts.transferRepository.FindTransfers(lookahead)
+
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.TransferPage{
+		Transfers: make([]domain.Transfer, 0, len(transfers)),
+		Total:     total,
+	}
+
+	if len(transfers) > filter.Limit {
+		transfers = transfers[:filter.Limit]
+		page.NextCursor = encodeTransferCursor(filter, transfers[len(transfers)-1])
+	}
+
+	page.Transfers = append(page.Transfers, transfers...)
+
+	return page, nil
+}
+
+func (ts *TransferService) GetTransferHistory(filter domain.TransferHistoryFilter) ([]domain.CompletedTransfer, error) {
+	if filter.TeamId < 0 || filter.PlayerId < 0 {
+		return nil, errors.New("team and player filters cannot be negative")
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errors.New("history date range must start before it ends")
+	}
+
+	if filter.Status != "" && filter.Status != domain.TransferTransferred && filter.Status != domain.TransferExpired &&
+		filter.Status != domain.TransferWithdrawn {
+		return nil, fmt.Errorf("invalid status: %s", filter.Status)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTransferPageSize
+	} else if filter.Limit > maxTransferPageSize {
+		filter.Limit = maxTransferPageSize
+	}
+
+	transfers, err := ts.transferRepository.FindCompletedTransfers(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(make([]domain.CompletedTransfer, 0, len(transfers)), transfers...), nil
+}
+
+func (ts *TransferService) GetPlayerCareer(playerId int) (*domain.PlayerCareer, error) {
+	player, err := ts.playerRepository.GetPlayer(playerId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := ts.transferRepository.FindCompletedTransfers(domain.TransferHistoryFilter{PlayerId: playerId, Status: domain.TransferTransferred})
+
+	if err != nil {
+		return nil, err
+	}
+
+	career := &domain.PlayerCareer{
+		Player:    player,
+		Transfers: make([]domain.CompletedTransfer, 0, len(transfers)),
+	}
+
+	for i := len(transfers) - 1; i >= 0; i-- {
+		career.Transfers = append(career.Transfers, transfers[i])
+	}
+
+	return career, nil
+}
+
+func validateTransferFilter(filter *domain.TransferFilter) error {
+	if filter.SortBy == "" {
+		filter.SortBy = "id"
+	}
+
+	if _, ok := transferSortValues[filter.SortBy]; !ok {
+		return fmt.Errorf("invalid sort field: %s", filter.SortBy)
+	}
+
+	if filter.Position != "" && !domain.IsValidPosition(filter.Position) {
+		return fmt.Errorf("invalid position: %s", filter.Position)
+	}
+
+	if filter.Mode != "" && filter.Mode != domain.TransferFixedPrice && filter.Mode != domain.TransferAuction {
+		return fmt.Errorf("invalid mode: %s", filter.Mode)
+	}
+
+	if filter.MinAge < 0 || filter.MaxAge < 0 || filter.MinAskedPrice < 0 || filter.MaxAskedPrice < 0 ||
+		filter.MinMarketValue < 0 || filter.MaxMarketValue < 0 {
+		return errors.New("range filters cannot be negative")
+	}
+
+	if (filter.MaxAge > 0 && filter.MinAge > filter.MaxAge) ||
+		(filter.MaxAskedPrice > 0 && filter.MinAskedPrice > filter.MaxAskedPrice) ||
+		(filter.MaxMarketValue > 0 && filter.MinMarketValue > filter.MaxMarketValue) {
+		return errors.New("range filters must have min lower than max")
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTransferPageSize
+	} else if filter.Limit > maxTransferPageSize {
+		filter.Limit = maxTransferPageSize
+	}
+
+	if filter.Cursor != "" {
+		after, err := decodeTransferCursor(*filter)
+
+		if err != nil {
+			return err
+		}
+
+		filter.After = after
+	}
+
+	return nil
+}
+
+const (
+	defaultTransferPageSize = 20
+	maxTransferPageSize     = 100
+)
+
+var transferSortValues = map[string]func(domain.Transfer) int{
+	"id":          func(t domain.Transfer) int { return t.Id },
+	"askedPrice":  func(t domain.Transfer) int { return t.AskedPrice },
+	"marketValue": func(t domain.Transfer) int { return t.MarketValue },
+	"age":         func(t domain.Transfer) int { return int(t.Player.Age) },
+}
+
+func encodeTransferCursor(filter domain.TransferFilter, last domain.Transfer) string {
+	cursor := fmt.Sprintf("%s:%t:%d:%d:%s", filter.SortBy, filter.Descending, transferSortValues[filter.SortBy](last), last.Id,
+		transferFilterHash(filter))
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
+}
+
+func transferFilterHash(filter domain.TransferFilter) string {
+	filter.Cursor, filter.After, filter.Limit = "", nil, 0
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%+v", filter)))
+	return hex.EncodeToString(sum[:8])
 }
 
-func (ts *TransferService) GetTransfers() ([]domain.Transfer, error) {
-	transfers, err := ts.transferRepository.FindTransfers()
-	return transfers, err
+func decodeTransferCursor(filter domain.TransferFilter) (*domain.TransferCursor, error) {
+	invalid := errors.New("invalid cursor")
+	decoded, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+
+	if err != nil {
+		return nil, invalid
+	}
+
+	parts := strings.Split(string(decoded), ":")
+
+	if len(parts) != 5 {
+		return nil, invalid
+	}
+
+	if parts[0] != filter.SortBy || parts[1] != strconv.FormatBool(filter.Descending) || parts[4] != transferFilterHash(filter) {
+		return nil, errors.New("cursor does not match the query parameters")
+	}
+
+	value, err := strconv.Atoi(parts[2])
+
+	if err != nil {
+		return nil, invalid
+	}
+
+	id, err := strconv.Atoi(parts[3])
+
+	if err != nil {
+		return nil, invalid
+	}
+
+	return &domain.TransferCursor{Value: value, Id: id}, nil
 }
 
 func (ts *TransferService) GetTransfer(transferId int) (*domain.Transfer, error) {
@@ -81,6 +511,10 @@ func (ts *TransferService) UpdateTransfer(accountId int, transferId int, patchJS
 		return nil, err
 	}
 
+	if transfer.Mode == domain.TransferAuction {
+		return nil, errors.New("auction listings cannot be repriced")
+	}
+
 	patch, err := jsonpatch.DecodePatch(patchJSON)
 
 	if err != nil {
@@ -98,6 +532,10 @@ func (ts *TransferService) UpdateTransfer(accountId int, transferId int, patchJS
 		return nil, err
 	}
 
+	if transfer.AskedPrice <= 0 {
+		return nil, errAskedPrice
+	}
+
 	err = ts.transferRepository.UpdateTransfer(accountId, transfer)
 
 	if err != nil {
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"fmt"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"github.com/giancarlobastos/soccer-manager-api/repository/memory"
+	"testing"
+	"time"
+)
+
+func newTransferService(enforced bool) (*TransferService, repository.Repositories) {
+	repositories := memory.NewRepositories()
+	gc := config.Default().Game
+	gc.TransferWindowsEnforced = enforced
+	ws := NewTransferWindowService(repositories.Windows, repositories.Transfers, repositories.Leagues, gc)
+
+	return NewTransferService(repositories.Transfers, repositories.Players, repositories.Teams, ws, gc), repositories
+}
+
+func TestGetBidsVisibility(t *testing.T) {
+	tests := []struct {
+		name          string
+		sealed        bool
+		profile       string
+		bids          int
+		rivalContract bool
+	}{
+		{"open auction as a bidder", false, domain.UserProfile, 2, false},
+		{"sealed auction as a bidder", true, domain.UserProfile, 1, false},
+		{"open auction as an admin", false, domain.AdminProfile, 2, true},
+		{"sealed auction as an admin", true, domain.AdminProfile, 2, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts, repositories := newTransferService(false)
+			seller := newTestAccount(t, repositories, "seller@example.com", 0)
+			bidder := newTestAccount(t, repositories, "bidder@example.com", 5000000)
+			rival := newTestAccount(t, repositories, "rival@example.com", 5000000)
+			terms := &domain.ContractTerms{Wage: 10000, Years: 2}
+
+			transferId, err := ts.NewAuction(seller.Id, seller.Team.Players[0].Id, 1000000, time.Now().Add(time.Hour), test.sealed)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = ts.PlaceBid(bidder.Id, transferId, 1100000, terms); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = ts.PlaceBid(rival.Id, transferId, 1200000, terms); err != nil {
+				t.Fatal(err)
+			}
+
+			bids, err := ts.GetBids(domain.User{AccountId: bidder.Id, Profile: test.profile}, transferId)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(bids) != test.bids {
+				t.Fatalf("expected %d visible bids, got %d", test.bids, len(bids))
+			}
+
+			for _, bid := range bids {
+				if bid.TeamId == bidder.Team.Id && bid.Contract == nil {
+					t.Fatal("expected bidders to see their own contract terms")
+				}
+
+				if bid.TeamId == rival.Team.Id && (bid.Contract != nil) != test.rivalContract {
+					t.Fatalf("expected rival contract terms visible to be %v", test.rivalContract)
+				}
+			}
+		})
+	}
+}
+
+func TestTransfersRequireAnOpenWindow(t *testing.T) {
+	ts, repositories := newTransferService(true)
+	seller := newTestAccount(t, repositories, "seller@example.com", 0)
+	playerId := seller.Team.Players[0].Id
+
+	if _, err := ts.NewTransfer(seller.Id, playerId, 1000000, nil); err != ErrTransferWindowClosed {
+		t.Fatalf("expected listings to require an open window, got %v", err)
+	}
+
+	if _, err := ts.NewAuction(seller.Id, playerId, 1000000, time.Now().Add(time.Hour), false); err != ErrTransferWindowClosed {
+		t.Fatalf("expected auctions to require an open window, got %v", err)
+	}
+
+	opensAt, closesAt := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	if _, err := ts.windowService.CreateWindow(domain.TransferWindow{Name: "Summer", OpensAt: &opensAt, ClosesAt: &closesAt}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ts.NewTransfer(seller.Id, playerId, 1000000, nil); err != nil {
+		t.Fatalf("expected listings to be allowed in an open window, got %v", err)
+	}
+}
+
+func TestAskedPriceMustBePositive(t *testing.T) {
+	tests := []struct {
+		name  string
+		price int
+		err   error
+	}{
+		{"negative", -1, errAskedPrice},
+		{"zero", 0, errAskedPrice},
+		{"positive", 1, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts, repositories := newTransferService(false)
+			seller := newTestAccount(t, repositories, "seller@example.com", 0)
+
+			if _, err := ts.NewTransfer(seller.Id, seller.Team.Players[0].Id, test.price, nil); err != test.err {
+				t.Fatalf("expected %v when listing, got %v", test.err, err)
+			}
+
+			listed := newTestAccount(t, repositories, "listed@example.com", 0)
+			transferId, err := ts.NewTransfer(listed.Id, listed.Team.Players[0].Id, 1000000, nil)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			patch := fmt.Sprintf(`[{"op": "replace", "path": "/askedPrice", "value": %d}]`, test.price)
+
+			if _, err = ts.UpdateTransfer(listed.Id, transferId, []byte(patch)); err != test.err {
+				t.Fatalf("expected %v when repricing, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestTransferCursorMatchesQuery(t *testing.T) {
+	ts, repositories := newTransferService(false)
+
+	for i := 1; i <= 3; i++ {
+		seller := newTestAccount(t, repositories, fmt.Sprintf("seller%d@example.com", i), 0)
+
+		if _, err := ts.NewTransfer(seller.Id, seller.Team.Players[0].Id, i*1000000, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filter := domain.TransferFilter{Position: domain.Forward, SortBy: "askedPrice", Limit: 1}
+	page, err := ts.GetTransfers(filter)
+
+	if err != nil || page.NextCursor == "" {
+		t.Fatalf("expected a first page with a cursor, got %+v: %v", page, err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(filter *domain.TransferFilter)
+		valid  bool
+	}{
+		{"same query", func(filter *domain.TransferFilter) {}, true},
+		{"different limit", func(filter *domain.TransferFilter) { filter.Limit = 2 }, true},
+		{"different filter", func(filter *domain.TransferFilter) { filter.MinAskedPrice = 2000000 }, false},
+		{"different position", func(filter *domain.TransferFilter) { filter.Position = "" }, false},
+		{"different sort", func(filter *domain.TransferFilter) { filter.SortBy = "marketValue" }, false},
+		{"different direction", func(filter *domain.TransferFilter) { filter.Descending = true }, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := filter
+			next.Cursor = page.NextCursor
+			test.change(&next)
+
+			if _, err := ts.GetTransfers(next); (err == nil) != test.valid {
+				t.Fatalf("expected valid=%v, got %v", test.valid, err)
+			}
+		})
+	}
+}
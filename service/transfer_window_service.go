@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository"
+	"strings"
+	"time"
+)
+
+var ErrTransferWindowClosed = errors.New("the transfer window is closed")
+
+type TransferWindowService struct {
+	windowRepository   repository.TransferWindowRepository
+	transferRepository repository.TransferRepository
+	leagueRepository   repository.LeagueRepository
+	gameConfig         config.GameConfig
+}
+
+func NewTransferWindowService(wr repository.TransferWindowRepository, tfr repository.TransferRepository, lr repository.LeagueRepository, gc config.GameConfig) *TransferWindowService {
+	return &TransferWindowService{
+		windowRepository:   wr,
+		transferRepository: tfr,
+		leagueRepository:   lr,
+		gameConfig:         gc,
+	}
+}
+
+var listingPolicies = map[string]bool{
+	domain.WindowCarryOver: true,
+	domain.WindowExpire:    true,
+}
+
+func (ws *TransferWindowService) GetStatus() (*domain.TransferWindowStatus, error) {
+	windows, err := ws.getWindows(time.Now())
+
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.TransferWindowStatus{
+		Enforced: ws.gameConfig.TransferWindowsEnforced,
+		Open:     !ws.gameConfig.TransferWindowsEnforced || anyOpen(windows),
+		Windows:  windows,
+	}
+
+	return status, nil
+}
+
+func (ws *TransferWindowService) EnsureOpen(now time.Time) error {
+	if !ws.gameConfig.TransferWindowsEnforced {
+		return nil
+	}
+
+	windows, err := ws.getWindows(now)
+
+	if err != nil {
+		return err
+	}
+
+	if !anyOpen(windows) {
+		return ErrTransferWindowClosed
+	}
+
+	return nil
+}
+
+func (ws *TransferWindowService) CreateWindow(window domain.TransferWindow) (*domain.TransferWindow, error) {
+	window.Name = strings.TrimSpace(window.Name)
+
+	if window.Name == "" {
+		return nil, errors.New("transfer window name is required")
+	}
+
+	if window.ListingPolicy == "" {
+		window.ListingPolicy = domain.WindowCarryOver
+	}
+
+	if !listingPolicies[window.ListingPolicy] {
+		return nil, errors.New("listing policy must be CARRY_OVER or EXPIRE")
+	}
+
+	dated := window.OpensAt != nil || window.ClosesAt != nil
+	scheduled := window.LeagueId != nil || window.OpensAfterRound != nil || window.ClosesAfterRound != nil
+
+	switch {
+	case dated && scheduled:
+		return nil, errors.New("a transfer window uses either dates or league rounds, not both")
+	case dated:
+		if window.OpensAt == nil || window.ClosesAt == nil || !window.ClosesAt.After(*window.OpensAt) {
+			return nil, errors.New("a transfer window must close after it opens")
+		}
+
+		opensAt, closesAt := window.OpensAt.UTC(), window.ClosesAt.UTC()
+		window.OpensAt, window.ClosesAt = &opensAt, &closesAt
+	case scheduled:
+		if window.LeagueId == nil || window.OpensAfterRound == nil || window.ClosesAfterRound == nil {
+			return nil, errors.New("a round based transfer window needs a league, an opening round and a closing round")
+		}
+
+		if *window.OpensAfterRound < 0 || *window.ClosesAfterRound <= *window.OpensAfterRound {
+			return nil, errors.New("a transfer window must close after it opens")
+		}
+
+		if _, err := ws.leagueRepository.GetLeague(*window.LeagueId); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("a transfer window needs opening and closing dates or league rounds")
+	}
+
+	window.CreatedAt = time.Now()
+
+	if err := ws.windowRepository.CreateWindow(&window); err != nil {
+		return nil, err
+	}
+
+	return ws.getWindow(window.Id, time.Now())
+}
+
+func (ws *TransferWindowService) OpenWindow(windowId int) (*domain.TransferWindow, error) {
+	return ws.setManualState(windowId, domain.WindowOpen)
+}
+
+func (ws *TransferWindowService) CloseWindow(windowId int) (*domain.TransferWindow, error) {
+	return ws.setManualState(windowId, domain.WindowClosed)
+}
+
+func (ws *TransferWindowService) DeleteWindow(windowId int) error {
+	return ws.windowRepository.DeleteWindow(windowId)
+}
+
+func (ws *TransferWindowService) GetWindows() ([]domain.TransferWindow, error) {
+	return ws.getWindows(time.Now())
+}
+
+func (ws *TransferWindowService) CloseDueWindows(now time.Time) (int, error) {
+	windows, err := ws.getWindows(now)
+
+	if err != nil {
+		return 0, err
+	}
+
+	open := anyOpen(windows)
+	closed := 0
+
+	for _, window := range windows {
+		if window.ClosedAt != nil || !windowEnded(window, now) {
+			continue
+		}
+
+		if window.ListingPolicy == domain.WindowExpire && ws.gameConfig.TransferWindowsEnforced && !open {
+			if _, err = ws.transferRepository.ExpireListings(now); err != nil {
+				return closed, err
+			}
+		}
+
+		if err = ws.windowRepository.MarkWindowClosed(window.Id, now); err != nil {
+			return closed, err
+		}
+
+		closed++
+	}
+
+	return closed, nil
+}
+
+func (ws *TransferWindowService) setManualState(windowId int, state string) (*domain.TransferWindow, error) {
+	if _, err := ws.windowRepository.GetWindow(windowId); err != nil {
+		return nil, err
+	}
+
+	if err := ws.windowRepository.UpdateManualState(windowId, &state); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if _, err := ws.CloseDueWindows(now); err != nil {
+		return nil, err
+	}
+
+	return ws.getWindow(windowId, now)
+}
+
+func (ws *TransferWindowService) getWindow(windowId int, now time.Time) (*domain.TransferWindow, error) {
+	window, err := ws.windowRepository.GetWindow(windowId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	window.Open = windowOpen(window, now)
+	return &window, nil
+}
+
+func (ws *TransferWindowService) getWindows(now time.Time) ([]domain.TransferWindow, error) {
+	found, err := ws.windowRepository.FindWindows()
+
+	if err != nil {
+		return nil, err
+	}
+
+	windows := make([]domain.TransferWindow, 0, len(found))
+
+	for _, window := range found {
+		window.Open = windowOpen(window, now)
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+func anyOpen(windows []domain.TransferWindow) bool {
+	for _, window := range windows {
+		if window.Open {
+			return true
+		}
+	}
+
+	return false
+}
+
+func windowOpen(window domain.TransferWindow, now time.Time) bool {
+	if window.ManualState != nil {
+		return *window.ManualState == domain.WindowOpen
+	}
+
+	if window.OpensAt != nil && window.ClosesAt != nil {
+		return !now.Before(*window.OpensAt) && now.Before(*window.ClosesAt)
+	}
+
+	if window.OpensAfterRound != nil && window.ClosesAfterRound != nil {
+		return window.LeagueStatus != domain.LeagueFinished &&
+			window.LeagueRound >= *window.OpensAfterRound && window.LeagueRound < *window.ClosesAfterRound
+	}
+
+	return false
+}
+
+func windowEnded(window domain.TransferWindow, now time.Time) bool {
+	if window.ManualState != nil {
+		return *window.ManualState == domain.WindowClosed
+	}
+
+	if window.ClosesAt != nil {
+		return !now.Before(*window.ClosesAt)
+	}
+
+	if window.ClosesAfterRound != nil {
+		return window.LeagueStatus == domain.LeagueFinished || window.LeagueRound >= *window.ClosesAfterRound
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/repository/memory"
+	"testing"
+	"time"
+)
+
+func newTransferWindowService(enforced bool) *TransferWindowService {
+	repositories := memory.NewRepositories()
+	gc := config.Default().Game
+	gc.TransferWindowsEnforced = enforced
+
+	return NewTransferWindowService(repositories.Windows, repositories.Transfers, repositories.Leagues, gc)
+}
+
+func TestEnsureOpen(t *testing.T) {
+	now := time.Now()
+	hourAgo, inAnHour, inTwoHours := now.Add(-time.Hour), now.Add(time.Hour), now.Add(2*time.Hour)
+	closed := domain.WindowClosed
+
+	tests := []struct {
+		name     string
+		enforced bool
+		windows  []domain.TransferWindow
+		err      error
+	}{
+		{"not enforced", false, nil, nil},
+		{"not enforced with a future window", false, []domain.TransferWindow{{Name: "Summer", OpensAt: &inAnHour, ClosesAt: &inTwoHours}}, nil},
+		{"enforced without windows", true, nil, ErrTransferWindowClosed},
+		{"enforced with an open window", true, []domain.TransferWindow{{Name: "Summer", OpensAt: &hourAgo, ClosesAt: &inAnHour}}, nil},
+		{"enforced with a future window", true, []domain.TransferWindow{{Name: "Summer", OpensAt: &inAnHour, ClosesAt: &inTwoHours}}, ErrTransferWindowClosed},
+		{"enforced with a manually closed window", true, []domain.TransferWindow{{Name: "Summer", OpensAt: &hourAgo, ClosesAt: &inAnHour, ManualState: &closed}}, ErrTransferWindowClosed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ws := newTransferWindowService(test.enforced)
+
+			for _, window := range test.windows {
+				created, err := ws.CreateWindow(window)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if window.ManualState != nil {
+					if _, err = ws.setManualState(created.Id, *window.ManualState); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			if err := ws.EnsureOpen(now); err != test.err {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestEnsureOpenFollowsManualState(t *testing.T) {
+	ws := newTransferWindowService(true)
+	inAnHour, inTwoHours := time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)
+	window, err := ws.CreateWindow(domain.TransferWindow{Name: "Summer", OpensAt: &inAnHour, ClosesAt: &inTwoHours})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ws.OpenWindow(window.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ws.EnsureOpen(time.Now()); err != nil {
+		t.Fatalf("expected a manually opened window to be open, got %v", err)
+	}
+
+	if _, err = ws.CloseWindow(window.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ws.EnsureOpen(time.Now()); err != ErrTransferWindowClosed {
+		t.Fatalf("expected a manually closed window to be closed, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package squad
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/names"
+	"github.com/giancarlobastos/soccer-manager-api/valuation"
+	"math/rand"
+)
+
+const (
+	minAge   = 18
+	ageRange = 22
+)
+
+type Generator struct {
+	gameConfig config.GameConfig
+	names      names.Generator
+}
+
+func NewGenerator(gc config.GameConfig, ng names.Generator) *Generator {
+	return &Generator{
+		gameConfig: gc,
+		names:      ng,
+	}
+}
+
+func (g *Generator) Generate(country string, seed int64) []domain.Player {
+	rng := rand.New(rand.NewSource(seed))
+	squad := g.gameConfig.Squad
+	players := make([]domain.Player, 0, squad.Size())
+	players = g.generatePlayers(players, country, domain.GoalKeeper, squad.GoalKeepers, rng)
+	players = g.generatePlayers(players, country, domain.Defender, squad.Defenders, rng)
+	players = g.generatePlayers(players, country, domain.Midfielder, squad.Midfielders, rng)
+	return g.generatePlayers(players, country, domain.Forward, squad.Forwards, rng)
+}
+
+func (g *Generator) GeneratePlayers(country string, position domain.PlayerPosition, quantity int, seed int64) []domain.Player {
+	rng := rand.New(rand.NewSource(seed))
+	return g.generatePlayers(make([]domain.Player, 0, quantity), country, position, quantity, rng)
+}
+
+func (g *Generator) generatePlayers(players []domain.Player, country string, position domain.PlayerPosition, quantity int, rng *rand.Rand) []domain.Player {
+	for i := 0; i < quantity; i++ {
+		nationality := g.names.Nationality(country, rng)
+		firstName, lastName := g.names.Name(nationality, rng)
+		age := uint8(rng.Intn(ageRange) + minAge)
+		attributes := valuation.GenerateAttributes(position, age, rng)
+		players = append(players, domain.Player{
+			FirstName:   firstName,
+			LastName:    lastName,
+			Country:     nationality,
+			Age:         age,
+			Position:    position,
+			MarketValue: valuation.MarketValue(position, age, attributes, g.gameConfig.InitialPlayerValue),
+			Attributes:  attributes,
+		})
+	}
+
+	return players
+}
@@ -0,0 +1,82 @@
+package squad
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/config"
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"github.com/giancarlobastos/soccer-manager-api/names"
+	"reflect"
+	"testing"
+)
+
+func newGenerator(t *testing.T) *Generator {
+	ng, err := names.Load()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewGenerator(config.Default().Game, ng)
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	g := newGenerator(t)
+	first := g.Generate("England", 42)
+
+	if !reflect.DeepEqual(first, g.Generate("England", 42)) {
+		t.Fatal("expected the same seed to generate the same squad")
+	}
+
+	if reflect.DeepEqual(first, g.Generate("England", 43)) {
+		t.Fatal("expected different seeds to generate different squads")
+	}
+}
+
+func TestGenerateFollowsSquadComposition(t *testing.T) {
+	g := newGenerator(t)
+	squad := config.Default().Game.Squad
+	players := g.Generate("Brazil", 7)
+	counts := map[domain.PlayerPosition]int{}
+	nationals := 0
+
+	for _, player := range players {
+		counts[player.Position]++
+
+		if player.FirstName == "" || player.LastName == "" || player.Country == "" || player.MarketValue <= 0 {
+			t.Fatalf("unexpected generated player: %+v", player)
+		}
+
+		if player.Age < minAge || player.Age >= minAge+ageRange {
+			t.Fatalf("unexpected age: %+v", player)
+		}
+
+		if player.Country == "Brazil" {
+			nationals++
+		}
+	}
+
+	expected := map[domain.PlayerPosition]int{domain.GoalKeeper: squad.GoalKeepers, domain.Defender: squad.Defenders,
+		domain.Midfielder: squad.Midfielders, domain.Forward: squad.Forwards}
+
+	if !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("expected composition %v, got %v", expected, counts)
+	}
+
+	if nationals < len(players)/2 {
+		t.Fatalf("expected most players to share the team country, got %d of %d", nationals, len(players))
+	}
+}
+
+func TestGeneratePlayers(t *testing.T) {
+	g := newGenerator(t)
+	players := g.GeneratePlayers("Italy", domain.Defender, 4, 11)
+
+	if len(players) != 4 || !reflect.DeepEqual(players, g.GeneratePlayers("Italy", domain.Defender, 4, 11)) {
+		t.Fatalf("expected four reproducible players, got %+v", players)
+	}
+
+	for _, player := range players {
+		if player.Position != domain.Defender || player.TeamId != nil || player.MarketValue <= 0 {
+			t.Fatalf("unexpected generated player: %+v", player)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package valuation
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"math"
+	"math/rand"
+)
+
+const (
+	minAttribute     = 1
+	maxAttribute     = 99
+	averageOverall   = 60.0
+	overallSpread    = 8.0
+	valueElasticity  = 8.0
+	peakAge          = 29
+	agingDiscount    = 0.88
+	developmentAge   = 28
+	potentialWeight  = 0.5
+	valueGranularity = 10000
+	wageShare        = 0.01
+	veteranPremium   = 1.1
+	wageGranularity  = 100
+	maxContractYears = 5
+	signingFeeShare  = 0.1
+)
+
+type weights struct {
+	pace, shooting, passing, defending, goalkeeping, stamina float64
+}
+
+var profiles = map[domain.PlayerPosition]weights{
+	domain.GoalKeeper: {pace: 0.05, passing: 0.1, defending: 0.1, goalkeeping: 0.7, stamina: 0.05},
+	domain.Defender:   {pace: 0.2, shooting: 0.05, passing: 0.15, defending: 0.45, stamina: 0.15},
+	domain.Midfielder: {pace: 0.1, shooting: 0.15, passing: 0.4, defending: 0.15, stamina: 0.2},
+	domain.Forward:    {pace: 0.3, shooting: 0.45, passing: 0.15, stamina: 0.1},
+}
+
+func Overall(position domain.PlayerPosition, a domain.PlayerAttributes) int {
+	w := profiles[position]
+	overall := w.pace*float64(a.Pace) + w.shooting*float64(a.Shooting) + w.passing*float64(a.Passing) +
+		w.defending*float64(a.Defending) + w.goalkeeping*float64(a.Goalkeeping) + w.stamina*float64(a.Stamina)
+
+	return int(math.Round(overall))
+}
+
+func MarketValue(position domain.PlayerPosition, age uint8, a domain.PlayerAttributes, reference int) int {
+	overall := float64(Overall(position, a))
+	youth := math.Max(0, math.Min(1, float64(developmentAge-int(age))/10))
+	effective := overall + math.Max(0, float64(a.Potential)-overall)*youth*potentialWeight
+	value := float64(reference) * math.Exp((effective-averageOverall)/valueElasticity)
+
+	if int(age) > peakAge {
+		value *= math.Pow(agingDiscount, float64(int(age)-peakAge))
+	}
+
+	if reference <= 0 {
+		return 0
+	}
+
+	return int(math.Max(1, math.Round(value/valueGranularity))) * valueGranularity
+}
+
+func WageDemand(marketValue int, age uint8) int {
+	wage := float64(marketValue) * wageShare
+
+	if int(age) > peakAge {
+		wage *= veteranPremium
+	}
+
+	return int(math.Max(1, math.Round(wage/wageGranularity))) * wageGranularity
+}
+
+func MaxContractYears(age uint8) int {
+	return int(math.Max(1, math.Min(maxContractYears, float64(35-int(age)))))
+}
+
+func SigningFee(marketValue int) int {
+	return int(math.Round(float64(marketValue)*signingFeeShare/valueGranularity)) * valueGranularity
+}
+
+func GenerateAttributes(position domain.PlayerPosition, age uint8, rng *rand.Rand) domain.PlayerAttributes {
+	quality := averageOverall + rng.NormFloat64()*overallSpread
+
+	if int(age) < 24 {
+		quality -= float64(24-int(age)) * 1.5
+	}
+
+	w := profiles[position]
+	attribute := func(weight float64) int {
+		offset := -25.0
+
+		if weight >= 0.3 {
+			offset = 0
+		} else if weight >= 0.1 {
+			offset = -5
+		}
+
+		return clamp(quality + offset + rng.NormFloat64()*5)
+	}
+
+	a := domain.PlayerAttributes{
+		Pace:        attribute(w.pace),
+		Shooting:    attribute(w.shooting),
+		Passing:     attribute(w.passing),
+		Defending:   attribute(w.defending),
+		Goalkeeping: attribute(w.goalkeeping),
+		Stamina:     attribute(w.stamina),
+	}
+
+	if position != domain.GoalKeeper {
+		a.Goalkeeping = clamp(float64(a.Goalkeeping) - 20)
+	}
+
+	if int(age) > 31 {
+		decline := float64(int(age)-31) * 2
+		a.Pace = clamp(float64(a.Pace) - decline)
+		a.Stamina = clamp(float64(a.Stamina) - decline)
+	}
+
+	overall := Overall(position, a)
+	a.Potential = overall
+
+	if int(age) < developmentAge {
+		growth := float64(developmentAge-int(age))*2 + rng.NormFloat64()*4
+		a.Potential = clamp(math.Max(float64(overall), float64(overall)+growth))
+	}
+
+	return a
+}
+
+func clamp(value float64) int {
+	return int(math.Max(minAttribute, math.Min(maxAttribute, math.Round(value))))
+}
@@ -0,0 +1,122 @@
+package valuation
+
+import (
+	"github.com/giancarlobastos/soccer-manager-api/domain"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var positions = []domain.PlayerPosition{domain.GoalKeeper, domain.Defender, domain.Midfielder, domain.Forward}
+
+func TestOverallWeighsPositionRelevantAttributes(t *testing.T) {
+	striker := domain.PlayerAttributes{Pace: 80, Shooting: 85, Passing: 60, Defending: 30, Goalkeeping: 10, Stamina: 60}
+
+	if Overall(domain.Forward, striker) <= Overall(domain.Defender, striker) {
+		t.Fatal("expected a striker profile to rate higher as a forward than as a defender")
+	}
+
+	if Overall(domain.GoalKeeper, striker) >= Overall(domain.Midfielder, striker) {
+		t.Fatal("expected an outfield profile to rate poorly as a goalkeeper")
+	}
+}
+
+func TestMarketValue(t *testing.T) {
+	average := domain.PlayerAttributes{Pace: 60, Shooting: 60, Passing: 60, Defending: 60, Goalkeeping: 60, Stamina: 60, Potential: 60}
+	better := average
+	better.Shooting, better.Pace = 75, 75
+
+	if value := MarketValue(domain.Forward, 29, average, 1000000); value != 1000000 {
+		t.Fatalf("expected an average player at peak age to be worth the reference value, got %d", value)
+	}
+
+	if MarketValue(domain.Forward, 29, better, 1000000) <= MarketValue(domain.Forward, 29, average, 1000000) {
+		t.Fatal("expected better attributes to increase the value")
+	}
+
+	if MarketValue(domain.Forward, 34, average, 1000000) >= MarketValue(domain.Forward, 29, average, 1000000) {
+		t.Fatal("expected older players to be worth less")
+	}
+
+	prospect := average
+	prospect.Potential = 85
+
+	if MarketValue(domain.Forward, 19, prospect, 1000000) <= MarketValue(domain.Forward, 19, average, 1000000) {
+		t.Fatal("expected potential to increase the value of young players")
+	}
+
+	if MarketValue(domain.Forward, 33, prospect, 1000000) != MarketValue(domain.Forward, 33, average, 1000000) {
+		t.Fatal("expected potential not to matter for veterans")
+	}
+
+	if value := MarketValue(domain.Forward, 18, domain.PlayerAttributes{}, 1000000); value <= 0 || value%valueGranularity != 0 {
+		t.Fatalf("expected a positive rounded value, got %d", value)
+	}
+}
+
+func TestWageDemand(t *testing.T) {
+	if wage := WageDemand(1000000, 25); wage != 10000 {
+		t.Fatalf("expected a wage of one percent of the market value, got %d", wage)
+	}
+
+	if WageDemand(1000000, 33) <= WageDemand(1000000, 25) {
+		t.Fatal("expected veterans to ask for a premium")
+	}
+
+	if wage := WageDemand(0, 20); wage != wageGranularity {
+		t.Fatalf("expected a minimum wage, got %d", wage)
+	}
+
+	if MaxContractYears(20) != maxContractYears || MaxContractYears(33) != 2 || MaxContractYears(40) != 1 {
+		t.Fatal("expected older players to accept shorter contracts only")
+	}
+}
+
+func TestSigningFee(t *testing.T) {
+	if fee := SigningFee(1230000); fee != 120000 {
+		t.Fatalf("expected a tenth of the market value rounded to the value granularity, got %d", fee)
+	}
+
+	if fee := SigningFee(0); fee != 0 {
+		t.Fatalf("expected worthless players to sign for free, got %d", fee)
+	}
+}
+
+func TestGenerateAttributes(t *testing.T) {
+	if !reflect.DeepEqual(GenerateAttributes(domain.Forward, 20, rand.New(rand.NewSource(5))),
+		GenerateAttributes(domain.Forward, 20, rand.New(rand.NewSource(5)))) {
+		t.Fatal("expected the same seed to generate the same attributes")
+	}
+
+	rng := rand.New(rand.NewSource(1))
+
+	for _, position := range positions {
+		total, goalkeeping, samples := 0, 0, 500
+
+		for i := 0; i < samples; i++ {
+			age := uint8(rng.Intn(22) + 18)
+			a := GenerateAttributes(position, age, rng)
+
+			for _, value := range []int{a.Pace, a.Shooting, a.Passing, a.Defending, a.Goalkeeping, a.Stamina, a.Potential} {
+				if value < minAttribute || value > maxAttribute {
+					t.Fatalf("attribute out of range: %+v", a)
+				}
+			}
+
+			if a.Potential < Overall(position, a) {
+				t.Fatalf("expected potential to be at least the overall rating: %+v", a)
+			}
+
+			total += Overall(position, a)
+			goalkeeping += a.Goalkeeping
+		}
+
+		if mean := total / samples; mean < 45 || mean > 65 {
+			t.Fatalf("expected %s overall ratings around the average, got %d", position, mean)
+		}
+
+		if mean := goalkeeping / samples; (position == domain.GoalKeeper) != (mean > 40) {
+			t.Fatalf("unexpected mean goalkeeping %d for %s", mean, position)
+		}
+	}
+}